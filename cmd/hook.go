@@ -10,11 +10,14 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
@@ -83,6 +86,9 @@ func runHookPreReceive(c *cli.Context) error {
 	userIDStr := os.Getenv(models.EnvPusherID)
 	repoPath := models.RepoPath(username, reponame)
 
+	// new commits of the updated refs, which are empty for deleted refs
+	updates := make(map[string]string)
+
 	buf := bytes.NewBuffer(nil)
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
@@ -102,6 +108,24 @@ func runHookPreReceive(c *cli.Context) error {
 		oldCommitID := string(fields[0])
 		newCommitID := string(fields[1])
 		refFullName := string(fields[2])
+		if newCommitID == git.EmptySHA {
+			updates[refFullName] = ""
+		} else {
+			updates[refFullName] = newCommitID
+		}
+
+		// only whitelisted users can create, update or delete protected tags
+		if strings.HasPrefix(refFullName, git.TagPrefix) {
+			tagName := strings.TrimPrefix(refFullName, git.TagPrefix)
+			userID, _ := strconv.ParseInt(userIDStr, 10, 64)
+			canPush, err := private.CanUserPushTag(repoID, tagName, userID)
+			if err != nil {
+				fail("Internal error", "Fail to detect user can push tag: %v", err)
+			} else if !canPush {
+				fail(fmt.Sprintf("tag %s is protected", tagName), "")
+			}
+			continue
+		}
 
 		branchName := strings.TrimPrefix(refFullName, git.BranchPrefix)
 		protectBranch, err := private.GetProtectedBranchBy(repoID, branchName)
@@ -132,12 +156,141 @@ func runHookPreReceive(c *cli.Context) error {
 			} else if !canPush {
 				fail(fmt.Sprintf("protected branch %s can not be pushed to", branchName), "")
 			}
+
+			// check changes to protected files, creating the branch changes
+			// all of them
+			if len(protectBranch.GetProtectedFilePatterns()) > 0 {
+				files, err := models.GetChangedFilesOfPush(repoPath, oldCommitID, newCommitID)
+				if err != nil {
+					fail("Internal error", "Fail to detect changed files: %v", err)
+				}
+				if protectedFiles := protectBranch.MatchProtectedFiles(files); len(protectedFiles) > 0 {
+					canPush, err := private.CanUserPushProtectedFiles(protectBranch.ID, userID)
+					if err != nil {
+						fail("Internal error", "Fail to detect user can push protected files: %v", err)
+					} else if !canPush {
+						fail(fmt.Sprintf("protected file %s of branch %s can not be changed", protectedFiles[0], branchName), "")
+					}
+				}
+			}
 		}
 	}
 
+	if !isWiki {
+		checkRepoSizeLimit(repoID, repoPath, updates)
+	}
+
 	return nil
 }
 
+// checkRepoSizeLimit fails if the push makes the repository exceed its size
+// limit. A push which does not grow the git objects reachable from the refs of
+// the repository, like a force push dropping commits, is always allowed so that
+// the size of the repository can be reduced.
+func checkRepoSizeLimit(repoID int64, repoPath string, updates map[string]string) {
+	pushSize, err := getPushSize()
+	if err != nil {
+		fail("Internal error", "Fail to get push size: %v", err)
+	}
+	err = private.CheckRepoSizeLimit(repoID, pushSize)
+	if err == nil {
+		return
+	} else if !models.IsErrRepoSizeLimitExceeded(err) {
+		fail("Internal error", "Fail to check repository size limit: %v", err)
+	}
+	sizeErr := err.(models.ErrRepoSizeLimitExceeded)
+
+	// the objects received by the push are readable until it is accepted
+	size, err := getReachableSize(repoPath, "--glob=*")
+	if err != nil {
+		fail("Internal error", "Fail to get repository size: %v", err)
+	}
+	args := make([]string, 0, 2*len(updates)+1)
+	for refFullName := range updates {
+		args = append(args, "--exclude="+refFullName)
+	}
+	args = append(args, "--glob=*")
+	for _, newCommitID := range updates {
+		if len(newCommitID) > 0 {
+			args = append(args, newCommitID)
+		}
+	}
+	newSize, err := getReachableSize(repoPath, args...)
+	if err != nil {
+		fail("Internal error", "Fail to get repository size after push: %v", err)
+	}
+	if newSize <= size {
+		return
+	}
+
+	fail(fmt.Sprintf("push of %s exceeds the repository size limit of %s, the repository already uses %s",
+		base.FileSize(sizeErr.PushSize), base.FileSize(sizeErr.Limit), base.FileSize(sizeErr.Size)), "")
+}
+
+// getReachableSize returns the size on disk of the git objects listed by
+// git rev-list --objects with the given revisions.
+func getReachableSize(repoPath string, revs ...string) (int64, error) {
+	revList := exec.Command("git", append([]string{"rev-list", "--objects"}, revs...)...)
+	revList.Dir = repoPath
+	revList.Stderr = os.Stderr
+	objects, err := revList.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+
+	// objects are listed with their path, which is ignored
+	catFile := exec.Command("git", "cat-file", "--batch-check=%(objectsize:disk) %(rest)")
+	catFile.Dir = repoPath
+	catFile.Stdin = objects
+	catFile.Stderr = os.Stderr
+	sizes := new(bytes.Buffer)
+	catFile.Stdout = sizes
+
+	if err = revList.Start(); err != nil {
+		return 0, fmt.Errorf("rev-list: %v", err)
+	}
+	catFileErr := catFile.Run()
+	if err = revList.Wait(); err != nil {
+		return 0, fmt.Errorf("rev-list: %v", err)
+	} else if catFileErr != nil {
+		return 0, fmt.Errorf("cat-file: %v", catFileErr)
+	}
+
+	var size int64
+	for _, line := range strings.Split(sizes.String(), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		objectSize, err := strconv.ParseInt(strings.SplitN(line, " ", 2)[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected cat-file output: %q", line)
+		}
+		size += objectSize
+	}
+	return size, nil
+}
+
+// getPushSize returns the size of the objects received by the push, which git
+// keeps in a quarantine directory until the pre-receive hook accepts them.
+func getPushSize() (int64, error) {
+	quarantinePath := os.Getenv("GIT_QUARANTINE_PATH")
+	if len(quarantinePath) == 0 {
+		return 0, nil
+	}
+
+	var size int64
+	err := filepath.Walk(quarantinePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
 func runHookUpdate(c *cli.Context) error {
 	if len(os.Getenv("SSH_ORIGINAL_COMMAND")) == 0 {
 		return nil
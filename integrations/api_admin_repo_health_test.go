@@ -0,0 +1,51 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/routers/api/v1/admin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminRepoHealthCheck(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	var check admin.RepoHealthCheck
+	req := NewRequestf(t, "GET", "/api/v1/admin/repos/user2/repo1/fsck?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &check)
+	assert.Equal(t, "none", check.Status)
+	assert.Nil(t, check.Checked)
+
+	req = NewRequestf(t, "POST", "/api/v1/admin/repos/user2/repo1/fsck?gc=true&token=%s", token)
+	session.MakeRequest(t, req, http.StatusAccepted)
+
+	// the check is run in the background
+	for i := 0; i < 50 && check.Status == "none"; i++ {
+		time.Sleep(100 * time.Millisecond)
+		req = NewRequestf(t, "GET", "/api/v1/admin/repos/user2/repo1/fsck?token=%s", token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &check)
+	}
+	assert.Equal(t, "healthy", check.Status)
+	assert.Empty(t, check.Errors)
+	assert.NotNil(t, check.Checked)
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/repos/user2/unknown/fsck?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// only site administrators can check repositories
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/admin/repos/user2/repo1/fsck?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
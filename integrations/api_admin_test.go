@@ -106,3 +106,36 @@ func TestAPISudoUserForbidden(t *testing.T) {
 	req := NewRequest(t, "GET", urlStr)
 	session.MakeRequest(t, req, http.StatusForbidden)
 }
+
+func TestAPIAdminGetAllUsers(t *testing.T) {
+	prepareTestEnv(t)
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/admin/users?admin=false&active=true&page=2&limit=3&token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var users []*api.User
+	DecodeJSON(t, resp, &users)
+	if assert.Len(t, users, 3) {
+		assert.EqualValues(t, 8, users[0].ID)
+		assert.EqualValues(t, 10, users[1].ID)
+		assert.EqualValues(t, 11, users[2].ID)
+	}
+	assert.Equal(t, "14", resp.Header().Get("X-Total-Count"))
+	assert.Contains(t, resp.Header().Get("Link"), `rel="next"`)
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/users?q=user2@example.com&token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &users)
+	if assert.Len(t, users, 1) {
+		assert.EqualValues(t, "user2", users[0].UserName)
+	}
+
+	// only site administrators can list users
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/users?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/api/v1/repo"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -43,3 +45,61 @@ func TestAPIGetBranch(t *testing.T) {
 		testAPIGetBranch(t, test.BranchName, test.Exists)
 	}
 }
+
+func TestAPIListMergedBranches(t *testing.T) {
+	prepareTestEnv(t)
+	repo16 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 16}).(*models.Repository)
+	assert.NoError(t, models.UpdateProtectBranch(repo16, &models.ProtectedBranch{
+		RepoID:     repo16.ID,
+		BranchName: "master",
+	}, models.WhitelistOptions{}))
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	listMerged := func(into string, expectedStatus int) []*repo.MergedBranch {
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo16/branches/merged?into=%s&token=%s", into, token)
+		resp := session.MakeRequest(t, req, expectedStatus)
+		var branches []*repo.MergedBranch
+		if expectedStatus == http.StatusOK {
+			DecodeJSON(t, resp, &branches)
+		}
+		return branches
+	}
+
+	// good-sign-not-yet-validated points to an ancestor of master, good-sign
+	// has a commit which is not in master
+	assert.Equal(t, []*repo.MergedBranch{
+		{Name: "good-sign-not-yet-validated"},
+		{Name: "not-signed"},
+	}, listMerged("", http.StatusOK))
+
+	assert.Equal(t, []*repo.MergedBranch{
+		{Name: "good-sign-not-yet-validated"},
+		{Name: "master", IsDefault: true, IsProtected: true},
+	}, listMerged("not-signed", http.StatusOK))
+
+	assert.Empty(t, listMerged("good-sign-not-yet-validated", http.StatusOK))
+
+	listMerged("unknown", http.StatusNotFound)
+}
+
+func TestAPIListMergedBranchesBranchNamedMerged(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	testCreateBranch(t, session, "user2", "repo1", "branch/master", "merged", http.StatusFound)
+
+	// the path of a branch named merged is the one of the merged branches
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/branches/merged?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var branches []*repo.MergedBranch
+	DecodeJSON(t, resp, &branches)
+	assert.Contains(t, branches, &repo.MergedBranch{Name: "merged"})
+
+	// its reference can be retrieved instead
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/git/refs/heads/merged?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var ref api.Reference
+	DecodeJSON(t, resp, &ref)
+	assert.Equal(t, "refs/heads/merged", ref.Ref)
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	repo_api "code.gitea.io/gitea/routers/api/v1/repo"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -63,3 +65,41 @@ func TestAPIReplaceIssueLabels(t *testing.T) {
 	models.AssertCount(t, &models.IssueLabel{IssueID: issue.ID}, 1)
 	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: label.ID})
 }
+
+func TestAPIBulkUpdateIssueLabels(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}).(*models.Issue)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/labels/bulk?token=%s",
+		owner.Name, repo.Name, token)
+	req := NewRequestWithJSON(t, "POST", urlStr, &auth.BulkIssueLabelsForm{
+		Issues: []int64{issue.Index, 9999},
+		Add:    []int64{2},
+		Remove: []int64{1},
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var results []*repo_api.BulkIssueLabelsResult
+	DecodeJSON(t, resp, &results)
+	if assert.Len(t, results, 2) {
+		assert.True(t, results[0].Success)
+		if assert.Len(t, results[0].Labels, 1) {
+			assert.EqualValues(t, 2, results[0].Labels[0].ID)
+		}
+		assert.False(t, results[1].Success)
+		assert.NotEmpty(t, results[1].Error)
+	}
+
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: 2})
+	models.AssertNotExistsBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: 1})
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &auth.BulkIssueLabelsForm{
+		Issues: []int64{issue.Index},
+		Add:    []int64{9999},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
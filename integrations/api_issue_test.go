@@ -7,6 +7,7 @@ package integrations
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -34,6 +35,33 @@ func TestAPIListIssues(t *testing.T) {
 	}
 }
 
+func TestAPIListIssuesByInvolvement(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	test := func(query string, expectedIssueIDs ...int64) {
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/issues?state=all&q=%s&token=%s", url.QueryEscape(query), token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var apiIssues []*api.Issue
+		DecodeJSON(t, resp, &apiIssues)
+		issueIDs := make([]int64, 0, len(apiIssues))
+		for _, apiIssue := range apiIssues {
+			issueIDs = append(issueIDs, apiIssue.ID)
+		}
+		assert.ElementsMatch(t, expectedIssueIDs, issueIDs)
+	}
+	test("author:me", 5)
+	test("assignee:user1", 1)
+	test("author:user1 assignee:user1", 1, 2, 3)
+	test("involves:me", 3, 5)
+	test("involves:nobody")
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/issues?q=%s&token=%s", url.QueryEscape("author:user1 assignee:user2"), token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func TestAPICreateIssue(t *testing.T) {
 	prepareTestEnv(t)
 	const body, title = "apiTestBody", "apiTestTitle"
@@ -0,0 +1,87 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullRequestDivergence(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	// a pull request made from a fork
+	testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+	testEditFileToNewBranch(t, session, "user1", "repo1", "master", "feature/test", "README.md", "Hello, World (Edited)\n")
+	resp := testPullCreate(t, session, "user1", "repo1", "feature/test", "This is a pull title")
+	elem := strings.Split(test.RedirectURL(resp), "/")
+	assert.EqualValues(t, "pulls", elem[3])
+
+	headRepo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 1, Name: "repo1"}).(*models.Repository)
+	forkRemotes, err := git.NewCommand("remote").RunInDir(headRepo.RepoPath())
+	assert.NoError(t, err)
+
+	getDivergence := func() *models.PullRequestDivergence {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/%s/divergence?token=%s", elem[1], elem[2], elem[4], token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var divergence *models.PullRequestDivergence
+		DecodeJSON(t, resp, &divergence)
+		return divergence
+	}
+
+	// the head branch is ahead of the base branch
+	divergence := getDivergence()
+	assert.Equal(t, models.PullRequestDivergenceUpToDate, divergence.State)
+	assert.Equal(t, 1, divergence.Ahead)
+	assert.Equal(t, 0, divergence.Behind)
+	assert.False(t, divergence.CanFastForward)
+	assert.NotEmpty(t, divergence.HeadCommitID)
+	assert.Equal(t, divergence.BaseCommitID, divergence.MergeBaseCommitID)
+
+	// merging adds a merge commit the head branch is behind of
+	testPullMerge(t, session, elem[1], elem[2], elem[4], models.MergeStyleMerge)
+	divergence = getDivergence()
+	assert.Equal(t, models.PullRequestDivergenceBehind, divergence.State)
+	assert.Equal(t, 0, divergence.Ahead)
+	assert.Equal(t, 1, divergence.Behind)
+	assert.True(t, divergence.CanFastForward)
+	assert.Equal(t, divergence.HeadCommitID, divergence.MergeBaseCommitID)
+
+	testEditFile(t, session, "user1", "repo1", "feature/test", "README.md", "Hello, World (Edited again)\n")
+	divergence = getDivergence()
+	assert.Equal(t, models.PullRequestDivergenceDiverged, divergence.State)
+	assert.Equal(t, 1, divergence.Ahead)
+	assert.Equal(t, 1, divergence.Behind)
+	assert.False(t, divergence.CanFastForward)
+
+	// the divergence is computed without adding remotes to the fork
+	remotes, err := git.NewCommand("remote").RunInDir(headRepo.RepoPath())
+	assert.NoError(t, err)
+	assert.Equal(t, forkRemotes, remotes)
+
+	// deleting the head branch does not break the pull request
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/user1/repo1/branches"), http.StatusOK)
+	req := NewRequestWithValues(t, "POST", "/user1/repo1/branches/delete?name="+url.QueryEscape("feature/test"), map[string]string{
+		"_csrf": getCsrf(t, NewHTMLParser(t, resp.Body).doc),
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	divergence = getDivergence()
+	assert.Equal(t, models.PullRequestDivergenceHeadDeleted, divergence.State)
+	assert.Empty(t, divergence.HeadCommitID)
+	assert.NotEmpty(t, divergence.BaseCommitID)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/999/divergence?token=%s", elem[1], elem[2], token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/setting"
+	repo_api "code.gitea.io/gitea/routers/api/v1/repo"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -56,3 +57,45 @@ func TestAPIMergePullWIP(t *testing.T) {
 
 	session.MakeRequest(t, req, http.StatusMethodNotAllowed)
 }
+
+func TestAPICreateDraftPull(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	testEditFileToNewBranch(t, session, "user2", "repo1", "master", "draft", "README.md", "Draft\n")
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+token, &repo_api.CreateDraftablePullRequestOption{
+		CreatePullRequestOption: api.CreatePullRequestOption{
+			Head:  "draft",
+			Base:  "master",
+			Title: "Draft pull request",
+		},
+		Draft: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var pr repo_api.DraftablePullRequest
+	DecodeJSON(t, resp, &pr)
+	assert.True(t, pr.IsDraft)
+	assert.False(t, pr.Mergeable)
+	models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID, IsDraft: true})
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/user2/repo1/pulls/%d/ready?token=%s", pr.Index, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/pulls/%d?token=%s", pr.Index, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &pr)
+	assert.False(t, pr.IsDraft)
+
+	// a closed pull request cannot be marked as ready
+	closed := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID}).(*models.PullRequest)
+	closed.IsDraft = true
+	assert.NoError(t, closed.UpdateCols("is_draft"))
+	state := "closed"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d?token=%s", pr.Index, token), &api.EditPullRequestOption{
+		State: &state,
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestf(t, "POST", "/api/v1/repos/user2/repo1/pulls/%d/ready?token=%s", pr.Index, token)
+	session.MakeRequest(t, req, http.StatusConflict)
+	models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID, IsDraft: true})
+}
@@ -5,8 +5,12 @@
 package integrations
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
 	"testing"
 
 	"code.gitea.io/git"
@@ -83,3 +87,77 @@ func TestAPICreateRelease(t *testing.T) {
 		Note:    newRelease.Note,
 	})
 }
+
+func newMultipartRequest(t *testing.T, method, urlStr string, values map[string]string, files map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range values {
+		assert.NoError(t, writer.WriteField(key, value))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile("attachment", name)
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	req := NewRequestWithBody(t, method, urlStr, body)
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAPIReleaseAttachments(t *testing.T) {
+	prepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+
+	// zip archives are allowed attachments by default
+	const zipContent = "PK\x03\x04 content"
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/releases?token=%s", owner.Name, repo.Name, token)
+	req := newMultipartRequest(t, "POST", urlStr, map[string]string{
+		"tag_name": "v0.0.2",
+		"target":   "master",
+		"title":    "v0.0.2",
+	}, map[string]string{
+		"first.zip":  zipContent,
+		"second.zip": zipContent,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var release api.Release
+	DecodeJSON(t, resp, &release)
+	assert.Len(t, release.Attachments, 2)
+
+	attachmentsURL := fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d/assets", owner.Name, repo.Name, release.ID)
+	req = newMultipartRequest(t, "POST", attachmentsURL+"?token="+token, nil, map[string]string{"first.zip": zipContent})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	attach := models.AssertExistsAndLoadBean(t, &models.Attachment{ReleaseID: release.ID, Name: "first.zip"}).(*models.Attachment)
+	attachURL := fmt.Sprintf("%s/%d?token=%s", attachmentsURL, attach.ID, token)
+
+	// rename
+	req = NewRequestWithJSON(t, "PATCH", attachURL, &api.EditAttachmentOptions{Name: "second.zip"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "PATCH", attachURL, &api.EditAttachmentOptions{Name: "renamed.zip"})
+	session.MakeRequest(t, req, http.StatusCreated)
+	models.AssertExistsAndLoadBean(t, &models.Attachment{ID: attach.ID, Name: "renamed.zip"})
+
+	// replace the content
+	req = newMultipartRequest(t, "PATCH", attachURL, nil, map[string]string{"new.zip": zipContent + " replaced"})
+	session.MakeRequest(t, req, http.StatusCreated)
+	data, err := ioutil.ReadFile(attach.LocalPath())
+	assert.NoError(t, err)
+	assert.Equal(t, zipContent+" replaced", string(data))
+	models.AssertExistsAndLoadBean(t, &models.Attachment{ID: attach.ID, Name: "renamed.zip", Size: int64(len(data))})
+
+	// deleting the release removes the attachment files
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/%s/releases/%d?token=%s", owner.Name, repo.Name, release.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Attachment{ReleaseID: release.ID})
+	_, err = os.Stat(attach.LocalPath())
+	assert.True(t, os.IsNotExist(err))
+}
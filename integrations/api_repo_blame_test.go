@@ -0,0 +1,67 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/api/v1/repo"
+
+	"code.gitea.io/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoBlame(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo1.RepoPath())
+	assert.NoError(t, err)
+	initialCommitID, err := gitRepo.GetBranchCommitID("master")
+	assert.NoError(t, err)
+
+	// change the last line and add another one in a second commit
+	testEditFile(t, session, "user2", "repo1", "master", "README.md", "# repo1\n\nDescription of repo1\nSecond line\n")
+	editCommitID, err := gitRepo.GetBranchCommitID("master")
+	assert.NoError(t, err)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/blame/master/README.md?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var blame repo.FileBlame
+	DecodeJSON(t, resp, &blame)
+	assert.Equal(t, "README.md", blame.Path)
+	assert.Equal(t, editCommitID, blame.CommitID)
+	if assert.Len(t, blame.Ranges, 2) {
+		assert.Equal(t, initialCommitID, blame.Ranges[0].SHA)
+		assert.Equal(t, 1, blame.Ranges[0].StartLine)
+		assert.Equal(t, 2, blame.Ranges[0].EndLine)
+		assert.Equal(t, editCommitID, blame.Ranges[1].SHA)
+		assert.Equal(t, 3, blame.Ranges[1].StartLine)
+		assert.Equal(t, 4, blame.Ranges[1].EndLine)
+		assert.Equal(t, "user2@example.com", blame.Ranges[1].Author.Email)
+		assert.False(t, blame.Ranges[1].Committer.Date.IsZero())
+	}
+
+	// the blame at the initial commit is the one of the initial file
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/blame/%s/README.md?token=%s", initialCommitID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &blame)
+	assert.Equal(t, initialCommitID, blame.CommitID)
+	if assert.Len(t, blame.Ranges, 1) {
+		assert.Equal(t, initialCommitID, blame.Ranges[0].SHA)
+		assert.Equal(t, 3, blame.Ranges[0].EndLine)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/blame/master/unknown.md?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	testEditFile(t, session, "user2", "repo1", "master", "README.md", "\x00\x01\x02binary")
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/blame/master/README.md?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
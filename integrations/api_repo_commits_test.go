@@ -0,0 +1,57 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoListCommits(t *testing.T) {
+	prepareTestEnv(t)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// the history of master of user2/repo16 has three commits
+	expected := []string{
+		"69554a64c1e6030f051e5c3f94bfbd773cd6a324",
+		"27566bd5738fc8b4e3fef3c5e72cce608537bd95",
+		"5099b81332712fe655e34e8dd63574f503f61811",
+	}
+	var paged []string
+	for page := 1; page <= 2; page++ {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?page=%d&limit=2&token=%s", user.Name, page, token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+		var commits []*api.PayloadCommit
+		DecodeJSON(t, resp, &commits)
+		for _, commit := range commits {
+			paged = append(paged, commit.ID)
+		}
+	}
+	assert.Equal(t, expected, paged)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?page=3&limit=2&token=%s", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var commits []*api.PayloadCommit
+	DecodeJSON(t, resp, &commits)
+	assert.Len(t, commits, 0)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?sha=%s&token=%s", user.Name, expected[1], token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &commits)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, expected[1], commits[0].ID)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?sha=unknown&token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCompareCommits(t *testing.T) {
+	prepareTestEnv(t)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/compare/5099b81332712fe655e34e8dd63574f503f61811...master?patch=true&token=%s", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var comparison models.Comparison
+	DecodeJSON(t, resp, &comparison)
+	assert.Equal(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", comparison.HeadCommitID)
+	assert.Len(t, comparison.Commits, 2)
+	if assert.Len(t, comparison.Files, 1) {
+		assert.Equal(t, "readme.md", comparison.Files[0].Filename)
+	}
+	assert.NotEmpty(t, comparison.Patch)
+
+	// branches with unrelated histories
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/compare/master...good-sign?token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// abbreviated commit SHAs and other revisions
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/compare/5099b81...master~1?token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &comparison)
+	assert.Equal(t, "5099b81332712fe655e34e8dd63574f503f61811", comparison.BaseCommitID)
+	assert.Len(t, comparison.Commits, 1)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/compare/master...unknown?token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/compare/master?token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/api/v1/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoContributorStats(t *testing.T) {
+	prepareTestEnv(t)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	// user2/repo16 has two commits of user2 and one of user21 in between
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors?token=%s", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var stats repo.RepoContributorStats
+	DecodeJSON(t, resp, &stats)
+	assert.False(t, stats.Pending)
+	assert.Equal(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", stats.CommitID)
+	assert.Nil(t, stats.From)
+	assert.Nil(t, stats.To)
+	if assert.Len(t, stats.Contributors, 2) {
+		assert.Equal(t, "user2@example.com", stats.Contributors[0].Email)
+		assert.Equal(t, 2, stats.Contributors[0].Commits)
+		assert.Equal(t, 2, stats.Contributors[0].Additions)
+		assert.Equal(t, 1, stats.Contributors[0].Deletions)
+		if assert.NotNil(t, stats.Contributors[0].User) {
+			assert.EqualValues(t, 2, stats.Contributors[0].User.ID)
+		}
+		assert.Equal(t, "user21@example.com", stats.Contributors[1].Email)
+		assert.Equal(t, 1, stats.Contributors[1].Commits)
+		assert.Equal(t, 1, stats.Contributors[1].Additions)
+		assert.Equal(t, 1, stats.Contributors[1].Deletions)
+	}
+
+	// the commits are summed up by day, the window overlaps the day of all of them
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors?from=2017-08-06T17:56:40Z&to=2017-08-06T17:58:20Z&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	stats = repo.RepoContributorStats{}
+	DecodeJSON(t, resp, &stats)
+	assert.NotNil(t, stats.From)
+	assert.NotNil(t, stats.To)
+	if assert.Len(t, stats.Contributors, 2) {
+		assert.Equal(t, 2, stats.Contributors[0].Commits)
+		assert.Equal(t, "user21@example.com", stats.Contributors[1].Email)
+		assert.EqualValues(t, 21, stats.Contributors[1].User.ID)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors?to=2017-08-06T00:00:00Z&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	stats = repo.RepoContributorStats{}
+	DecodeJSON(t, resp, &stats)
+	assert.Empty(t, stats.Contributors)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors?from=2017-08-07T00:00:00Z&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	stats = repo.RepoContributorStats{}
+	DecodeJSON(t, resp, &stats)
+	assert.NotNil(t, stats.Contributors)
+	assert.Empty(t, stats.Contributors)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors?from=yesterday&token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the repository is private
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/stats/contributors", user.Name)
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -313,3 +313,21 @@ func TestAPIOrgRepoCreate(t *testing.T) {
 		session.MakeRequest(t, req, testCase.expectedStatus)
 	}
 }
+
+func TestAPIRetryIssueMigration(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "POST", "/api/v1/repos/user2/repo1/issue_migration/retry?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// only a failed migration is retried
+	assert.NoError(t, models.NewIssueMigration(&models.IssueMigration{RepoID: 1, DoerID: 2, Status: models.IssueMigrationRunning}))
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	// the repository administrators retry the migration
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/repos/user2/repo1/issue_migration/retry?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
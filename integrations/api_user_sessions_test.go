@@ -0,0 +1,141 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/routers/api/v1/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserSessions(t *testing.T) {
+	prepareTestEnv(t)
+
+	// sessions of their own so the cached sessions of other tests are kept
+	first := loginUserWithPassword(t, "user10", userPassword)
+	second := loginUserWithPassword(t, "user10", userPassword)
+	second.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+	token := getTokenForLoggedInUser(t, first)
+
+	csrf := GetCSRF(t, first, "/user/settings")
+	req := NewRequest(t, "GET", "/api/v1/user/sessions")
+	req.Header.Add("X-Csrf-Token", csrf)
+	resp := first.MakeRequest(t, req, http.StatusOK)
+	var sessions []*user.Session
+	DecodeJSON(t, resp, &sessions)
+	if !assert.Len(t, sessions, 2) {
+		return
+	}
+	current, other := sessions[0], sessions[1]
+	if !current.Current {
+		current, other = other, current
+	}
+	assert.True(t, current.Current)
+	assert.False(t, other.Current)
+
+	// revoking another session does not affect the caller
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/sessions/%d?token=%s", other.ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	second.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+	first.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/sessions/%d?token=%s", other.ID, token))
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// revoking the current session signs it out
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/sessions/%d", current.ID))
+	req.Header.Add("X-Csrf-Token", csrf)
+	first.MakeRequest(t, req, http.StatusNoContent)
+	first.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+
+	// revoking all sessions keeps the access tokens
+	first = loginUserWithPassword(t, "user10", userPassword)
+	second = loginUserWithPassword(t, "user10", userPassword)
+	first.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+	second.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+	req = NewRequest(t, "DELETE", "/api/v1/user/sessions?token="+token)
+	MakeRequest(t, req, http.StatusNoContent)
+	first.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+	second.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+
+	req = NewRequest(t, "GET", "/api/v1/user/sessions?token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &sessions)
+	assert.Len(t, sessions, 0)
+}
+
+func TestAPIUserSessionRememberCookie(t *testing.T) {
+	prepareTestEnv(t)
+
+	resp := MakeRequest(t, NewRequest(t, "GET", "/user/login"), http.StatusOK)
+	req := NewRequestWithValues(t, "POST", "/user/login", map[string]string{
+		"_csrf":     NewHTMLParser(t, resp.Body).GetCSRF(),
+		"user_name": "user10",
+		"password":  userPassword,
+		"remember":  "on",
+	})
+	resp = MakeRequest(t, req, http.StatusFound)
+	session := emptyTestSession(t)
+	baseURL, err := url.Parse(setting.AppURL)
+	assert.NoError(t, err)
+	session.jar.SetCookies(baseURL, (&http.Request{Header: http.Header{"Cookie": resp.HeaderMap["Set-Cookie"]}}).Cookies())
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+
+	// the remember me cookies alone sign in a new session
+	rememberCookies := []*http.Cookie{session.GetCookie(setting.CookieUserName), session.GetCookie(setting.CookieRememberName)}
+	remembered := emptyTestSession(t)
+	remembered.jar.SetCookies(baseURL, rememberCookies)
+	remembered.MakeRequest(t, NewRequest(t, "GET", "/user/login"), http.StatusFound)
+
+	token := getTokenForLoggedInUser(t, loginUser(t, "user1"))
+	req = NewRequest(t, "GET", "/api/v1/admin/users/user10/sessions?token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var sessions []*user.Session
+	DecodeJSON(t, resp, &sessions)
+	if !assert.Len(t, sessions, 1) {
+		return
+	}
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/users/user10/sessions/%d?token=%s", sessions[0].ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// but no longer once a session is revoked
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+	revoked := emptyTestSession(t)
+	revoked.jar.SetCookies(baseURL, rememberCookies)
+	revoked.MakeRequest(t, NewRequest(t, "GET", "/user/login"), http.StatusOK)
+	revoked.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+}
+
+func TestAPIAdminUserSessions(t *testing.T) {
+	prepareTestEnv(t)
+
+	userSession := loginUserWithPassword(t, "user10", userPassword)
+	userSession.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusOK)
+	token := getTokenForLoggedInUser(t, loginUser(t, "user1"))
+
+	req := NewRequest(t, "GET", "/api/v1/admin/users/user10/sessions?token="+token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var sessions []*user.Session
+	DecodeJSON(t, resp, &sessions)
+	if !assert.Len(t, sessions, 1) {
+		return
+	}
+	assert.False(t, sessions[0].Current)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/users/user10/sessions/%d?token=%s", sessions[0].ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	userSession.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+
+	req = NewRequest(t, "GET", "/api/v1/admin/users/user10/sessions?token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &sessions)
+	assert.Len(t, sessions, 0)
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAuthSourceOAuth2_InvalidGroupTeamMap(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/admin/auths/new")
+	req := NewRequestWithValues(t, "POST", "/admin/auths/new", map[string]string{
+		"_csrf":                              csrf,
+		"type":                               "6",
+		"name":                               "oidc",
+		"oauth2_provider":                    "openidConnect",
+		"oauth2_key":                         "key",
+		"oauth2_secret":                      "secret",
+		"open_id_connect_auto_discovery_url": "https://example.com/.well-known/openid-configuration",
+		"oauth2_group_claim_name":            "groups",
+		"oauth2_group_team_map":              `{"developers": ["team1"]}`,
+		"is_active":                          "on",
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	// the source is not created and the mapping is shown as invalid
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.True(t, htmlDoc.doc.Find("#oauth2_group_team_map").Parent().HasClass("error"))
+	assert.Contains(t, strings.TrimSpace(htmlDoc.doc.Find(".ui.negative.message").Text()), "invalid")
+	models.AssertNotExistsBean(t, &models.LoginSource{Name: "oidc"})
+}
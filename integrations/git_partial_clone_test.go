@@ -0,0 +1,90 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/Unknwon/com"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPartialClone(t *testing.T) {
+	if !setting.Git.SupportPartialClone {
+		t.Skip("installed Git does not support partial clone")
+	}
+
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		u.Path = "user2/repo1.git"
+
+		for _, filter := range []string{"blob:none", "tree:0"} {
+			t.Run(filter, func(t *testing.T) {
+				dstPath, err := ioutil.TempDir("", "repo1")
+				assert.NoError(t, err)
+				defer os.RemoveAll(dstPath)
+
+				_, err = git.NewCommand("clone", "--no-checkout", "--filter="+filter, u.String(), dstPath).Run()
+				assert.NoError(t, err)
+
+				promisor, err := git.NewCommand("config", "remote.origin.promisor").RunInDir(dstPath)
+				assert.NoError(t, err)
+				assert.Equal(t, "true", strings.TrimSpace(promisor))
+
+				// the filtered objects have not been sent
+				objects, err := git.NewCommand("rev-list", "--objects", "--all", "--missing=print").RunInDir(dstPath)
+				assert.NoError(t, err)
+				assert.Contains(t, objects, "\n?")
+
+				// and are fetched on demand
+				_, err = git.NewCommand("checkout", "master").RunInDir(dstPath)
+				assert.NoError(t, err)
+				assert.True(t, com.IsExist(filepath.Join(dstPath, "README.md")))
+			})
+		}
+
+		t.Run("UnreachableObject", func(t *testing.T) {
+			repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+			commitID, err := git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+				"commit-tree", "-m", "unreachable", "HEAD^{tree}").RunInDir(repo.RepoPath())
+			assert.NoError(t, err)
+			commitID = strings.TrimSpace(commitID)
+
+			dstPath, err := ioutil.TempDir("", "repo1")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dstPath)
+			_, err = git.NewCommand("clone", "--no-checkout", "--filter=blob:none", u.String(), dstPath).Run()
+			assert.NoError(t, err)
+
+			// only the objects reachable from a ref can be fetched by their ID
+			_, err = git.NewCommand("fetch", "origin", commitID).RunInDir(dstPath)
+			assert.Error(t, err)
+			headCommitID, err := git.NewCommand("rev-parse", "origin/master").RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("fetch", "origin", strings.TrimSpace(headCommitID)).RunInDir(dstPath)
+			assert.NoError(t, err)
+		})
+
+		t.Run("Full", func(t *testing.T) {
+			dstPath, err := ioutil.TempDir("", "repo1")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dstPath)
+
+			assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+			objects, err := git.NewCommand("rev-list", "--objects", "--all", "--missing=print").RunInDir(dstPath)
+			assert.NoError(t, err)
+			assert.NotContains(t, objects, "\n?")
+			assert.True(t, com.IsExist(filepath.Join(dstPath, "README.md")))
+		})
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitProtectedFiles(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		assert.NoError(t, repo.GetOwner())
+		assert.NoError(t, repo.AddCollaborator(user4))
+
+		// both users can push to master, only user2 can change docs
+		session := loginUser(t, "user2")
+		settings := func(patterns string) map[string]string {
+			return map[string]string{
+				"_csrf":                           GetCSRF(t, session, "/user2/repo1/settings/branches/master"),
+				"protected":                       "on",
+				"enable_whitelist":                "on",
+				"whitelist_users":                 "2,4",
+				"protected_file_patterns":         patterns,
+				"protected_files_whitelist_users": "2",
+			}
+		}
+		req := NewRequestWithValues(t, "POST", "/user2/repo1/settings/branches/master", settings("docs/**"))
+		session.MakeRequest(t, req, http.StatusFound)
+		protectBranch := models.AssertExistsAndLoadBean(t, &models.ProtectedBranch{RepoID: repo.ID, BranchName: "master"}).(*models.ProtectedBranch)
+		assert.Equal(t, "docs/**", protectBranch.ProtectedFilePatterns)
+		assert.Equal(t, []int64{2}, protectBranch.ProtectedFilesUserIDs)
+
+		// the settings page shows the whitelist, so saving it again keeps it
+		req = NewRequest(t, "GET", "/user2/repo1/settings/branches/master")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		assert.Equal(t, "2", doc.GetInputValueByName("protected_files_whitelist_users"))
+		assert.Equal(t, "docs/**", doc.GetInputValueByName("protected_file_patterns"))
+
+		// an invalid pattern is reported instead of saved
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/branches/master", settings("docs/[z-a]"))
+		session.MakeRequest(t, req, http.StatusFound)
+		protectBranch = models.AssertExistsAndLoadBean(t, &models.ProtectedBranch{ID: protectBranch.ID}).(*models.ProtectedBranch)
+		assert.Equal(t, "docs/**", protectBranch.ProtectedFilePatterns)
+		assert.Equal(t, []int64{2}, protectBranch.ProtectedFilesUserIDs)
+
+		// release does not exist yet and has the same protection
+		assert.NoError(t, models.UpdateProtectBranch(repo, &models.ProtectedBranch{
+			RepoID:                repo.ID,
+			BranchName:            "release",
+			EnableWhitelist:       true,
+			ProtectedFilePatterns: "docs/**",
+		}, models.WhitelistOptions{
+			UserIDs:               []int64{2, 4},
+			ProtectedFilesUserIDs: []int64{2},
+		}))
+
+		u.Path = "user2/repo1.git"
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		u.User = url.UserPassword("user2", userPassword)
+		owner := u.String()
+		u.User = url.UserPassword("user4", userPassword)
+		collaborator := u.String()
+
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		assert.NoError(t, err)
+
+		commit := func(path, content string) {
+			assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dstPath, path)), 0755))
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, path), []byte(content), 0644))
+			_, err := git.NewCommand("add", path).RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+				"commit", "-m", "change "+path).RunInDir(dstPath)
+			assert.NoError(t, err)
+		}
+		push := func(remote string, refspecs ...string) error {
+			_, err := git.NewCommand(append([]string{"push", remote}, refspecs...)...).RunInDir(dstPath)
+			return err
+		}
+
+		t.Run("Update", func(t *testing.T) {
+			commit("README.md", "changed by user4")
+			assert.NoError(t, push(collaborator, "master"))
+
+			commit("docs/index.md", "protected")
+			assert.Error(t, push(collaborator, "master"))
+			assert.NoError(t, push(owner, "master"))
+			headCommitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+			assert.NoError(t, err)
+			commitID, err := gitRepo.GetBranchCommitID("master")
+			assert.NoError(t, err)
+			assert.Equal(t, headCommitID[:40], commitID)
+		})
+
+		t.Run("Create", func(t *testing.T) {
+			// creating the branch counts as changing all of its files
+			assert.Error(t, push(collaborator, "master:release"))
+			assert.False(t, gitRepo.IsBranchExist("release"))
+			assert.NoError(t, push(owner, "master:release"))
+			assert.True(t, gitRepo.IsBranchExist("release"))
+		})
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitProtectedTag(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+		assert.NoError(t, repo.GetOwner())
+		assert.NoError(t, repo.AddCollaborator(user4))
+
+		// protect the v* tags, only user2 can push them
+		session := loginUser(t, "user2")
+		req := NewRequestWithValues(t, "POST", "/user2/repo1/settings/tags", map[string]string{
+			"_csrf":           GetCSRF(t, session, "/user2/repo1/settings/tags"),
+			"name_pattern":    "v*",
+			"whitelist_users": "2",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+		protectTag := models.AssertExistsAndLoadBean(t, &models.ProtectedTag{RepoID: repo.ID, NamePattern: "v*"}).(*models.ProtectedTag)
+		assert.Equal(t, []int64{2}, protectTag.WhitelistUserIDs)
+		req = NewRequestf(t, "GET", "/user2/repo1/settings/tags/%d", protectTag.ID)
+		session.MakeRequest(t, req, http.StatusOK)
+
+		u.Path = "user2/repo1.git"
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		u.User = url.UserPassword("user2", userPassword)
+		owner := u.String()
+		u.User = url.UserPassword("user4", userPassword)
+		collaborator := u.String()
+
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		assert.NoError(t, err)
+
+		push := func(remote string, refspecs ...string) error {
+			_, err := git.NewCommand(append([]string{"push", remote}, refspecs...)...).RunInDir(dstPath)
+			return err
+		}
+
+		t.Run("Create", func(t *testing.T) {
+			_, err := git.NewCommand("tag", "v2.0").RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("tag", "v2.1").RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("tag", "other").RunInDir(dstPath)
+			assert.NoError(t, err)
+
+			assert.Error(t, push(collaborator, "v2.0"))
+			assert.False(t, gitRepo.IsTagExist("v2.0"))
+			assert.NoError(t, push(owner, "v2.0"))
+			assert.True(t, gitRepo.IsTagExist("v2.0"))
+
+			// the tag names are checked as they are, not unescaped
+			for _, name := range []string{"v%2f1", "v2#1"} {
+				_, err = git.NewCommand("tag", name).RunInDir(dstPath)
+				assert.NoError(t, err)
+				assert.Error(t, push(collaborator, name))
+				assert.False(t, gitRepo.IsTagExist(name))
+			}
+
+			// tags not matching any pattern are not restricted
+			assert.NoError(t, push(collaborator, "other"))
+			assert.True(t, gitRepo.IsTagExist("other"))
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			assert.Error(t, push(collaborator, ":refs/tags/v2.0"))
+			assert.True(t, gitRepo.IsTagExist("v2.0"))
+			assert.NoError(t, push(collaborator, ":refs/tags/other"))
+			assert.False(t, gitRepo.IsTagExist("other"))
+		})
+
+		t.Run("ForceUpdate", func(t *testing.T) {
+			_, err := git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+				"commit", "--allow-empty", "-m", "second commit").RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("tag", "-f", "v2.0").RunInDir(dstPath)
+			assert.NoError(t, err)
+			newCommitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+			assert.NoError(t, err)
+
+			assert.Error(t, push(collaborator, "-f", "v2.0"))
+			commitID, err := gitRepo.GetTagCommitID("v2.0")
+			assert.NoError(t, err)
+			assert.NotEqual(t, newCommitID[:40], commitID)
+
+			assert.NoError(t, push(owner, "-f", "v2.0"))
+			commitID, err = gitRepo.GetTagCommitID("v2.0")
+			assert.NoError(t, err)
+			assert.Equal(t, newCommitID[:40], commitID)
+
+			assert.NoError(t, push(owner, ":refs/tags/v2.0"))
+			assert.False(t, gitRepo.IsTagExist("v2.0"))
+		})
+
+		// the releases can not be used to bypass the protection
+		session = loginUser(t, "user4")
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/releases/new", map[string]string{
+			"_csrf":      GetCSRF(t, session, "/user2/repo1/releases/new"),
+			"tag_name":   "v2.1",
+			"tag_target": "master",
+			"title":      "v2.1 is released",
+		})
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "The tag name is protected.")
+		assert.False(t, gitRepo.IsTagExist("v2.1"))
+	})
+}
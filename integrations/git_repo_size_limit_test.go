@@ -0,0 +1,112 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitRepoSizeLimit(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+		owner.MaxRepoSize = 1
+		assert.NoError(t, models.UpdateUserCols(owner, "max_repo_size"))
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		assert.NoError(t, err)
+
+		// commits a file of random data, which git can not compress
+		commitFile := func(name string, size int) string {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			assert.NoError(t, err)
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, name), data, 0644))
+			_, err = git.NewCommand("add", name).RunInDir(dstPath)
+			assert.NoError(t, err)
+			_, err = git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+				"commit", "-m", "add "+name).RunInDir(dstPath)
+			assert.NoError(t, err)
+			commitID, err := git.NewCommand("rev-parse", "HEAD").RunInDir(dstPath)
+			assert.NoError(t, err)
+			return commitID[:40]
+		}
+		push := func(refspec string) error {
+			_, err := git.NewCommand("push", "origin", refspec).RunInDir(dstPath)
+			return err
+		}
+
+		t.Run("UnderLimit", func(t *testing.T) {
+			commitID := commitFile("small.bin", 10<<10)
+			assert.NoError(t, push("master"))
+			masterID, err := gitRepo.GetBranchCommitID("master")
+			assert.NoError(t, err)
+			assert.Equal(t, commitID, masterID)
+		})
+
+		t.Run("OverLimit", func(t *testing.T) {
+			_, err := git.NewCommand("checkout", "-b", "big").RunInDir(dstPath)
+			assert.NoError(t, err)
+			commitFile("big.bin", 2<<20)
+			err = push("big")
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "exceeds the repository size limit of 1.0MB")
+			}
+			assert.False(t, gitRepo.IsBranchExist("big"))
+		})
+
+		t.Run("LFS", func(t *testing.T) {
+			_, err := git.NewCommand("checkout", "master").RunInDir(dstPath)
+			assert.NoError(t, err)
+			assert.NoError(t, push("master:lfs"))
+
+			// LFS objects are uploaded before the git objects are pushed
+			_, err = models.NewLFSMetaObject(&models.LFSMetaObject{
+				Oid:          "2eccdb43825d2a49d99d542daa20075cff1d97d9d2349a8977efe9c03661737c",
+				Size:         1 << 20,
+				RepositoryID: repo.ID,
+			})
+			assert.NoError(t, err)
+			commitFile("pointer.txt", 128)
+			assert.Error(t, push("master"))
+
+			// pushes adding no object are still allowed
+			assert.NoError(t, push(":lfs"))
+			assert.False(t, gitRepo.IsBranchExist("lfs"))
+		})
+
+		t.Run("Shrink", func(t *testing.T) {
+			// the repository exceeds its limit, dropping small.bin reduces
+			// its size even though the push adds a commit
+			_, err := git.NewCommand("reset", "--hard", "HEAD~2").RunInDir(dstPath)
+			assert.NoError(t, err)
+			commitID := commitFile("tiny.bin", 16)
+			assert.NoError(t, push("+master"))
+			masterID, err := gitRepo.GetBranchCommitID("master")
+			assert.NoError(t, err)
+			assert.Equal(t, commitID, masterID)
+
+			commitFile("tiny2.bin", 16)
+			assert.Error(t, push("master"))
+		})
+	})
+}
@@ -184,3 +184,35 @@ func TestIssueCommentClose(t *testing.T) {
 	val := htmlDoc.doc.Find(".comment-list .comments .comment .render-content p").First().Text()
 	assert.Equal(t, "Description", val)
 }
+
+func TestIssueMove(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+	issueURL := testNewIssue(t, session, "user2", "repo1", "Moved issue", "Description")
+	testIssueAddComment(t, session, issueURL, "Test comment", "")
+
+	req := NewRequest(t, "GET", issueURL)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	link, exists := htmlDoc.doc.Find(".ui.move form").Attr("action")
+	assert.True(t, exists, "The template has changed")
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":       htmlDoc.GetCSRF(),
+		"target_repo": "user3/repo3",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	movedURL := test.RedirectURL(resp)
+	assert.Contains(t, movedURL, "/user3/repo3/issues/")
+
+	req = NewRequest(t, "GET", movedURL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "Moved issue", htmlDoc.doc.Find("#issue-title").Text())
+	assert.Equal(t, "Test comment", htmlDoc.doc.Find(".comment-list .comments .comment .render-content p").Eq(1).Text())
+
+	req = NewRequest(t, "GET", issueURL)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.Equal(t, 1, htmlDoc.doc.Find(".comment-list a[href$=\""+movedURL+"\"]").Length())
+}
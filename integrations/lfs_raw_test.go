@@ -0,0 +1,102 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lfsPointer(content []byte) (*models.LFSMetaObject, string) {
+	hash := sha256.Sum256(content)
+	meta := &models.LFSMetaObject{Oid: hex.EncodeToString(hash[:]), Size: int64(len(content))}
+	return meta, fmt.Sprintf("%s\n%s%s\nsize %d\n", models.LFSMetaFileIdentifier, models.LFSMetaFileOidPrefix, meta.Oid, meta.Size)
+}
+
+func TestRawLFSFile(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+		// a PNG image stored in LFS, and a pointer to an object never uploaded
+		content := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+		meta, pointer := lfsPointer(content)
+		meta.RepositoryID = repo.ID
+		_, err := models.NewLFSMetaObject(meta)
+		assert.NoError(t, err)
+		contentStore := &lfs.ContentStore{BasePath: setting.LFS.ContentPath}
+		assert.NoError(t, contentStore.Put(meta, bytes.NewReader(content)))
+		_, missingPointer := lfsPointer([]byte("missing"))
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "image.png"), []byte(pointer), 0644))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, "missing.bin"), []byte(missingPointer), 0644))
+		_, err = git.NewCommand("add", "image.png", "missing.bin").RunInDir(dstPath)
+		assert.NoError(t, err)
+		_, err = git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+			"commit", "-m", "add LFS pointers").RunInDir(dstPath)
+		assert.NoError(t, err)
+		_, err = git.NewCommand("push", "origin", "master").RunInDir(dstPath)
+		assert.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		t.Run("Normal", func(t *testing.T) {
+			req := NewRequest(t, "GET", "/user2/repo1/raw/branch/master/README.md")
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "# repo1\n\nDescription for repo1", resp.Body.String())
+			assert.Equal(t, "text/plain; charset=utf-8", resp.Header().Get("Content-Type"))
+		})
+
+		t.Run("LFS", func(t *testing.T) {
+			for _, link := range []string{
+				"/user2/repo1/raw/branch/master/image.png",
+				"/api/v1/repos/user2/repo1/raw/master/image.png?token=" + token,
+			} {
+				req := NewRequest(t, "GET", link)
+				resp := session.MakeRequest(t, req, http.StatusOK)
+				assert.Equal(t, content, resp.Body.Bytes())
+				assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+				assert.Equal(t, fmt.Sprint(len(content)), resp.Header().Get("Content-Length"))
+			}
+
+			// the pointer itself can still be fetched
+			req := NewRequest(t, "GET", "/user2/repo1/raw/branch/master/image.png?pointer=true")
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, pointer, resp.Body.String())
+		})
+
+		t.Run("MissingLFSObject", func(t *testing.T) {
+			req := NewRequest(t, "GET", "/user2/repo1/raw/branch/master/missing.bin")
+			session.MakeRequest(t, req, http.StatusNotFound)
+			req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/master/missing.bin?token="+token)
+			session.MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequest(t, "GET", "/user2/repo1/raw/branch/master/missing.bin?pointer=true")
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, missingPointer, resp.Body.String())
+		})
+	})
+}
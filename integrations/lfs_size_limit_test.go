@@ -0,0 +1,73 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/lfs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFSRepoSizeLimit(t *testing.T) {
+	prepareTestEnv(t)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	owner.MaxRepoSize = 10
+	assert.NoError(t, models.UpdateUserCols(owner, "max_repo_size"))
+	session := loginUser(t, "user2")
+
+	const (
+		smallOid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+		bigOid   = "5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef"
+	)
+	newRequest := func(method, path string, values interface{}) *http.Request {
+		req := NewRequestWithJSON(t, method, "/user2/repo1.git/info/lfs"+path, values)
+		req.Header.Set("Accept", "application/vnd.git-lfs+json")
+		req.Header.Set("Content-Type", "application/vnd.git-lfs+json")
+		return req
+	}
+
+	t.Run("Batch", func(t *testing.T) {
+		req := newRequest("POST", "/objects/batch", &lfs.BatchVars{
+			Operation: "upload",
+			Objects: []*lfs.RequestVars{
+				{Oid: smallOid, Size: 1 << 10},
+				{Oid: bigOid, Size: 16 << 20},
+			},
+		})
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var batch lfs.BatchResponse
+		DecodeJSON(t, resp, &batch)
+		if assert.Len(t, batch.Objects, 2) {
+			assert.Nil(t, batch.Objects[0].Error)
+			assert.Contains(t, batch.Objects[0].Actions, "upload")
+			if assert.NotNil(t, batch.Objects[1].Error) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, batch.Objects[1].Error.Code)
+			}
+			assert.Empty(t, batch.Objects[1].Actions)
+		}
+		models.AssertNotExistsBean(t, &models.LFSMetaObject{Oid: bigOid, RepositoryID: repo.ID})
+	})
+
+	t.Run("Post", func(t *testing.T) {
+		req := newRequest("POST", "/objects", &lfs.RequestVars{Oid: bigOid, Size: 16 << 20})
+		session.MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+		models.AssertNotExistsBean(t, &models.LFSMetaObject{Oid: bigOid, RepositoryID: repo.ID})
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		// the meta object was created while the repository was not limited
+		_, err := models.NewLFSMetaObject(&models.LFSMetaObject{Oid: bigOid, Size: 16 << 20, RepositoryID: repo.ID})
+		assert.NoError(t, err)
+		req := NewRequestWithBody(t, "PUT", "/user2/repo1.git/info/lfs/objects/"+bigOid, bytes.NewReader(make([]byte, 1<<10)))
+		req.Header.Set("Accept", "application/vnd.git-lfs")
+		session.MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgSettingsRepoUnits(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	// keep only the issues enabled on new repositories
+	req := NewRequestWithValues(t, "POST", "/org/user3/settings", map[string]string{
+		"_csrf":      GetCSRF(t, session, "/org/user3/settings"),
+		"name":       "user3",
+		"repo_units": fmt.Sprint(models.UnitTypeIssues),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	assert.True(t, org.IsRepoUnitDisabled(models.UnitTypeWiki))
+	assert.True(t, org.IsRepoUnitDisabled(models.UnitTypePullRequests))
+	assert.False(t, org.IsRepoUnitDisabled(models.UnitTypeIssues))
+
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/org/user3/repos?token="+token, &api.CreateRepoOption{
+		Name: "repo-units",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiRepo api.Repository
+	DecodeJSON(t, resp, &apiRepo)
+
+	repo, err := models.GetRepositoryByID(apiRepo.ID)
+	assert.NoError(t, err)
+	assert.True(t, repo.UnitEnabled(models.UnitTypeCode))
+	assert.True(t, repo.UnitEnabled(models.UnitTypeIssues))
+	assert.False(t, repo.UnitEnabled(models.UnitTypePullRequests))
+	assert.False(t, repo.UnitEnabled(models.UnitTypeWiki))
+
+	// the defaults are shown on the settings page
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/org/user3/settings"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(`input[name="repo_units"][checked]`).Length())
+	assert.EqualValues(t, 3, htmlDoc.doc.Find(`input[name="repo_units"]`).Length())
+}
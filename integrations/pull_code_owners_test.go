@@ -0,0 +1,49 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullCreate_CodeOwners(t *testing.T) {
+	prepareTestEnv(t)
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user2/repo1/_new/master/")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	doc := NewHTMLParser(t, resp.Body)
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/_new/master/", map[string]string{
+		"_csrf":         doc.GetCSRF(),
+		"last_commit":   doc.GetInputValueByName("last_commit"),
+		"tree_path":     ".gitea/CODEOWNERS",
+		"content":       "*          @user4\nREADME.md  @user5 @user1\n",
+		"commit_choice": "direct",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	session = loginUser(t, "user1")
+	testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+	testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+	resp = testPullCreate(t, session, "user1", "repo1", "master", "This is a pull title")
+
+	// only the owner of README.md other than the poster is requested
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{Title: "This is a pull title"}).(*models.Issue)
+	models.AssertExistsAndLoadBean(t, &models.Review{IssueID: issue.ID, ReviewerID: 5, Type: models.ReviewTypeRequest})
+	models.AssertCount(t, &models.Review{IssueID: issue.ID}, 1)
+
+	req = NewRequest(t, "GET", test.RedirectURL(resp))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	doc = NewHTMLParser(t, resp.Body)
+	reviewer := strings.TrimSpace(doc.doc.Find(".review-item .text a").First().Text())
+	assert.Equal(t, "user5", reviewer)
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullSquashCommitAuthor(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		prUnit, err := repo.GetUnit(models.UnitTypePullRequests)
+		assert.NoError(t, err)
+		prUnit.PullRequestsConfig().SquashUseCommitAuthor = true
+		units := make([]models.RepoUnit, 0, len(repo.Units))
+		for _, unit := range repo.Units {
+			units = append(units, *unit)
+		}
+		assert.NoError(t, models.UpdateRepositoryUnits(repo, units))
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+		dstPath, err := ioutil.TempDir("", "repo1")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dstPath)
+		assert.NoError(t, git.Clone(u.String(), dstPath, git.CloneRepoOptions{}))
+
+		// pushes a branch with a commit of each author
+		pushBranch := func(branch string, authors ...string) {
+			_, err := git.NewCommand("checkout", "-b", branch, "origin/master").RunInDir(dstPath)
+			assert.NoError(t, err)
+			for i, author := range authors {
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(dstPath, branch+".txt"), []byte(fmt.Sprintf("change %d\n", i)), 0644))
+				_, err = git.NewCommand("add", branch+".txt").RunInDir(dstPath)
+				assert.NoError(t, err)
+				_, err = git.NewCommand("-c", "user.name=user2", "-c", "user.email=user2@example.com",
+					"commit", "--author="+author, "-m", fmt.Sprintf("change %d", i)).RunInDir(dstPath)
+				assert.NoError(t, err)
+			}
+			_, err = git.NewCommand("push", "origin", branch).RunInDir(dstPath)
+			assert.NoError(t, err)
+		}
+
+		// user2 opens the pull request, the admin user1 squashes it
+		posterToken := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+		mergerToken := getTokenForLoggedInUser(t, loginUser(t, "user1"))
+		squash := func(branch string) *git.Commit {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/pulls?token="+posterToken, &api.CreatePullRequestOption{
+				Head:  branch,
+				Base:  "master",
+				Title: "Squash " + branch,
+			})
+			resp := MakeRequest(t, req, http.StatusCreated)
+			var pr api.PullRequest
+			DecodeJSON(t, resp, &pr)
+
+			req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/merge?token=%s", pr.Index, mergerToken), &auth.MergePullRequestForm{
+				Do: string(models.MergeStyleSquash),
+			})
+			MakeRequest(t, req, http.StatusOK)
+
+			gitRepo, err := git.OpenRepository(repo.RepoPath())
+			assert.NoError(t, err)
+			commit, err := gitRepo.GetBranchCommit("master")
+			assert.NoError(t, err)
+			return commit
+		}
+
+		t.Run("SingleAuthor", func(t *testing.T) {
+			pushBranch("single-author", "Contributor <contributor@example.com>", "Contributor <contributor@example.com>")
+			commit := squash("single-author")
+			assert.Equal(t, "Contributor", commit.Author.Name)
+			assert.Equal(t, "contributor@example.com", commit.Author.Email)
+			assert.Equal(t, "User One", commit.Committer.Name)
+			assert.Equal(t, "user1@example.com", commit.Committer.Email)
+		})
+
+		t.Run("MultipleAuthors", func(t *testing.T) {
+			pushBranch("multiple-authors", "Contributor <contributor@example.com>", "Other <other@example.com>")
+			commit := squash("multiple-authors")
+			// falls back to the poster of the pull request
+			assert.Equal(t, "User Two", commit.Author.Name)
+			assert.Equal(t, "user2@example.com", commit.Author.Email)
+			assert.Equal(t, "User One", commit.Committer.Name)
+		})
+	})
+}
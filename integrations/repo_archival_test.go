@@ -0,0 +1,46 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoSettingsArchive(t *testing.T) {
+	prepareTestEnv(t)
+
+	archive := func(session *TestSession, action string, expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", "/user2/repo1/settings", map[string]string{
+			"_csrf":  GetCSRF(t, session, "/user2/repo1/settings"),
+			"action": action,
+		})
+		session.MakeRequest(t, req, expectedStatus)
+	}
+
+	// only the owner can archive the repository, not its administrators
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+	assert.NoError(t, repo.AddCollaborator(models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)))
+	assert.NoError(t, repo.ChangeCollaborationAccessMode(4, models.AccessModeAdmin))
+	archive(loginUser(t, "user4"), "archive", http.StatusNotFound)
+	assert.False(t, models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository).IsArchived)
+
+	session := loginUser(t, "user2")
+	archive(session, "archive", http.StatusFound)
+	assert.True(t, models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository).IsArchived)
+
+	req := NewRequest(t, "GET", "/user2/repo1/settings")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	doc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, doc.doc.Find(`input[name="action"][value="unarchive"]`).Length())
+
+	archive(session, "unarchive", http.StatusFound)
+	assert.False(t, models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository).IsArchived)
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoSettingsCloseIssuesBranches(t *testing.T) {
+	prepareTestEnv(t)
+	session := loginUser(t, "user2")
+
+	req := NewRequestWithValues(t, "POST", "/user2/repo1/settings", map[string]string{
+		"_csrf":                   GetCSRF(t, session, "/user2/repo1/settings"),
+		"action":                  "advanced",
+		"enable_issues":           "on",
+		"enable_external_tracker": "false",
+		"close_issues_branches":   "develop\n release/* \n",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	unit, err := repo.GetUnit(models.UnitTypeIssues)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"develop", "release/*"}, unit.IssuesConfig().CloseIssuesBranches)
+
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/settings"), http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "develop\nrelease/*", htmlDoc.doc.Find("#close_issues_branches").Text())
+
+	// invalid patterns are rejected
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/settings", map[string]string{
+		"_csrf":                   GetCSRF(t, session, "/user2/repo1/settings"),
+		"action":                  "advanced",
+		"enable_issues":           "on",
+		"enable_external_tracker": "false",
+		"close_issues_branches":   "release/[z-a]",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	unit, err = repo.GetUnit(models.UnitTypeIssues)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"develop", "release/*"}, unit.IssuesConfig().CloseIssuesBranches)
+}
@@ -476,8 +476,37 @@ func getIssueFromRef(repo *Repository, ref string) (*Issue, error) {
 	return issue, nil
 }
 
-// UpdateIssuesCommit checks if issues are manipulated by commit message.
-func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) error {
+// canCloseIssuesInBranch returns true if the keywords of the commits pushed to
+// the branch close and reopen issues: the default branch always does, other
+// branches have to match a pattern of the issues settings of the repository.
+func (repo *Repository) canCloseIssuesInBranch(branchName string) bool {
+	if branchName == repo.DefaultBranch {
+		return true
+	}
+
+	u, err := repo.GetUnit(UnitTypeIssues)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range u.IssuesConfig().CloseIssuesBranches {
+		re, err := util.CompileGlob(pattern, '/')
+		if err != nil {
+			log.Error(4, "CompileGlob [%s]: %v", pattern, err)
+			continue
+		}
+		if re.MatchString(branchName) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateIssuesCommit checks if issues are manipulated by the message of the
+// commits pushed to the branch. Issues are referenced on any branch but
+// closed or reopened only on the branches allowed by the repository.
+func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit, branchName string) error {
+	canClose := repo.canCloseIssuesInBranch(branchName)
+
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -500,6 +529,10 @@ func UpdateIssuesCommit(doer *User, repo *Repository, commits []*PushCommit) err
 			}
 		}
 
+		if !canClose {
+			continue
+		}
+
 		refMarked = make(map[int64]bool)
 		// FIXME: can merge this one and next one to a common function.
 		for _, ref := range issueCloseKeywordsPat.FindAllString(c.Message, -1) {
@@ -609,7 +642,7 @@ func CommitRepoAction(opts CommitRepoActionOptions) error {
 			opts.Commits.CompareURL = repo.ComposeCompareURL(opts.OldCommitID, opts.NewCommitID)
 		}
 
-		if err = UpdateIssuesCommit(pusher, repo, opts.Commits.Commits); err != nil {
+		if err = UpdateIssuesCommit(pusher, repo, opts.Commits.Commits, refName); err != nil {
 			log.Error(4, "updateIssuesCommit: %v", err)
 		}
 	}
@@ -227,12 +227,68 @@ func TestUpdateIssuesCommit(t *testing.T) {
 
 	AssertNotExistsBean(t, commentBean)
 	AssertNotExistsBean(t, &Issue{RepoID: repo.ID, Index: 2}, "is_closed=1")
-	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits))
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, repo.DefaultBranch))
 	AssertExistsAndLoadBean(t, commentBean)
 	AssertExistsAndLoadBean(t, issueBean, "is_closed=1")
 	CheckConsistencyFor(t, &Action{})
 }
 
+func TestUpdateIssuesCommit_Branches(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo.Owner = user
+
+	unit := AssertExistsAndLoadBean(t, &RepoUnit{RepoID: repo.ID, Type: UnitTypeIssues}).(*RepoUnit)
+	unit.IssuesConfig().CloseIssuesBranches = []string{"develop", "release/*"}
+	_, err := x.ID(unit.ID).Cols("config").Update(unit)
+	assert.NoError(t, err)
+
+	pushCommits := []*PushCommit{
+		{
+			Sha1:           "abcdef3",
+			CommitterEmail: "user2@example.com",
+			CommitterName:  "User Two",
+			AuthorEmail:    "user2@example.com",
+			AuthorName:     "User Two",
+			Message:        "fixes #1",
+		},
+	}
+	commentBean := &Comment{
+		Type:      CommentTypeCommitRef,
+		CommitSHA: "abcdef3",
+		PosterID:  user.ID,
+		IssueID:   1,
+	}
+
+	// other branches only reference the issue
+	for _, branch := range []string{"feature", "release/1.0/fix"} {
+		assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, branch))
+		AssertExistsAndLoadBean(t, commentBean)
+		AssertNotExistsBean(t, &Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+	}
+
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, "release/1.0"))
+	AssertExistsAndLoadBean(t, &Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+
+	pushCommits[0].Message = "reopens #1"
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, "feature"))
+	AssertExistsAndLoadBean(t, &Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+	assert.NoError(t, UpdateIssuesCommit(user, repo, pushCommits, "develop"))
+	AssertNotExistsBean(t, &Issue{RepoID: repo.ID, Index: 1}, "is_closed=1")
+	CheckConsistencyFor(t, &Action{})
+}
+
+func TestIssuesConfig_ValidateCloseIssuesBranches(t *testing.T) {
+	cfg := &IssuesConfig{CloseIssuesBranches: []string{"develop", "release/*"}}
+	assert.NoError(t, cfg.ValidateCloseIssuesBranches())
+
+	cfg.CloseIssuesBranches = append(cfg.CloseIssuesBranches, "release/[z-a]")
+	err := cfg.ValidateCloseIssuesBranches()
+	assert.True(t, IsErrInvalidCloseIssuesBranchPattern(err))
+	assert.Equal(t, "release/[z-a]", err.(ErrInvalidCloseIssuesBranchPattern).Pattern)
+}
+
 func testCorrectRepoAction(t *testing.T, opts CommitRepoActionOptions, actionBean *Action) {
 	AssertNotExistsBean(t, actionBean)
 	assert.NoError(t, CommitRepoAction(opts))
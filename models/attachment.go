@@ -7,7 +7,6 @@ package models
 import (
 	"fmt"
 	"io"
-	"mime/multipart"
 	"os"
 	"path"
 
@@ -71,36 +70,43 @@ func (a *Attachment) DownloadURL() string {
 	return fmt.Sprintf("%sattachments/%s", setting.AppURL, a.UUID)
 }
 
-// NewAttachment creates a new attachment object.
-func NewAttachment(name string, buf []byte, file multipart.File) (_ *Attachment, err error) {
-	attach := &Attachment{
-		UUID: gouuid.NewV4().String(),
-		Name: name,
-	}
-
-	localPath := attach.LocalPath()
-	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
-		return nil, fmt.Errorf("MkdirAll: %v", err)
+// writeAttachmentFile stores buf followed by the rest of file at localPath
+// and returns the size of the stored file.
+func writeAttachmentFile(localPath string, buf []byte, file io.Reader) (int64, error) {
+	if err := os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
+		return 0, fmt.Errorf("MkdirAll: %v", err)
 	}
 
 	fw, err := os.Create(localPath)
 	if err != nil {
-		return nil, fmt.Errorf("Create: %v", err)
+		return 0, fmt.Errorf("Create: %v", err)
 	}
 	defer fw.Close()
 
 	if _, err = fw.Write(buf); err != nil {
-		return nil, fmt.Errorf("Write: %v", err)
+		return 0, fmt.Errorf("Write: %v", err)
 	} else if _, err = io.Copy(fw, file); err != nil {
-		return nil, fmt.Errorf("Copy: %v", err)
+		return 0, fmt.Errorf("Copy: %v", err)
 	}
 
 	// Update file size
 	var fi os.FileInfo
 	if fi, err = fw.Stat(); err != nil {
-		return nil, fmt.Errorf("file size: %v", err)
+		return 0, fmt.Errorf("file size: %v", err)
+	}
+	return fi.Size(), nil
+}
+
+// NewAttachment creates a new attachment object.
+func NewAttachment(name string, buf []byte, file io.Reader) (_ *Attachment, err error) {
+	attach := &Attachment{
+		UUID: gouuid.NewV4().String(),
+		Name: name,
+	}
+
+	if attach.Size, err = writeAttachmentFile(attach.LocalPath(), buf, file); err != nil {
+		return nil, err
 	}
-	attach.Size = fi.Size()
 
 	if _, err := x.Insert(attach); err != nil {
 		return nil, err
@@ -109,6 +115,26 @@ func NewAttachment(name string, buf []byte, file multipart.File) (_ *Attachment,
 	return attach, nil
 }
 
+// ReplaceContent replaces the stored file of the attachment. The new content
+// is written to a temporary file first, so a failed upload keeps the old one.
+func (a *Attachment) ReplaceContent(buf []byte, file io.Reader) (err error) {
+	localPath := a.LocalPath()
+	tmpPath := localPath + ".tmp"
+	size, err := writeAttachmentFile(tmpPath, buf, file)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Rename(tmpPath, localPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("Rename: %v", err)
+	}
+
+	a.Size = size
+	_, err = x.ID(a.ID).Cols("size").Update(a)
+	return err
+}
+
 // GetAttachmentByID returns attachment by given id
 func GetAttachmentByID(id int64) (*Attachment, error) {
 	return getAttachmentByID(x, id)
@@ -195,7 +221,7 @@ func DeleteAttachments(attachments []*Attachment, remove bool) (int, error) {
 
 	if remove {
 		for i, a := range attachments {
-			if err := os.Remove(a.LocalPath()); err != nil {
+			if err := os.Remove(a.LocalPath()); err != nil && !os.IsNotExist(err) {
 				return i, err
 			}
 		}
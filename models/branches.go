@@ -6,8 +6,10 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"code.gitea.io/git"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -36,6 +38,14 @@ type ProtectedBranch struct {
 	ApprovalsWhitelistUserIDs []int64        `xorm:"JSON TEXT"`
 	ApprovalsWhitelistTeamIDs []int64        `xorm:"JSON TEXT"`
 	RequiredApprovals         int64          `xorm:"NOT NULL DEFAULT 0"`
+	ApprovalsTeamID           int64          `xorm:"NOT NULL DEFAULT 0"`
+	ApprovalsTeamWeight       int64          `xorm:"NOT NULL DEFAULT 1"`
+	RequireTeamApproval       bool           `xorm:"NOT NULL DEFAULT false"`
+	ProtectedFilePatterns     string         `xorm:"TEXT"`
+	ProtectedFilesUserIDs     []int64        `xorm:"JSON TEXT"`
+	ProtectedFilesTeamIDs     []int64        `xorm:"JSON TEXT"`
+	EnableStatusCheck         bool           `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts       []string       `xorm:"JSON TEXT"`
 	CreatedUnix               util.TimeStamp `xorm:"created"`
 	UpdatedUnix               util.TimeStamp `xorm:"updated"`
 }
@@ -89,15 +99,52 @@ func (protectBranch *ProtectedBranch) CanUserMerge(userID int64) bool {
 	return in
 }
 
-// HasEnoughApprovals returns true if pr has enough granted approvals.
+// HasEnoughApprovals returns true if pr has enough granted approvals and, if
+// required, has been approved by a member of the approvals team.
 func (protectBranch *ProtectedBranch) HasEnoughApprovals(pr *PullRequest) bool {
+	if protectBranch.RequireTeamApproval && !protectBranch.HasTeamApproval(pr) {
+		return false
+	}
 	if protectBranch.RequiredApprovals == 0 {
 		return true
 	}
 	return protectBranch.GetGrantedApprovalsCount(pr) >= protectBranch.RequiredApprovals
 }
 
-// GetGrantedApprovalsCount returns the number of granted approvals for pr. A granted approval must be authored by a user in an approval whitelist.
+// IsApprovalsWhitelisted returns if the approvals of some user are granted approvals
+func (protectBranch *ProtectedBranch) IsApprovalsWhitelisted(userID int64) bool {
+	if base.Int64sContains(protectBranch.ApprovalsWhitelistUserIDs, userID) {
+		return true
+	}
+
+	if len(protectBranch.ApprovalsWhitelistTeamIDs) == 0 {
+		return false
+	}
+
+	in, err := IsUserInTeams(userID, protectBranch.ApprovalsWhitelistTeamIDs)
+	if err != nil {
+		log.Error(1, "IsUserInTeams:", err)
+		return false
+	}
+	return in
+}
+
+// IsApprovalsTeamMember returns if some user is a member of the approvals team
+func (protectBranch *ProtectedBranch) IsApprovalsTeamMember(userID int64) bool {
+	if protectBranch.ApprovalsTeamID == 0 {
+		return false
+	}
+
+	in, err := IsUserInTeams(userID, []int64{protectBranch.ApprovalsTeamID})
+	if err != nil {
+		log.Error(1, "IsUserInTeams:", err)
+		return false
+	}
+	return in
+}
+
+// GetGrantedApprovalsCount returns the number of granted approvals for pr. A granted approval must be authored by a user in an approval whitelist
+// or in the approvals team, whose approvals count ApprovalsTeamWeight times.
 func (protectBranch *ProtectedBranch) GetGrantedApprovalsCount(pr *PullRequest) int64 {
 	reviews, err := GetReviewersByPullID(pr.Issue.ID)
 	if err != nil {
@@ -105,24 +152,224 @@ func (protectBranch *ProtectedBranch) GetGrantedApprovalsCount(pr *PullRequest)
 		return 0
 	}
 
+	teamWeight := protectBranch.ApprovalsTeamWeight
+	if teamWeight < 1 {
+		teamWeight = 1
+	}
+
 	approvals := int64(0)
-	userIDs := make([]int64, 0)
 	for _, review := range reviews {
 		if review.Type != ReviewTypeApprove {
 			continue
 		}
-		if base.Int64sContains(protectBranch.ApprovalsWhitelistUserIDs, review.ID) {
+		if protectBranch.IsApprovalsTeamMember(review.ID) {
+			approvals += teamWeight
+		} else if protectBranch.IsApprovalsWhitelisted(review.ID) {
 			approvals++
-			continue
 		}
-		userIDs = append(userIDs, review.ID)
 	}
-	approvalTeamCount, err := UsersInTeamsCount(userIDs, protectBranch.ApprovalsWhitelistTeamIDs)
+	return approvals
+}
+
+// HasTeamApproval returns true if pr has been approved by a member of the
+// approvals team, or if there is no approvals team.
+func (protectBranch *ProtectedBranch) HasTeamApproval(pr *PullRequest) bool {
+	if protectBranch.ApprovalsTeamID == 0 {
+		return true
+	}
+
+	reviews, err := GetReviewersByPullID(pr.Issue.ID)
 	if err != nil {
-		log.Error(1, "UsersInTeamsCount:", err)
-		return 0
+		log.Error(1, "GetReviewersByPullID:", err)
+		return false
 	}
-	return approvalTeamCount + approvals
+	for _, review := range reviews {
+		if review.Type == ReviewTypeApprove && protectBranch.IsApprovalsTeamMember(review.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProtectedFilePatterns returns the glob patterns of the files which require
+// an approval of the protected files whitelist to be changed.
+func (protectBranch *ProtectedBranch) GetProtectedFilePatterns() []string {
+	patterns := make([]string, 0, 2)
+	for _, pattern := range strings.Split(protectBranch.ProtectedFilePatterns, ";") {
+		if pattern = strings.TrimSpace(pattern); len(pattern) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// MatchProtectedFiles returns the files matching any protected file pattern.
+func (protectBranch *ProtectedBranch) MatchProtectedFiles(files []string) []string {
+	patterns := protectBranch.GetProtectedFilePatterns()
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	matched := make([]string, 0, len(files))
+	for _, file := range files {
+		for _, pattern := range patterns {
+			if util.GlobMatch(pattern, file, '/') {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// IsProtectedFilesWhitelisted returns if some user is allowed to change the
+// protected files of this protected branch
+func (protectBranch *ProtectedBranch) IsProtectedFilesWhitelisted(userID int64) bool {
+	if base.Int64sContains(protectBranch.ProtectedFilesUserIDs, userID) {
+		return true
+	}
+
+	if len(protectBranch.ProtectedFilesTeamIDs) == 0 {
+		return false
+	}
+
+	in, err := IsUserInTeams(userID, protectBranch.ProtectedFilesTeamIDs)
+	if err != nil {
+		log.Error(1, "IsUserInTeams:", err)
+		return false
+	}
+	return in
+}
+
+// HasProtectedFilesApproval returns true if pr does not change protected files or
+// has been approved by a user of the protected files whitelist.
+func (protectBranch *ProtectedBranch) HasProtectedFilesApproval(pr *PullRequest) (bool, error) {
+	if len(protectBranch.GetProtectedFilePatterns()) == 0 {
+		return true, nil
+	}
+
+	files, err := pr.GetChangedFiles()
+	if err != nil {
+		return false, fmt.Errorf("GetChangedFiles: %v", err)
+	}
+	if len(protectBranch.MatchProtectedFiles(files)) == 0 {
+		return true, nil
+	}
+
+	reviews, err := GetReviewersByPullID(pr.Issue.ID)
+	if err != nil {
+		return false, fmt.Errorf("GetReviewersByPullID: %v", err)
+	}
+	for _, review := range reviews {
+		if review.Type == ReviewTypeApprove && protectBranch.IsProtectedFilesWhitelisted(review.ID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MatchStatusCheckContexts returns true if each required status check context
+// pattern matches at least one of the reported statuses and all statuses
+// matched by a pattern are successful. Patterns are globs where '*' matches
+// any sequence of characters, e.g. "build (*)".
+func MatchStatusCheckContexts(patterns []string, statuses []*CommitStatus) bool {
+	for _, pattern := range patterns {
+		re, err := util.CompileGlob(pattern, 0)
+		if err != nil {
+			return false
+		}
+
+		matched := false
+		for _, status := range statuses {
+			if !re.MatchString(status.Context) {
+				continue
+			}
+			if status.State != CommitStatusSuccess {
+				return false
+			}
+			matched = true
+		}
+		// A required check which has not been reported yet blocks merging.
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// HasRequiredStatusChecks returns true if status checks are not required or
+// the head commit of pr passes all required status checks.
+func (protectBranch *ProtectedBranch) HasRequiredStatusChecks(pr *PullRequest) (bool, error) {
+	if !protectBranch.EnableStatusCheck || len(protectBranch.StatusCheckContexts) == 0 {
+		return true, nil
+	}
+
+	if err := pr.GetBaseRepo(); err != nil {
+		return false, fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return false, fmt.Errorf("OpenRepository: %v", err)
+	}
+	sha, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return false, fmt.Errorf("GetRefCommitID: %v", err)
+	}
+
+	statuses, err := GetAllLatestCommitStatus(pr.BaseRepo, sha)
+	if err != nil {
+		return false, fmt.Errorf("GetAllLatestCommitStatus: %v", err)
+	}
+	return MatchStatusCheckContexts(protectBranch.StatusCheckContexts, statuses), nil
+}
+
+// GetChangedFilesBetween returns the paths of the files changed between two commits
+// of the repository at repoPath.
+func GetChangedFilesBetween(repoPath, oldCommitID, newCommitID string) ([]string, error) {
+	stdout, err := git.NewCommand("diff", "--name-only", "-z", oldCommitID, newCommitID).RunInDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, 10)
+	for _, file := range strings.Split(stdout, "\x00") {
+		if len(file) > 0 {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GetChangedFilesOfPush returns the paths of the files changed by a push
+// updating a ref from oldCommitID to newCommitID. A push creating the ref
+// changes all the files of its commit.
+func GetChangedFilesOfPush(repoPath, oldCommitID, newCommitID string) ([]string, error) {
+	if oldCommitID == git.EmptySHA {
+		oldCommitID = emptyTreeSHA
+	}
+	return GetChangedFilesBetween(repoPath, oldCommitID, newCommitID)
+}
+
+// ValidateProtectedFilePatterns returns an error if one of the protected
+// file patterns is not a valid glob.
+func (protectBranch *ProtectedBranch) ValidateProtectedFilePatterns() error {
+	for _, pattern := range protectBranch.GetProtectedFilePatterns() {
+		if _, err := util.CompileGlob(pattern, '/'); err != nil {
+			return ErrInvalidProtectedFilePattern{pattern}
+		}
+	}
+	return nil
+}
+
+// ValidateStatusCheckContexts returns an error if one of the required status
+// check context patterns is not a valid glob.
+func (protectBranch *ProtectedBranch) ValidateStatusCheckContexts() error {
+	for _, pattern := range protectBranch.StatusCheckContexts {
+		if _, err := util.CompileGlob(pattern, 0); err != nil {
+			return ErrInvalidStatusCheckPattern{pattern}
+		}
+	}
+	return nil
 }
 
 // GetProtectedBranchByRepoID getting protected branch by repo ID
@@ -167,6 +414,10 @@ type WhitelistOptions struct {
 
 	ApprovalsUserIDs []int64
 	ApprovalsTeamIDs []int64
+	ApprovalsTeamID  int64
+
+	ProtectedFilesUserIDs []int64
+	ProtectedFilesTeamIDs []int64
 }
 
 // UpdateProtectBranch saves branch protection options of repository.
@@ -178,6 +429,10 @@ func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, opts
 		return fmt.Errorf("GetOwner: %v", err)
 	}
 
+	if err = protectBranch.ValidateProtectedFilePatterns(); err != nil {
+		return err
+	}
+
 	whitelist, err := updateUserWhitelist(repo, protectBranch.WhitelistUserIDs, opts.UserIDs)
 	if err != nil {
 		return err
@@ -196,6 +451,12 @@ func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, opts
 	}
 	protectBranch.ApprovalsWhitelistUserIDs = whitelist
 
+	whitelist, err = updateUserWhitelist(repo, protectBranch.ProtectedFilesUserIDs, opts.ProtectedFilesUserIDs)
+	if err != nil {
+		return err
+	}
+	protectBranch.ProtectedFilesUserIDs = whitelist
+
 	// if the repo is in an organization
 	whitelist, err = updateTeamWhitelist(repo, protectBranch.WhitelistTeamIDs, opts.TeamIDs)
 	if err != nil {
@@ -215,6 +476,23 @@ func UpdateProtectBranch(repo *Repository, protectBranch *ProtectedBranch, opts
 	}
 	protectBranch.ApprovalsWhitelistTeamIDs = whitelist
 
+	if protectBranch.ApprovalsTeamID != opts.ApprovalsTeamID {
+		whitelist, err = updateTeamWhitelist(repo, nil, []int64{opts.ApprovalsTeamID})
+		if err != nil {
+			return err
+		}
+		protectBranch.ApprovalsTeamID = 0
+		if len(whitelist) > 0 {
+			protectBranch.ApprovalsTeamID = whitelist[0]
+		}
+	}
+
+	whitelist, err = updateTeamWhitelist(repo, protectBranch.ProtectedFilesTeamIDs, opts.ProtectedFilesTeamIDs)
+	if err != nil {
+		return err
+	}
+	protectBranch.ProtectedFilesTeamIDs = whitelist
+
 	// Make sure protectBranch.ID is not 0 for whitelists
 	if protectBranch.ID == 0 {
 		if _, err = x.Insert(protectBranch); err != nil {
@@ -290,7 +568,18 @@ func (repo *Repository) IsProtectedBranchForMerging(pr *PullRequest, branchName
 	if err != nil {
 		return true, err
 	} else if has {
-		return !protectedBranch.CanUserMerge(doer.ID) || !protectedBranch.HasEnoughApprovals(pr), nil
+		if !protectedBranch.CanUserMerge(doer.ID) || !protectedBranch.HasEnoughApprovals(pr) {
+			return true, nil
+		}
+		approved, err := protectedBranch.HasProtectedFilesApproval(pr)
+		if err != nil || !approved {
+			return true, err
+		}
+		passed, err := protectedBranch.HasRequiredStatusChecks(pr)
+		if err != nil {
+			return true, err
+		}
+		return !passed, nil
 	}
 
 	return false, nil
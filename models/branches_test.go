@@ -7,6 +7,8 @@ package models
 import (
 	"testing"
 
+	"code.gitea.io/git"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,3 +80,184 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestProtectedBranch_MatchProtectedFiles(t *testing.T) {
+	protectBranch := &ProtectedBranch{ProtectedFilePatterns: "migrations/** ; go.mod;;docs/*.md"}
+	assert.Equal(t, []string{"migrations/**", "go.mod", "docs/*.md"}, protectBranch.GetProtectedFilePatterns())
+
+	files := []string{
+		"go.mod",
+		"go.sum",
+		"migrations/v1.sql",
+		"migrations/old/v0.sql",
+		"models/migrations/v1.go",
+		"docs/index.md",
+		"docs/sub/index.md",
+	}
+	assert.Equal(t, []string{"go.mod", "migrations/v1.sql", "migrations/old/v0.sql", "docs/index.md"},
+		protectBranch.MatchProtectedFiles(files))
+
+	protectBranch = &ProtectedBranch{}
+	assert.Empty(t, protectBranch.MatchProtectedFiles(files))
+}
+
+func TestProtectedBranch_ValidateProtectedFilePatterns(t *testing.T) {
+	protectBranch := &ProtectedBranch{ProtectedFilePatterns: "migrations/**;[a-z]*.go"}
+	assert.NoError(t, protectBranch.ValidateProtectedFilePatterns())
+
+	protectBranch = &ProtectedBranch{ProtectedFilePatterns: "migrations/**;[z-a].go"}
+	err := protectBranch.ValidateProtectedFilePatterns()
+	assert.True(t, IsErrInvalidProtectedFilePattern(err))
+}
+
+func TestProtectedBranch_IsProtectedFilesWhitelisted(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	protectBranch := &ProtectedBranch{
+		ProtectedFilesUserIDs: []int64{1},
+		ProtectedFilesTeamIDs: []int64{2},
+	}
+	assert.True(t, protectBranch.IsProtectedFilesWhitelisted(1))
+	assert.True(t, protectBranch.IsProtectedFilesWhitelisted(4))
+	assert.False(t, protectBranch.IsProtectedFilesWhitelisted(5))
+}
+
+func TestProtectedBranch_HasProtectedFilesApproval(t *testing.T) {
+	PrepareTestEnv(t)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	_, err := git.NewCommand("update-ref", "refs/pull/99/head", "27566bd5738fc8b4e3fef3c5e72cce608537bd95").RunInDir(repo.RepoPath())
+	assert.NoError(t, err)
+
+	// user1 approved issue 2
+	pr := &PullRequest{
+		Index:      99,
+		BaseRepoID: repo.ID,
+		MergeBase:  "69554a64c1e6030f051e5c3f94bfbd773cd6a324",
+		Issue:      &Issue{ID: 2},
+	}
+
+	test := func(patterns string, userIDs []int64, expected bool) {
+		protectBranch := &ProtectedBranch{
+			ProtectedFilePatterns: patterns,
+			ProtectedFilesUserIDs: userIDs,
+		}
+		approved, err := protectBranch.HasProtectedFilesApproval(pr)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, approved)
+	}
+	test("", nil, true)
+	test("docs/**", nil, true)
+	test("*.md", nil, false)
+	test("*.md", []int64{5}, false)
+	test("*.md", []int64{1}, true)
+}
+
+func TestProtectedBranch_GetGrantedApprovalsCount(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user4 approved issue 3, user2 rejected it
+	pr := &PullRequest{Issue: &Issue{ID: 3}}
+
+	test := func(protectBranch *ProtectedBranch, expected int64) {
+		assert.EqualValues(t, expected, protectBranch.GetGrantedApprovalsCount(pr))
+		protectBranch.RequiredApprovals = 2
+		assert.Equal(t, expected >= 2, protectBranch.HasEnoughApprovals(pr))
+	}
+	test(&ProtectedBranch{}, 0)
+	test(&ProtectedBranch{ApprovalsWhitelistUserIDs: []int64{4}}, 1)
+	test(&ProtectedBranch{ApprovalsWhitelistUserIDs: []int64{2}, ApprovalsWhitelistTeamIDs: []int64{1}}, 0)
+	// an approval counts once even if its author is whitelisted as user and team member
+	test(&ProtectedBranch{ApprovalsWhitelistUserIDs: []int64{4}, ApprovalsWhitelistTeamIDs: []int64{2}}, 1)
+	test(&ProtectedBranch{ApprovalsTeamID: 2, ApprovalsTeamWeight: 1}, 1)
+	test(&ProtectedBranch{ApprovalsTeamID: 2, ApprovalsTeamWeight: 2}, 2)
+	test(&ProtectedBranch{ApprovalsWhitelistUserIDs: []int64{4}, ApprovalsTeamID: 2, ApprovalsTeamWeight: 0}, 1)
+	test(&ProtectedBranch{ApprovalsTeamID: 1, ApprovalsTeamWeight: 2}, 0)
+}
+
+func TestProtectedBranch_HasTeamApproval(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user4 approved issue 3, user2 rejected it
+	pr := &PullRequest{Issue: &Issue{ID: 3}}
+
+	protectBranch := &ProtectedBranch{ApprovalsTeamID: 2, RequireTeamApproval: true}
+	assert.True(t, protectBranch.HasTeamApproval(pr))
+	assert.True(t, protectBranch.HasEnoughApprovals(pr))
+
+	// the team approval is required regardless of the number of approvals
+	protectBranch = &ProtectedBranch{
+		ApprovalsWhitelistUserIDs: []int64{4},
+		RequiredApprovals:         1,
+		ApprovalsTeamID:           1,
+		RequireTeamApproval:       true,
+	}
+	assert.EqualValues(t, 1, protectBranch.GetGrantedApprovalsCount(pr))
+	assert.False(t, protectBranch.HasTeamApproval(pr))
+	assert.False(t, protectBranch.HasEnoughApprovals(pr))
+
+	protectBranch.RequireTeamApproval = false
+	assert.True(t, protectBranch.HasEnoughApprovals(pr))
+
+	protectBranch = &ProtectedBranch{RequireTeamApproval: true}
+	assert.True(t, protectBranch.HasTeamApproval(pr))
+}
+
+func TestGetChangedFilesBetween(t *testing.T) {
+	PrepareTestEnv(t)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+
+	files, err := GetChangedFilesBetween(repo.RepoPath(), "69554a64c1e6030f051e5c3f94bfbd773cd6a324", "27566bd5738fc8b4e3fef3c5e72cce608537bd95")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"readme.md"}, files)
+
+	files, err = GetChangedFilesBetween(repo.RepoPath(), "27566bd5738fc8b4e3fef3c5e72cce608537bd95", "27566bd5738fc8b4e3fef3c5e72cce608537bd95")
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestMatchStatusCheckContexts(t *testing.T) {
+	statuses := []*CommitStatus{
+		{Context: "build (go1.21)", State: CommitStatusSuccess},
+		{Context: "build (go1.22)", State: CommitStatusSuccess},
+		{Context: "ci/lint", State: CommitStatusSuccess},
+		{Context: "ci/test", State: CommitStatusFailure},
+	}
+
+	for _, test := range []struct {
+		Patterns []string
+		Expected bool
+	}{
+		{nil, true},
+		{[]string{"build (go1.21)"}, true},
+		{[]string{"build (*)"}, true},
+		{[]string{"build (*)", "ci/lint"}, true},
+		{[]string{"build (go1.2?)"}, true},
+		// a failing context matched by the pattern
+		{[]string{"ci/*"}, false},
+		{[]string{"*"}, false},
+		// patterns matching no reported context block merging
+		{[]string{"deploy (*)"}, false},
+		{[]string{"build (*)", "deploy"}, false},
+		{[]string{"build"}, false},
+	} {
+		assert.Equal(t, test.Expected, MatchStatusCheckContexts(test.Patterns, statuses), "patterns: %v", test.Patterns)
+	}
+
+	// nothing reported yet
+	assert.False(t, MatchStatusCheckContexts([]string{"build (*)"}, nil))
+	// a pending context isn't successful
+	assert.False(t, MatchStatusCheckContexts([]string{"build (*)"}, []*CommitStatus{
+		{Context: "build (go1.21)", State: CommitStatusSuccess},
+		{Context: "build (go1.22)", State: CommitStatusPending},
+	}))
+}
+
+func TestProtectedBranch_ValidateStatusCheckContexts(t *testing.T) {
+	protectBranch := &ProtectedBranch{StatusCheckContexts: []string{"build (*)", "ci/[a-z]*"}}
+	assert.NoError(t, protectBranch.ValidateStatusCheckContexts())
+
+	protectBranch.StatusCheckContexts = append(protectBranch.StatusCheckContexts, "ci/[z-a]")
+	err := protectBranch.ValidateStatusCheckContexts()
+	assert.True(t, IsErrInvalidStatusCheckPattern(err))
+	assert.Equal(t, "ci/[z-a]", err.(ErrInvalidStatusCheckPattern).Pattern)
+}
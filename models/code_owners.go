@@ -0,0 +1,288 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CodeOwnersFiles are the paths of the CODEOWNERS file of a repository, in
+// the order they are looked for.
+var CodeOwnersFiles = []string{"CODEOWNERS", ".gitea/CODEOWNERS"}
+
+// maxCodeOwnersSize is the size above which a CODEOWNERS file is truncated.
+const maxCodeOwnersSize = 3 << 20
+
+// CodeOwnerRule associates the files matching a pattern of a CODEOWNERS file
+// with their owners: users written as @name or as an email address, and
+// teams of the owner organization written as @org/team.
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+
+	file *regexp.Regexp
+	dir  *regexp.Regexp
+}
+
+// compile compiles the pattern of the rule, which follows the gitignore
+// rules: a pattern without a slash but a trailing one matches at any depth,
+// a trailing slash matches the content of a directory, and a pattern whose
+// last component has no wildcard also matches the content of a directory.
+func (rule *CodeOwnerRule) compile() (err error) {
+	pattern := rule.Pattern
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if !dirOnly {
+		if rule.file, err = util.CompileGlob(pattern, '/'); err != nil {
+			return err
+		}
+	}
+	if dirOnly || !strings.ContainsAny(pattern[strings.LastIndex(pattern, "/")+1:], "*?[") {
+		if rule.dir, err = util.CompileGlob(pattern+"/**", '/'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match reports whether the file at the given path matches the rule.
+func (rule *CodeOwnerRule) Match(path string) bool {
+	return (rule.file != nil && rule.file.MatchString(path)) ||
+		(rule.dir != nil && rule.dir.MatchString(path))
+}
+
+// CodeOwnerRules are the rules of a CODEOWNERS file, in the order of the file.
+type CodeOwnerRules []*CodeOwnerRule
+
+// ParseCodeOwners parses the content of a CODEOWNERS file. Blank lines and
+// comments are ignored, so are the lines with an invalid pattern.
+func ParseCodeOwners(content string) CodeOwnerRules {
+	rules := make(CodeOwnerRules, 0, 10)
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule := &CodeOwnerRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		}
+		if err := rule.compile(); err != nil {
+			log.Trace("Invalid CODEOWNERS pattern %q: %v", rule.Pattern, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// Owners returns the owners of the file at the given path, those of the last
+// rule matching it.
+func (rules CodeOwnerRules) Owners(path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Match(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOfFiles returns the owners of any of the given files, in the order
+// they are first found.
+func (rules CodeOwnerRules) OwnersOfFiles(paths []string) []string {
+	owners := make([]string, 0, 5)
+	found := make(map[string]bool)
+	for _, path := range paths {
+		for _, owner := range rules.Owners(path) {
+			if !found[strings.ToLower(owner)] {
+				found[strings.ToLower(owner)] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// GetCodeOwners returns the rules of the CODEOWNERS file of a branch of the
+// repository, which are empty if it has no such file.
+func (repo *Repository) GetCodeOwners(branch string) (CodeOwnerRules, error) {
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return nil, fmt.Errorf("GetBranchCommit: %v", err)
+	}
+
+	for _, name := range CodeOwnersFiles {
+		blob, err := commit.GetBlobByPath(name)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("GetBlobByPath: %v", err)
+		}
+		r, err := blob.Data()
+		if err != nil {
+			return nil, fmt.Errorf("Data: %v", err)
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(r, maxCodeOwnersSize))
+		if err != nil {
+			return nil, fmt.Errorf("ReadAll: %v", err)
+		}
+		return ParseCodeOwners(string(content)), nil
+	}
+	return nil, nil
+}
+
+// resolveCodeOwners returns the users designated by the owners of a
+// CODEOWNERS file of the repository, the teams being replaced by their
+// members. The owners which are not users nor teams of the organization
+// owning the repository are ignored.
+func resolveCodeOwners(e Engine, repo *Repository, owners []string) ([]*User, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+
+	users := make([]*User, 0, len(owners))
+	found := make(map[int64]bool)
+	addUser := func(u *User) {
+		if !found[u.ID] && !u.IsOrganization() {
+			found[u.ID] = true
+			users = append(users, u)
+		}
+	}
+
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			u, err := GetUserByEmail(owner)
+			if err != nil {
+				if IsErrUserNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			addUser(u)
+			continue
+		}
+
+		name := owner[1:]
+		if i := strings.Index(name, "/"); i >= 0 {
+			if !repo.Owner.IsOrganization() || !strings.EqualFold(name[:i], repo.Owner.Name) {
+				continue
+			}
+			team, err := getTeam(e, repo.OwnerID, name[i+1:])
+			if err != nil {
+				if err == ErrTeamNotExist {
+					continue
+				}
+				return nil, err
+			}
+			members, err := getTeamMembers(e, team.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				addUser(member)
+			}
+			continue
+		}
+
+		u, err := getUserByName(e, name)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		addUser(u)
+	}
+	return users, nil
+}
+
+// RequestCodeOwnerReviews requests the review of the pull request from the
+// owners of the files it changes, according to the CODEOWNERS file of its
+// base branch. The owners whose review was already requested or given are
+// left as they are, so it is called again whenever the pull request is
+// updated to request the review of the owners of newly changed files.
+func (pr *PullRequest) RequestCodeOwnerReviews() error {
+	if err := pr.GetBaseRepo(); err != nil {
+		return fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	rules, err := pr.BaseRepo.GetCodeOwners(pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("GetCodeOwners: %v", err)
+	} else if len(rules) == 0 {
+		return nil
+	}
+	files, err := pr.GetChangedFiles()
+	if err != nil {
+		return fmt.Errorf("GetChangedFiles: %v", err)
+	}
+	return pr.requestCodeOwnerReviews(x, rules, files)
+}
+
+func (pr *PullRequest) requestCodeOwnerReviews(e Engine, rules CodeOwnerRules, files []string) error {
+	if err := pr.loadIssue(e); err != nil {
+		return err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil
+	}
+	if err := pr.Issue.loadRepo(e); err != nil {
+		return err
+	}
+
+	owners, err := resolveCodeOwners(e, pr.Issue.Repo, rules.OwnersOfFiles(files))
+	if err != nil {
+		return fmt.Errorf("resolveCodeOwners: %v", err)
+	}
+	for _, owner := range owners {
+		if owner.ID == pr.Issue.PosterID || !owner.IsActive || owner.ProhibitLogin {
+			continue
+		}
+		perm, err := getUserRepoPermission(e, pr.Issue.Repo, owner)
+		if err != nil {
+			return fmt.Errorf("getUserRepoPermission: %v", err)
+		} else if !perm.CanRead(UnitTypePullRequests) {
+			continue
+		}
+
+		// a requested or submitted review satisfies the owner
+		has, err := e.Where("issue_id = ? AND reviewer_id = ? AND type <> ?", pr.IssueID, owner.ID, ReviewTypePending).
+			Exist(new(Review))
+		if err != nil {
+			return err
+		} else if has {
+			continue
+		}
+		if _, err = createReview(e, CreateReviewOptions{
+			Type:     ReviewTypeRequest,
+			Issue:    pr.Issue,
+			Reviewer: owner,
+		}); err != nil {
+			return fmt.Errorf("createReview: %v", err)
+		}
+	}
+	return nil
+}
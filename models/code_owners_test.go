@@ -0,0 +1,118 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOwnerRules_Owners(t *testing.T) {
+	rules := ParseCodeOwners(`# default owners
+*                @user2
+
+*.go             @user4 # Go code
+/docs/           @user5
+apps/            @user8
+/scripts/*.sh    @user10
+**/logs          @user11
+/vendor/         # not owned
+README.md        user1@example.com
+`)
+	assert.Len(t, rules, 8)
+
+	for path, owners := range map[string][]string{
+		"main.go":              {"@user4"},
+		"cmd/serve.go":         {"@user4"},
+		"LICENSE":              {"@user2"},
+		"docs/index.md":        {"@user5"},
+		"docs/api/main.go":     {"@user5"},
+		"src/docs/index.md":    {"@user2"},
+		"apps/app.js":          {"@user8"},
+		"src/apps/app.js":      {"@user8"},
+		"scripts/build.sh":     {"@user10"},
+		"scripts/ci/build.sh":  {"@user2"},
+		"logs/today.log":       {"@user11"},
+		"build/logs/today.log": {"@user11"},
+		"vendor/lib/lib.go":    {},
+		"README.md":            {"user1@example.com"},
+		"docs/README.md":       {"user1@example.com"},
+	} {
+		assert.Equal(t, owners, rules.Owners(path), path)
+	}
+
+	assert.Equal(t, []string{"@user4", "@user5", "@user2"},
+		rules.OwnersOfFiles([]string{"main.go", "docs/index.md", "cmd/serve.go", "LICENSE", "vendor/lib/lib.go"}))
+	assert.Empty(t, ParseCodeOwners("\n# nothing\n").Owners("main.go"))
+}
+
+func TestResolveCodeOwners(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	// team1 of user3 has user2 and user4 as members, user3 and user6 are
+	// organizations
+	users, err := resolveCodeOwners(x, repo, []string{"@user3/team1", "@user3/unknown", "@user6/owners",
+		"@user2", "@user3", "@unknown", "user5@example.com", "unknown@example.com"})
+	assert.NoError(t, err)
+	userIDs := make([]int64, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+	assert.Equal(t, []int64{2, 4, 5}, userIDs)
+
+	// teams are only those of the organization owning the repository
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	users, err = resolveCodeOwners(x, repo, []string{"@user3/team1"})
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestPullRequest_RequestCodeOwnerReviews(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	// the pull request is posted by user1, and reviewed by user1 to user4
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	rules := ParseCodeOwners("* @user2\n*.md @user5 @user1\n/docs/ @user8 user10@example.com\n")
+
+	assertReviewRequests := func(userIDs ...int64) {
+		users, err := GetReviewRequestsByPullID(pr.IssueID)
+		assert.NoError(t, err)
+		var requestedIDs []int64
+		for _, u := range users {
+			requestedIDs = append(requestedIDs, u.ID)
+		}
+		assert.Equal(t, userIDs, requestedIDs)
+	}
+
+	assert.NoError(t, pr.requestCodeOwnerReviews(x, rules, []string{"README.md", "main.go"}))
+	assertReviewRequests(5)
+
+	// a later push changing other files adds their owners
+	assert.NoError(t, pr.requestCodeOwnerReviews(x, rules, []string{"README.md", "main.go", "docs/index.md"}))
+	assertReviewRequests(5, 8, 10)
+	AssertCount(t, &Review{IssueID: pr.IssueID, ReviewerID: 5, Type: ReviewTypeRequest}, 1)
+
+	// submitting the review satisfies the request
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: pr.IssueID}).(*Issue)
+	assert.NoError(t, issue.LoadAttributes())
+	_, err := CreateReview(CreateReviewOptions{
+		Type:     ReviewTypeApprove,
+		Issue:    issue,
+		Reviewer: AssertExistsAndLoadBean(t, &User{ID: 5}).(*User),
+	})
+	assert.NoError(t, err)
+	assertReviewRequests(8, 10)
+
+	assert.NoError(t, pr.requestCodeOwnerReviews(x, rules, []string{"README.md", "docs/index.md"}))
+	assertReviewRequests(8, 10)
+	AssertNotExistsBean(t, &Review{IssueID: pr.IssueID, ReviewerID: 5, Type: ReviewTypeRequest})
+	AssertNotExistsBean(t, &Review{IssueID: pr.IssueID, ReviewerID: 2, Type: ReviewTypeRequest})
+
+	// merged pull requests are not reviewed anymore
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	assert.NoError(t, pr.requestCodeOwnerReviews(x, rules, []string{"README.md"}))
+	assertReviewRequests()
+}
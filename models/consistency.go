@@ -134,6 +134,15 @@ func (milestone *Milestone) checkForConsistency(t *testing.T) {
 	actual := getCount(t, x.Where("is_closed=?", true), &Issue{MilestoneID: milestone.ID})
 	assert.EqualValues(t, milestone.NumClosedIssues, actual,
 		"Unexpected number of closed issues for milestone %+v", milestone)
+
+	points, err := x.Where("milestone_id=?", milestone.ID).SumInt(new(Issue), "points")
+	assert.NoError(t, err)
+	assert.EqualValues(t, milestone.NumPoints, points,
+		"Unexpected number of points for milestone %+v", milestone)
+	closedPoints, err := x.Where("milestone_id=? AND is_closed=?", milestone.ID, true).SumInt(new(Issue), "points")
+	assert.NoError(t, err)
+	assert.EqualValues(t, milestone.NumClosedPoints, closedPoints,
+		"Unexpected number of closed points for milestone %+v", milestone)
 }
 
 func (label *Label) checkForConsistency(t *testing.T) {
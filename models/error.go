@@ -4,7 +4,10 @@
 
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ErrNameReserved represents a "reserved name" error.
 type ErrNameReserved struct {
@@ -483,6 +486,21 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return fmt.Sprintf("access token is empty")
 }
 
+// ErrUserSessionNotExist represents a "UserSessionNotExist" kind of error.
+type ErrUserSessionNotExist struct {
+	ID int64
+}
+
+// IsErrUserSessionNotExist checks if an error is a ErrUserSessionNotExist.
+func IsErrUserSessionNotExist(err error) bool {
+	_, ok := err.(ErrUserSessionNotExist)
+	return ok
+}
+
+func (err ErrUserSessionNotExist) Error() string {
+	return fmt.Sprintf("user session does not exist [id: %d]", err.ID)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -639,6 +657,24 @@ func (err ErrRepoRedirectNotExist) Error() string {
 	return fmt.Sprintf("repository redirect does not exist [uid: %d, name: %s]", err.OwnerID, err.RepoName)
 }
 
+// ErrRepoSizeLimitExceeded represents a "RepoSizeLimitExceeded" kind of error.
+type ErrRepoSizeLimitExceeded struct {
+	RepoID   int64
+	Size     int64
+	PushSize int64
+	Limit    int64
+}
+
+// IsErrRepoSizeLimitExceeded checks if an error is an ErrRepoSizeLimitExceeded.
+func IsErrRepoSizeLimitExceeded(err error) bool {
+	_, ok := err.(ErrRepoSizeLimitExceeded)
+	return ok
+}
+
+func (err ErrRepoSizeLimitExceeded) Error() string {
+	return fmt.Sprintf("repository size limit exceeded [repo_id: %d, size: %d, push_size: %d, limit: %d]", err.RepoID, err.Size, err.PushSize, err.Limit)
+}
+
 // ErrInvalidCloneAddr represents a "InvalidCloneAddr" kind of error.
 type ErrInvalidCloneAddr struct {
 	IsURLError         bool
@@ -657,6 +693,22 @@ func (err ErrInvalidCloneAddr) Error() string {
 		err.IsURLError, err.IsInvalidPath, err.IsPermissionDenied)
 }
 
+// ErrMirrorIntervalTooShort represents a "MirrorIntervalTooShort" kind of error.
+type ErrMirrorIntervalTooShort struct {
+	Interval    time.Duration
+	MinInterval time.Duration
+}
+
+// IsErrMirrorIntervalTooShort checks if an error is a ErrMirrorIntervalTooShort.
+func IsErrMirrorIntervalTooShort(err error) bool {
+	_, ok := err.(ErrMirrorIntervalTooShort)
+	return ok
+}
+
+func (err ErrMirrorIntervalTooShort) Error() string {
+	return fmt.Sprintf("mirror interval is shorter than the minimum [interval: %s, min_interval: %s]", err.Interval, err.MinInterval)
+}
+
 // ErrUpdateTaskNotExist represents a "UpdateTaskNotExist" kind of error.
 type ErrUpdateTaskNotExist struct {
 	UUID string
@@ -672,6 +724,22 @@ func (err ErrUpdateTaskNotExist) Error() string {
 	return fmt.Sprintf("update task does not exist [uuid: %s]", err.UUID)
 }
 
+// ErrNoMergeBase represents a "NoMergeBase" kind of error.
+type ErrNoMergeBase struct {
+	Base string
+	Head string
+}
+
+// IsErrNoMergeBase checks if an error is a ErrNoMergeBase.
+func IsErrNoMergeBase(err error) bool {
+	_, ok := err.(ErrNoMergeBase)
+	return ok
+}
+
+func (err ErrNoMergeBase) Error() string {
+	return fmt.Sprintf("commits have no common ancestor [base: %s, head: %s]", err.Base, err.Head)
+}
+
 // ErrReleaseAlreadyExist represents a "ReleaseAlreadyExist" kind of error.
 type ErrReleaseAlreadyExist struct {
 	TagName string
@@ -718,6 +786,21 @@ func (err ErrInvalidTagName) Error() string {
 	return fmt.Sprintf("release tag name is not valid [tag_name: %s]", err.TagName)
 }
 
+// ErrProtectedTagName represents a "ProtectedTagName" kind of error.
+type ErrProtectedTagName struct {
+	TagName string
+}
+
+// IsErrProtectedTagName checks if an error is a ErrProtectedTagName.
+func IsErrProtectedTagName(err error) bool {
+	_, ok := err.(ErrProtectedTagName)
+	return ok
+}
+
+func (err ErrProtectedTagName) Error() string {
+	return fmt.Sprintf("release tag name is protected [tag_name: %s]", err.TagName)
+}
+
 // ErrRepoFileAlreadyExist represents a "RepoFileAlreadyExist" kind of error.
 type ErrRepoFileAlreadyExist struct {
 	FileName string
@@ -816,6 +899,97 @@ func (err ErrNotAllowedToMerge) Error() string {
 	return fmt.Sprintf("not allowed to merge [reason: %s]", err.Reason)
 }
 
+// ErrInvalidProtectedFilePattern represents an error that a protected file pattern is not a valid glob
+type ErrInvalidProtectedFilePattern struct {
+	Pattern string
+}
+
+// IsErrInvalidProtectedFilePattern checks if an error is an ErrInvalidProtectedFilePattern.
+func IsErrInvalidProtectedFilePattern(err error) bool {
+	_, ok := err.(ErrInvalidProtectedFilePattern)
+	return ok
+}
+
+func (err ErrInvalidProtectedFilePattern) Error() string {
+	return fmt.Sprintf("invalid protected file pattern [pattern: %s]", err.Pattern)
+}
+
+// ErrInvalidWebhookFilterPattern represents an error that a branch or path filter pattern of a webhook is not a valid glob
+type ErrInvalidWebhookFilterPattern struct {
+	Pattern string
+}
+
+// IsErrInvalidWebhookFilterPattern checks if an error is an ErrInvalidWebhookFilterPattern.
+func IsErrInvalidWebhookFilterPattern(err error) bool {
+	_, ok := err.(ErrInvalidWebhookFilterPattern)
+	return ok
+}
+
+func (err ErrInvalidWebhookFilterPattern) Error() string {
+	return fmt.Sprintf("invalid webhook filter pattern [pattern: %s]", err.Pattern)
+}
+
+// ErrInvalidStatusCheckPattern represents an error that a required status check context pattern is not a valid glob
+type ErrInvalidStatusCheckPattern struct {
+	Pattern string
+}
+
+// IsErrInvalidStatusCheckPattern checks if an error is an ErrInvalidStatusCheckPattern.
+func IsErrInvalidStatusCheckPattern(err error) bool {
+	_, ok := err.(ErrInvalidStatusCheckPattern)
+	return ok
+}
+
+func (err ErrInvalidStatusCheckPattern) Error() string {
+	return fmt.Sprintf("invalid status check context pattern [pattern: %s]", err.Pattern)
+}
+
+// ErrInvalidCloseIssuesBranchPattern represents an error that a pattern of the branches closing issues is not a valid glob
+type ErrInvalidCloseIssuesBranchPattern struct {
+	Pattern string
+}
+
+// IsErrInvalidCloseIssuesBranchPattern checks if an error is an ErrInvalidCloseIssuesBranchPattern.
+func IsErrInvalidCloseIssuesBranchPattern(err error) bool {
+	_, ok := err.(ErrInvalidCloseIssuesBranchPattern)
+	return ok
+}
+
+func (err ErrInvalidCloseIssuesBranchPattern) Error() string {
+	return fmt.Sprintf("invalid close issues branch pattern [pattern: %s]", err.Pattern)
+}
+
+// ErrInvalidProtectedTagPattern represents an error that a protected tag pattern is not a valid glob
+type ErrInvalidProtectedTagPattern struct {
+	Pattern string
+}
+
+// IsErrInvalidProtectedTagPattern checks if an error is an ErrInvalidProtectedTagPattern.
+func IsErrInvalidProtectedTagPattern(err error) bool {
+	_, ok := err.(ErrInvalidProtectedTagPattern)
+	return ok
+}
+
+func (err ErrInvalidProtectedTagPattern) Error() string {
+	return fmt.Sprintf("invalid protected tag pattern [pattern: %s]", err.Pattern)
+}
+
+// ErrProtectedTagNotExist represents a "ProtectedTagNotExist" kind of error.
+type ErrProtectedTagNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrProtectedTagNotExist checks if an error is an ErrProtectedTagNotExist.
+func IsErrProtectedTagNotExist(err error) bool {
+	_, ok := err.(ErrProtectedTagNotExist)
+	return ok
+}
+
+func (err ErrProtectedTagNotExist) Error() string {
+	return fmt.Sprintf("protected tag does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
 // ErrTagAlreadyExists represents an error that tag with such name already exists
 type ErrTagAlreadyExists struct {
 	TagName string
@@ -853,6 +1027,22 @@ func (err ErrWebhookNotExist) Error() string {
 	return fmt.Sprintf("webhook does not exist [id: %d]", err.ID)
 }
 
+// ErrHookTaskNotExist represents a "HookTaskNotExist" kind of error.
+type ErrHookTaskNotExist struct {
+	ID     int64
+	HookID int64
+}
+
+// IsErrHookTaskNotExist checks if an error is a ErrHookTaskNotExist.
+func IsErrHookTaskNotExist(err error) bool {
+	_, ok := err.(ErrHookTaskNotExist)
+	return ok
+}
+
+func (err ErrHookTaskNotExist) Error() string {
+	return fmt.Sprintf("hook task does not exist [id: %d, hook_id: %d]", err.ID, err.HookID)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -877,6 +1067,68 @@ func (err ErrIssueNotExist) Error() string {
 	return fmt.Sprintf("issue does not exist [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
 }
 
+// ErrInvalidIssueSearchQuery represents a "InvalidIssueSearchQuery" kind of error.
+type ErrInvalidIssueSearchQuery struct {
+	Query  string
+	Reason string
+}
+
+// IsErrInvalidIssueSearchQuery checks if an error is a ErrInvalidIssueSearchQuery.
+func IsErrInvalidIssueSearchQuery(err error) bool {
+	_, ok := err.(ErrInvalidIssueSearchQuery)
+	return ok
+}
+
+func (err ErrInvalidIssueSearchQuery) Error() string {
+	return fmt.Sprintf("invalid issue search query: %s [query: %s]", err.Reason, err.Query)
+}
+
+// ErrIssueCannotBeMoved represents a "IssueCannotBeMoved" kind of error.
+type ErrIssueCannotBeMoved struct {
+	ID           int64
+	TargetRepoID int64
+}
+
+// IsErrIssueCannotBeMoved checks if an error is a ErrIssueCannotBeMoved.
+func IsErrIssueCannotBeMoved(err error) bool {
+	_, ok := err.(ErrIssueCannotBeMoved)
+	return ok
+}
+
+func (err ErrIssueCannotBeMoved) Error() string {
+	return fmt.Sprintf("issue cannot be moved to repository [id: %d, target_repo_id: %d]", err.ID, err.TargetRepoID)
+}
+
+// ErrIssueMigrationNotExist represents a "IssueMigrationNotExist" kind of error.
+type ErrIssueMigrationNotExist struct {
+	RepoID int64
+}
+
+// IsErrIssueMigrationNotExist checks if an error is a ErrIssueMigrationNotExist.
+func IsErrIssueMigrationNotExist(err error) bool {
+	_, ok := err.(ErrIssueMigrationNotExist)
+	return ok
+}
+
+func (err ErrIssueMigrationNotExist) Error() string {
+	return fmt.Sprintf("issue migration does not exist [repo_id: %d]", err.RepoID)
+}
+
+// ErrIssueMigrationNotFailed represents a "IssueMigrationNotFailed" kind of error.
+type ErrIssueMigrationNotFailed struct {
+	RepoID int64
+}
+
+// IsErrIssueMigrationNotFailed checks if an error is a ErrIssueMigrationNotFailed.
+func IsErrIssueMigrationNotFailed(err error) bool {
+	_, ok := err.(ErrIssueMigrationNotFailed)
+	return ok
+}
+
+func (err ErrIssueMigrationNotFailed) Error() string {
+	return fmt.Sprintf("issue migration has not failed [repo_id: %d]", err.RepoID)
+}
+
 // __________      .__  .__ __________                                     __
 // \______   \__ __|  | |  |\______   \ ____  ________ __   ____   _______/  |_
 //  |     ___/  |  \  | |  | |       _// __ \/ ____/  |  \_/ __ \ /  ___/\   __\
@@ -944,6 +1196,52 @@ func (err ErrInvalidMergeStyle) Error() string {
 		err.ID, err.Style)
 }
 
+// ErrUnknownMergeMessagePlaceholder represents an error if a merge message
+// template uses an unknown placeholder
+type ErrUnknownMergeMessagePlaceholder struct {
+	Name string
+}
+
+// IsErrUnknownMergeMessagePlaceholder checks if an error is a ErrUnknownMergeMessagePlaceholder.
+func IsErrUnknownMergeMessagePlaceholder(err error) bool {
+	_, ok := err.(ErrUnknownMergeMessagePlaceholder)
+	return ok
+}
+
+func (err ErrUnknownMergeMessagePlaceholder) Error() string {
+	return fmt.Sprintf("unknown merge message placeholder [name: %s]", err.Name)
+}
+
+// ErrPullRequestIsDraft represents an error if merging a draft pull request
+type ErrPullRequestIsDraft struct {
+	ID int64
+}
+
+// IsErrPullRequestIsDraft checks if an error is a ErrPullRequestIsDraft.
+func IsErrPullRequestIsDraft(err error) bool {
+	_, ok := err.(ErrPullRequestIsDraft)
+	return ok
+}
+
+func (err ErrPullRequestIsDraft) Error() string {
+	return fmt.Sprintf("pull request is a draft [id: %d]", err.ID)
+}
+
+// ErrPullRequestNotOpen represents an error if changing a closed or merged pull request
+type ErrPullRequestNotOpen struct {
+	ID int64
+}
+
+// IsErrPullRequestNotOpen checks if an error is a ErrPullRequestNotOpen.
+func IsErrPullRequestNotOpen(err error) bool {
+	_, ok := err.(ErrPullRequestNotOpen)
+	return ok
+}
+
+func (err ErrPullRequestNotOpen) Error() string {
+	return fmt.Sprintf("pull request is closed or merged [id: %d]", err.ID)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -1080,6 +1378,22 @@ func (err ErrAttachmentNotExist) Error() string {
 	return fmt.Sprintf("attachment does not exist [id: %d, uuid: %s]", err.ID, err.UUID)
 }
 
+// ErrReleaseAttachmentAlreadyExist represents a "ReleaseAttachmentAlreadyExist" kind of error.
+type ErrReleaseAttachmentAlreadyExist struct {
+	ReleaseID int64
+	Name      string
+}
+
+// IsErrReleaseAttachmentAlreadyExist checks if an error is a ErrReleaseAttachmentAlreadyExist.
+func IsErrReleaseAttachmentAlreadyExist(err error) bool {
+	_, ok := err.(ErrReleaseAttachmentAlreadyExist)
+	return ok
+}
+
+func (err ErrReleaseAttachmentAlreadyExist) Error() string {
+	return fmt.Sprintf("release attachment already exists [release_id: %d, name: %s]", err.ReleaseID, err.Name)
+}
+
 // .____                 .__           _________
 // |    |    ____   ____ |__| ____    /   _____/ ____  __ _________   ____  ____
 // |    |   /  _ \ / ___\|  |/    \   \_____  \ /  _ \|  |  \_  __ \_/ ___\/ __ \
@@ -1174,6 +1488,23 @@ func (err ErrTwoFactorNotEnrolled) Error() string {
 	return fmt.Sprintf("user not enrolled in 2FA [uid: %d]", err.UID)
 }
 
+// ErrTwoFactorRequired indicates that an organization requires two-factor
+// authentication the user has not enabled.
+type ErrTwoFactorRequired struct {
+	OrgID int64
+	UID   int64
+}
+
+// IsErrTwoFactorRequired checks if an error is a ErrTwoFactorRequired.
+func IsErrTwoFactorRequired(err error) bool {
+	_, ok := err.(ErrTwoFactorRequired)
+	return ok
+}
+
+func (err ErrTwoFactorRequired) Error() string {
+	return fmt.Sprintf("organization requires 2FA the user has not enabled [org_id: %d, uid: %d]", err.OrgID, err.UID)
+}
+
 //  ____ ___        .__                    .___
 // |    |   \______ |  |   _________     __| _/
 // |    |   /\____ \|  |  /  _ \__  \   / __ |
@@ -1238,6 +1569,22 @@ func (err ErrExternalLoginUserNotExist) Error() string {
 	return fmt.Sprintf("external login user link does not exists [userID: %d, loginSourceID: %d]", err.UserID, err.LoginSourceID)
 }
 
+// ErrExternalLoginUserNoRefreshToken represents a "ExternalLoginUserNoRefreshToken" kind of error.
+type ErrExternalLoginUserNoRefreshToken struct {
+	UserID        int64
+	LoginSourceID int64
+}
+
+// IsErrExternalLoginUserNoRefreshToken checks if an error is a ExternalLoginUserNoRefreshToken.
+func IsErrExternalLoginUserNoRefreshToken(err error) bool {
+	_, ok := err.(ErrExternalLoginUserNoRefreshToken)
+	return ok
+}
+
+func (err ErrExternalLoginUserNoRefreshToken) Error() string {
+	return fmt.Sprintf("external login user link has no refresh token [userID: %d, loginSourceID: %d]", err.UserID, err.LoginSourceID)
+}
+
 // ____ ________________________________              .__          __                 __  .__
 // |    |   \_____  \_   _____/\______   \ ____   ____ |__| _______/  |_____________ _/  |_|__| ____   ____
 // |    |   //  ____/|    __)   |       _// __ \ / ___\|  |/  ___/\   __\_  __ \__  \\   __\  |/  _ \ /    \
@@ -1312,7 +1659,7 @@ func IsErrCircularDependency(err error) bool {
 }
 
 func (err ErrCircularDependency) Error() string {
-	return fmt.Sprintf("circular dependencies exists (two issues blocking each other) [issue id: %d, dependency id: %d]", err.IssueID, err.DependencyID)
+	return fmt.Sprintf("circular dependencies exists (issues blocking each other) [issue id: %d, dependency id: %d]", err.IssueID, err.DependencyID)
 }
 
 // ErrDependenciesLeft represents an error where the issue you're trying to close still has dependencies left.
@@ -4,13 +4,82 @@
 
 package models
 
-import "github.com/markbates/goth"
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/markbates/goth"
+)
 
 // ExternalLoginUser makes the connecting between some existing user and additional external login sources
 type ExternalLoginUser struct {
 	ExternalID    string `xorm:"pk NOT NULL"`
 	UserID        int64  `xorm:"INDEX NOT NULL"`
 	LoginSourceID int64  `xorm:"pk NOT NULL"`
+	// AccessToken and RefreshToken are encrypted
+	AccessToken  string `xorm:"TEXT"`
+	RefreshToken string `xorm:"TEXT"`
+	ExpiresUnix  util.TimeStamp
+}
+
+func (externalLoginUser *ExternalLoginUser) getEncryptionKey() []byte {
+	k := md5.Sum([]byte(setting.SecretKey))
+	return k[:]
+}
+
+func (externalLoginUser *ExternalLoginUser) encryptToken(token string) (string, error) {
+	if len(token) == 0 {
+		return "", nil
+	}
+	tokenBytes, err := aesEncrypt(externalLoginUser.getEncryptionKey(), []byte(token))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(tokenBytes), nil
+}
+
+func (externalLoginUser *ExternalLoginUser) decryptToken(encryptedToken string) (string, error) {
+	if len(encryptedToken) == 0 {
+		return "", nil
+	}
+	decodedToken, err := base64.StdEncoding.DecodeString(encryptedToken)
+	if err != nil {
+		return "", err
+	}
+	token, err := aesDecrypt(externalLoginUser.getEncryptionKey(), decodedToken)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// GetAccessToken returns the decrypted access token the user got from the provider.
+func (externalLoginUser *ExternalLoginUser) GetAccessToken() (string, error) {
+	return externalLoginUser.decryptToken(externalLoginUser.AccessToken)
+}
+
+// GetRefreshToken returns the decrypted refresh token the user got from the provider.
+func (externalLoginUser *ExternalLoginUser) GetRefreshToken() (string, error) {
+	return externalLoginUser.decryptToken(externalLoginUser.RefreshToken)
+}
+
+func (externalLoginUser *ExternalLoginUser) setTokens(gothUser goth.User) (err error) {
+	if externalLoginUser.AccessToken, err = externalLoginUser.encryptToken(gothUser.AccessToken); err != nil {
+		return err
+	}
+	if externalLoginUser.RefreshToken, err = externalLoginUser.encryptToken(gothUser.RefreshToken); err != nil {
+		return err
+	}
+	externalLoginUser.ExpiresUnix = 0
+	if !gothUser.ExpiresAt.IsZero() {
+		externalLoginUser.ExpiresUnix = util.TimeStamp(gothUser.ExpiresAt.Unix())
+	}
+	return nil
 }
 
 // GetExternalLogin checks if a externalID in loginSourceID scope already exists
@@ -51,10 +120,55 @@ func LinkAccountToUser(user *User, gothUser goth.User) error {
 		return ErrExternalLoginUserAlreadyExist{gothUser.UserID, user.ID, loginSource.ID}
 	}
 
+	if err = externalLoginUser.setTokens(gothUser); err != nil {
+		return err
+	}
 	_, err = x.Insert(externalLoginUser)
 	return err
 }
 
+// UpdateExternalLoginUserTokens stores the tokens the user got from the provider on login
+func UpdateExternalLoginUserTokens(externalLoginUser *ExternalLoginUser, gothUser goth.User) error {
+	if err := externalLoginUser.setTokens(gothUser); err != nil {
+		return err
+	}
+	_, err := x.
+		Where("external_id = ? AND login_source_id = ?", externalLoginUser.ExternalID, externalLoginUser.LoginSourceID).
+		Cols("access_token", "refresh_token", "expires_unix").
+		Update(externalLoginUser)
+	return err
+}
+
+// RefreshExternalLoginUserToken requests new tokens from the provider using
+// the stored refresh token, and stores them. The returned user holds the
+// claims of the new ID token of an OpenID Connect provider, if it issued one.
+func RefreshExternalLoginUserToken(externalLoginUser *ExternalLoginUser) (goth.User, error) {
+	refreshToken, err := externalLoginUser.GetRefreshToken()
+	if err != nil {
+		return goth.User{}, err
+	}
+	if len(refreshToken) == 0 {
+		return goth.User{}, ErrExternalLoginUserNoRefreshToken{externalLoginUser.UserID, externalLoginUser.LoginSourceID}
+	}
+
+	loginSource, err := GetLoginSourceByID(externalLoginUser.LoginSourceID)
+	if err != nil {
+		return goth.User{}, err
+	}
+	if err = RegisterOAuth2ProviderIfMissing(loginSource); err != nil {
+		return goth.User{}, err
+	}
+
+	gothUser, err := oauth2.RefreshUser(loginSource.Name, refreshToken)
+	if err != nil {
+		return goth.User{}, err
+	}
+	if gothUser.RawData != nil && gothUser.UserID != externalLoginUser.ExternalID {
+		return goth.User{}, fmt.Errorf("refreshed ID token is issued for another user: %s", gothUser.UserID)
+	}
+	return gothUser, UpdateExternalLoginUserTokens(externalLoginUser, gothUser)
+}
+
 // RemoveAccountLink will remove all external login sources for the given user
 func RemoveAccountLink(user *User, loginSourceID int64) (int64, error) {
 	deleted, err := x.Delete(&ExternalLoginUser{UserID: user.ID, LoginSourceID: loginSourceID})
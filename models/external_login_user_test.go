@@ -0,0 +1,84 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/auth/oauth2"
+
+	"github.com/markbates/goth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshExternalLoginUserToken(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh", r.PostForm.Get("refresh_token"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "new-access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+
+	source := &LoginSource{
+		Type:      LoginOAuth2,
+		Name:      "gitlab",
+		IsActived: true,
+		Cfg: &OAuth2Config{
+			Provider: "gitlab",
+			CustomURLMapping: &oauth2.CustomURLMapping{
+				AuthURL:    server.URL + "/authorize",
+				TokenURL:   server.URL + "/token",
+				ProfileURL: server.URL + "/user",
+			},
+		},
+	}
+	_, err := x.Insert(source)
+	assert.NoError(t, err)
+	defer oauth2.RemoveProvider(source.Name)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, LinkAccountToUser(user, goth.User{
+		Provider:     source.Name,
+		UserID:       "1234",
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}))
+
+	// the tokens are stored encrypted
+	externalLoginUser := AssertExistsAndLoadBean(t, &ExternalLoginUser{ExternalID: "1234", LoginSourceID: source.ID}).(*ExternalLoginUser)
+	assert.NotEqual(t, "access", externalLoginUser.AccessToken)
+	assert.NotEqual(t, "refresh", externalLoginUser.RefreshToken)
+	accessToken, err := externalLoginUser.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "access", accessToken)
+
+	_, err = RefreshExternalLoginUserToken(externalLoginUser)
+	assert.NoError(t, err)
+	externalLoginUser = AssertExistsAndLoadBean(t, &ExternalLoginUser{ExternalID: "1234", LoginSourceID: source.ID}).(*ExternalLoginUser)
+	accessToken, err = externalLoginUser.GetAccessToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "new-access", accessToken)
+	// the provider didn't issue a new refresh token
+	refreshToken, err := externalLoginUser.GetRefreshToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", refreshToken)
+	assert.True(t, externalLoginUser.ExpiresUnix.AsTime().After(time.Now()))
+
+	assert.NoError(t, UpdateExternalLoginUserTokens(externalLoginUser, goth.User{AccessToken: "access"}))
+	_, err = RefreshExternalLoginUserToken(externalLoginUser)
+	assert.True(t, IsErrExternalLoginUserNoRefreshToken(err))
+}
@@ -0,0 +1,207 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/process"
+)
+
+// BlameCommit represents the commit a range of lines of a blame is attributed to.
+type BlameCommit struct {
+	SHA            string
+	AuthorName     string
+	AuthorEmail    string
+	AuthorTime     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterTime  time.Time
+	Summary        string
+}
+
+// BlameRange represents consecutive lines of a file last changed by the same commit.
+type BlameRange struct {
+	Commit    *BlameCommit
+	StartLine int
+	LineCount int
+}
+
+// parseBlameSignatureTime parses the unix time and the timezone of
+// git blame porcelain headers, e.g. "1502042309" and "+0200".
+func parseBlameSignatureTime(unix, tz string) time.Time {
+	sec, _ := strconv.ParseInt(unix, 10, 64)
+	t := time.Unix(sec, 0)
+	if len(tz) != 5 {
+		return t
+	}
+	hours, err1 := strconv.Atoi(tz[1:3])
+	minutes, err2 := strconv.Atoi(tz[3:])
+	if err1 != nil || err2 != nil {
+		return t
+	}
+	offset := hours*3600 + minutes*60
+	if tz[0] == '-' {
+		offset = -offset
+	}
+	return t.In(time.FixedZone("", offset))
+}
+
+// ParseBlamePorcelain parses the output of git blame --porcelain into ranges
+// of lines. The content of the lines is skipped without being kept in memory.
+func ParseBlamePorcelain(reader io.Reader) ([]*BlameRange, error) {
+	var (
+		ranges  []*BlameRange
+		commits = make(map[string]*BlameCommit)
+		current *BlameCommit
+		line    int
+
+		authorTime, authorTZ       string
+		committerTime, committerTZ string
+
+		input      = bufio.NewReader(reader)
+		isSkipping bool
+	)
+
+	for {
+		data, isPrefix, err := input.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("ReadLine: %v", err)
+		}
+
+		// Continuation of a line which is too long for the buffer,
+		// only lines of the content of the file can be that long.
+		if isSkipping {
+			isSkipping = isPrefix
+			continue
+		}
+		isSkipping = isPrefix
+
+		if current == nil {
+			fields := strings.Fields(string(data))
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid blame header: %q", data)
+			}
+			if line, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("invalid blame line number: %q", data)
+			}
+			current = commits[fields[0]]
+			if current == nil {
+				current = &BlameCommit{SHA: fields[0]}
+				commits[current.SHA] = current
+			}
+			authorTime, authorTZ, committerTime, committerTZ = "", "", "", ""
+			continue
+		}
+
+		if len(data) > 0 && data[0] == '\t' {
+			if authorTime != "" {
+				current.AuthorTime = parseBlameSignatureTime(authorTime, authorTZ)
+			}
+			if committerTime != "" {
+				current.CommitterTime = parseBlameSignatureTime(committerTime, committerTZ)
+			}
+
+			last := len(ranges) - 1
+			if last >= 0 && ranges[last].Commit == current && ranges[last].StartLine+ranges[last].LineCount == line {
+				ranges[last].LineCount++
+			} else {
+				ranges = append(ranges, &BlameRange{
+					Commit:    current,
+					StartLine: line,
+					LineCount: 1,
+				})
+			}
+			current = nil
+			continue
+		}
+
+		var value string
+		key := string(data)
+		if i := bytes.IndexByte(data, ' '); i >= 0 {
+			key, value = string(data[:i]), string(data[i+1:])
+		}
+		switch key {
+		case "author":
+			current.AuthorName = value
+		case "author-mail":
+			current.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			authorTime = value
+		case "author-tz":
+			authorTZ = value
+		case "committer":
+			current.CommitterName = value
+		case "committer-mail":
+			current.CommitterEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "committer-time":
+			committerTime = value
+		case "committer-tz":
+			committerTZ = value
+		case "summary":
+			current.Summary = value
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unexpected end of blame of commit %s", current.SHA)
+	}
+	return ranges, nil
+}
+
+// blameTimeout bounds the run time of git blame, which can be long for files
+// with a long history.
+var blameTimeout = 60 * time.Second
+
+// GetBlame returns the blame of the file at the given commit of the repository.
+// The output of git blame is parsed as it is streamed.
+func GetBlame(repoPath, commitID, file string) ([]*BlameRange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), blameTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "blame", "--porcelain", commitID, "--", file)
+	cmd.Dir = repoPath
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdoutPipe: %v", err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Start: %v", err)
+	}
+
+	pid := process.GetManager().Add(fmt.Sprintf("GetBlame [repo_path: %s]", repoPath), cmd)
+	defer process.GetManager().Remove(pid)
+
+	ranges, err := ParseBlamePorcelain(stdout)
+	if err != nil {
+		// git blame is killed by the timeout or stopped as its output is invalid
+		timeoutErr := ctx.Err()
+		cancel()
+		cmd.Wait()
+		if timeoutErr != nil {
+			return nil, fmt.Errorf("git blame: %v", timeoutErr)
+		}
+		return nil, fmt.Errorf("ParseBlamePorcelain: %v", err)
+	}
+
+	if err = cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git blame: %v(%v) stderr: %s", err, ctx.Err(), stderr)
+	}
+	return ranges, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const samplePorcelainBlame = `aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 1 1 2
+author Alice
+author-mail <alice@example.com>
+author-time 1500000000
+author-tz +0200
+committer Bob
+committer-mail <bob@example.com>
+committer-time 1500000100
+committer-tz -0130
+summary Initial commit
+boundary
+filename README.md
+	# title
+aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 2 2
+	
+bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb 2 3 1
+author Bob
+author-mail <bob@example.com>
+author-time 1500001000
+author-tz +0000
+committer Bob
+committer-mail <bob@example.com>
+committer-time 1500001000
+committer-tz +0000
+summary Add description
+previous aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa README.md
+filename README.md
+	LONGLINE
+aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 4 4 1
+filename README.md
+	footer
+`
+
+func TestParseBlamePorcelain(t *testing.T) {
+	// a content line longer than the read buffer is skipped
+	ranges, err := ParseBlamePorcelain(strings.NewReader(strings.Replace(samplePorcelainBlame, "LONGLINE", strings.Repeat("x", 10000), 1)))
+	assert.NoError(t, err)
+	if !assert.Len(t, ranges, 3) {
+		return
+	}
+
+	initial := ranges[0].Commit
+	assert.Equal(t, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", initial.SHA)
+	assert.Equal(t, "Alice", initial.AuthorName)
+	assert.Equal(t, "alice@example.com", initial.AuthorEmail)
+	assert.Equal(t, "Bob", initial.CommitterName)
+	assert.Equal(t, "bob@example.com", initial.CommitterEmail)
+	assert.Equal(t, "Initial commit", initial.Summary)
+	assert.True(t, time.Unix(1500000000, 0).Equal(initial.AuthorTime))
+	_, offset := initial.AuthorTime.Zone()
+	assert.Equal(t, 7200, offset)
+	_, offset = initial.CommitterTime.Zone()
+	assert.Equal(t, -5400, offset)
+
+	assert.Equal(t, 1, ranges[0].StartLine)
+	assert.Equal(t, 2, ranges[0].LineCount)
+	assert.Equal(t, "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", ranges[1].Commit.SHA)
+	assert.Equal(t, "Add description", ranges[1].Commit.Summary)
+	assert.Equal(t, 3, ranges[1].StartLine)
+	assert.Equal(t, 1, ranges[1].LineCount)
+	// the info of a commit is given once and shared by its ranges
+	assert.True(t, ranges[2].Commit == initial)
+	assert.Equal(t, 4, ranges[2].StartLine)
+	assert.Equal(t, 1, ranges[2].LineCount)
+
+	_, err = ParseBlamePorcelain(strings.NewReader("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 1 1 1\nauthor Alice\n"))
+	assert.Error(t, err)
+	_, err = ParseBlamePorcelain(strings.NewReader("invalid\n"))
+	assert.Error(t, err)
+}
+
+func TestGetBlame(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	ranges, err := GetBlame(repo.RepoPath(), "69554a64c1e6030f051e5c3f94bfbd773cd6a324", "readme.md")
+	assert.NoError(t, err)
+	if assert.Len(t, ranges, 1) {
+		assert.Equal(t, 1, ranges[0].StartLine)
+		assert.Equal(t, 1, ranges[0].LineCount)
+		assert.Equal(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", ranges[0].Commit.SHA)
+		assert.Equal(t, "user2@example.com", ranges[0].Commit.AuthorEmail)
+	}
+
+	_, err = GetBlame(repo.RepoPath(), "69554a64c1e6030f051e5c3f94bfbd773cd6a324", "unknown.md")
+	if assert.Error(t, err) {
+		// the error of git is reported
+		assert.Contains(t, err.Error(), "no such path")
+	}
+
+	// git blame is killed once it runs for too long
+	defer func(d time.Duration) { blameTimeout = d }(blameTimeout)
+	blameTimeout = 0
+	_, err = GetBlame(repo.RepoPath(), "69554a64c1e6030f051e5c3f94bfbd773cd6a324", "readme.md")
+	assert.Error(t, err)
+}
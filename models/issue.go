@@ -37,6 +37,7 @@ type Issue struct {
 	MilestoneID     int64       `xorm:"INDEX"`
 	Milestone       *Milestone  `xorm:"-"`
 	Priority        int
+	Points          int          `xorm:"NOT NULL DEFAULT 0"` // Estimate, 0 for not estimated.
 	AssigneeID      int64        `xorm:"-"`
 	Assignee        *User        `xorm:"-"`
 	IsClosed        bool         `xorm:"INDEX"`
@@ -514,6 +515,34 @@ func (issue *Issue) RemoveLabel(doer *User, label *Label) error {
 	return nil
 }
 
+// UpdateLabels adds and removes the given labels of the issue within one
+// transaction, creating the usual label comments, and triggers the
+// WebHooks once all changes are committed.
+func (issue *Issue) UpdateLabels(doer *User, toAdd, toRemove []*Label) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = issue.addLabels(sess, toAdd, doer); err != nil {
+		return fmt.Errorf("addLabels: %v", err)
+	}
+
+	for _, l := range toRemove {
+		if err = issue.removeLabel(sess, doer, l); err != nil {
+			return fmt.Errorf("removeLabel: %v", err)
+		}
+	}
+
+	if err = sess.Commit(); err != nil {
+		return fmt.Errorf("Commit: %v", err)
+	}
+
+	issue.sendLabelUpdatedWebhook(doer)
+	return nil
+}
+
 func (issue *Issue) clearLabels(e *xorm.Session, doer *User) (err error) {
 	if err = issue.getLabels(e); err != nil {
 		return fmt.Errorf("getLabels: %v", err)
@@ -805,6 +834,38 @@ func (issue *Issue) ChangeStatus(doer *User, isClosed bool) (err error) {
 	return nil
 }
 
+// ChangePoints changes the points of this issue, and those of its milestone.
+func (issue *Issue) ChangePoints(points int) (err error) {
+	oldPoints := issue.Points
+	issue.Points = points
+	sess := x.NewSession()
+	defer sess.Close()
+
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = updateIssueCols(sess, issue, "points"); err != nil {
+		return fmt.Errorf("updateIssueCols: %v", err)
+	}
+
+	if issue.MilestoneID > 0 {
+		m, err := getMilestoneByRepoID(sess, issue.RepoID, issue.MilestoneID)
+		if err != nil {
+			return err
+		}
+		m.NumPoints += points - oldPoints
+		if issue.IsClosed {
+			m.NumClosedPoints += points - oldPoints
+		}
+		if err = updateMilestone(sess, m); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
 // ChangeTitle changes the title of this issue, as the given user.
 func (issue *Issue) ChangeTitle(doer *User, title string) (err error) {
 	oldTitle := issue.Title
@@ -1214,6 +1275,10 @@ type IssuesOptions struct {
 	Labels      string
 	SortType    string
 	IssueIDs    []int64
+	// InvolvedID restricts the issues to the ones the user is involved in
+	// by any of the ways of Involvement.
+	InvolvedID  int64
+	Involvement IssueInvolvement
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
@@ -1283,6 +1348,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) error {
 		sess.And("issue.milestone_id=?", opts.MilestoneID)
 	}
 
+	if opts.InvolvedID > 0 && opts.Involvement != 0 {
+		sess.And(involvementCond(opts.InvolvedID, opts.Involvement))
+	}
+
 	switch opts.IsPull {
 	case util.OptionalBoolTrue:
 		sess.And("issue.is_pull=?", true)
@@ -1458,6 +1527,8 @@ type IssueStatsOptions struct {
 	PosterID    int64
 	IsPull      util.OptionalBool
 	IssueIDs    []int64
+	InvolvedID  int64
+	Involvement IssueInvolvement
 }
 
 // GetIssueStats returns issue statistic information by given conditions.
@@ -1501,6 +1572,10 @@ func GetIssueStats(opts *IssueStatsOptions) (*IssueStats, error) {
 				And("issue_user.is_mentioned = ?", true)
 		}
 
+		if opts.InvolvedID > 0 && opts.Involvement != 0 {
+			sess.And(involvementCond(opts.InvolvedID, opts.Involvement))
+		}
+
 		switch opts.IsPull {
 		case util.OptionalBoolTrue:
 			sess.And("issue.is_pull=?", true)
@@ -80,6 +80,8 @@ const (
 	CommentTypeCode
 	// Reviews a pull request by giving general feedback
 	CommentTypeReview
+	// Issue moved to another repository
+	CommentTypeIssueMoved
 )
 
 // CommentTag defines comment tag type
@@ -46,7 +46,7 @@ func CreateIssueDependency(user *User, issue, dep *Issue) error {
 		return ErrDependencyExists{issue.ID, dep.ID}
 	}
 	// And if it would be circular
-	circular, err := issueDepExists(sess, dep.ID, issue.ID)
+	circular, err := issueDepCreatesCycle(sess, issue.ID, dep.ID)
 	if err != nil {
 		return err
 	}
@@ -111,6 +111,32 @@ func issueDepExists(e Engine, issueID int64, depID int64) (bool, error) {
 	return e.Where("(issue_id = ? AND dependency_id = ?)", issueID, depID).Exist(&IssueDependency{})
 }
 
+// issueDepCreatesCycle checks if making issueID depend on depID would close a
+// cycle, that is if issueID can already be reached by following the
+// dependencies of depID, however long the chain is.
+func issueDepCreatesCycle(e Engine, issueID, depID int64) (bool, error) {
+	visited := map[int64]bool{depID: true}
+	queue := []int64{depID}
+	for len(queue) > 0 {
+		var deps []*IssueDependency
+		if err := e.In("issue_id", queue).Find(&deps); err != nil {
+			return false, err
+		}
+
+		queue = queue[:0]
+		for _, dep := range deps {
+			if dep.DependencyID == issueID {
+				return true, nil
+			}
+			if !visited[dep.DependencyID] {
+				visited[dep.DependencyID] = true
+				queue = append(queue, dep.DependencyID)
+			}
+		}
+	}
+	return false, nil
+}
+
 // IssueNoDependenciesLeft checks if issue can be closed
 func IssueNoDependenciesLeft(issue *Issue) (bool, error) {
 	return issueNoDependenciesLeft(x, issue)
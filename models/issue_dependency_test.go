@@ -58,3 +58,32 @@ func TestCreateIssueDependency(t *testing.T) {
 	err = RemoveIssueDependency(user1, issue1, issue2, DependencyTypeBlockedBy)
 	assert.NoError(t, err)
 }
+
+func TestCreateIssueDependency_Cycle(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issueA := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issueB := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	issueC := AssertExistsAndLoadBean(t, &Issue{ID: 3}).(*Issue)
+	issueD := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	for _, issue := range []*Issue{issueA, issueB, issueC, issueD} {
+		assert.NoError(t, issue.LoadAttributes())
+	}
+
+	// A is blocked by B, which is blocked by C
+	assert.NoError(t, CreateIssueDependency(user1, issueA, issueB))
+	assert.NoError(t, CreateIssueDependency(user1, issueB, issueC))
+
+	// C cannot be blocked by A, neither directly nor through D
+	err := CreateIssueDependency(user1, issueC, issueA)
+	assert.True(t, IsErrCircularDependency(err))
+	assert.NoError(t, CreateIssueDependency(user1, issueD, issueA))
+	err = CreateIssueDependency(user1, issueC, issueD)
+	assert.True(t, IsErrCircularDependency(err))
+	AssertNotExistsBean(t, &IssueDependency{IssueID: issueC.ID})
+
+	// Links that do not close a loop are allowed
+	assert.NoError(t, CreateIssueDependency(user1, issueA, issueC))
+	assert.NoError(t, CreateIssueDependency(user1, issueD, issueC))
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"github.com/go-xorm/builder"
+)
+
+// IssueInvolvement is a set of the ways a user is involved in an issue
+type IssueInvolvement int
+
+// enumerates all the ways a user is involved in an issue
+const (
+	// IssueInvolvementAuthor the user has created the issue
+	IssueInvolvementAuthor IssueInvolvement = 1 << iota
+	// IssueInvolvementAssignee the user is assigned to the issue
+	IssueInvolvementAssignee
+	// IssueInvolvementMentioned the user is mentioned in the issue
+	IssueInvolvementMentioned
+	// IssueInvolvementReviewer the user has reviewed the pull request
+	IssueInvolvementReviewer
+	// IssueInvolvementReviewRequested the review of the pull request is requested from the user
+	IssueInvolvementReviewRequested
+
+	// IssueInvolvementAny the user is involved in any way
+	IssueInvolvementAny = IssueInvolvementAuthor | IssueInvolvementAssignee | IssueInvolvementMentioned |
+		IssueInvolvementReviewer | IssueInvolvementReviewRequested
+)
+
+// issueInvolvementQualifiers maps the search qualifiers to the involvement they filter by
+var issueInvolvementQualifiers = map[string]IssueInvolvement{
+	"author":           IssueInvolvementAuthor,
+	"assignee":         IssueInvolvementAssignee,
+	"mentions":         IssueInvolvementMentioned,
+	"reviewed-by":      IssueInvolvementReviewer,
+	"review-requested": IssueInvolvementReviewRequested,
+	"involves":         IssueInvolvementAny,
+}
+
+// involvementCond returns the condition matching the issues userID is involved
+// in by any of the given ways. Subqueries rather than joins are used, so every
+// issue is matched once, using the indexed user column of each table.
+func involvementCond(userID int64, involvement IssueInvolvement) builder.Cond {
+	cond := builder.NewCond()
+	if involvement&IssueInvolvementAuthor != 0 {
+		cond = cond.Or(builder.Eq{"issue.poster_id": userID})
+	}
+	if involvement&IssueInvolvementAssignee != 0 {
+		cond = cond.Or(builder.In("issue.id", builder.Select("issue_id").From("issue_assignees").
+			Where(builder.Eq{"assignee_id": userID})))
+	}
+	if involvement&IssueInvolvementMentioned != 0 {
+		cond = cond.Or(builder.In("issue.id", builder.Select("issue_id").From("issue_user").
+			Where(builder.Eq{"uid": userID, "is_mentioned": true})))
+	}
+	if involvement&IssueInvolvementReviewer != 0 {
+		cond = cond.Or(builder.In("issue.id", builder.Select("issue_id").From("review").
+			Where(builder.Eq{"reviewer_id": userID}.And(builder.NotIn("type", ReviewTypePending, ReviewTypeRequest)))))
+	}
+	if involvement&IssueInvolvementReviewRequested != 0 {
+		cond = cond.Or(builder.In("issue.id", builder.Select("issue_id").From("review").
+			Where(builder.Eq{"reviewer_id": userID, "type": ReviewTypeRequest})))
+	}
+	return cond
+}
+
+// IssueSearchQuery represents an issue search string with its involvement
+// qualifiers parsed.
+type IssueSearchQuery struct {
+	Keyword     string
+	InvolvedID  int64
+	Involvement IssueInvolvement
+}
+
+// ParseIssueSearchQuery splits the involvement qualifiers like "author:alice"
+// or "involves:me" off the keyword of an issue search. Qualifiers are combined,
+// "author:me assignee:me" matches the issues the doer has either created or
+// is assigned to, and must all name the same user. "me" stands for the doer.
+func ParseIssueSearchQuery(query string, doer *User) (*IssueSearchQuery, error) {
+	q := &IssueSearchQuery{}
+	var keywords []string
+	for _, field := range strings.Fields(query) {
+		idx := strings.IndexByte(field, ':')
+		if idx < 0 {
+			keywords = append(keywords, field)
+			continue
+		}
+		involvement, ok := issueInvolvementQualifiers[strings.ToLower(field[:idx])]
+		if !ok {
+			keywords = append(keywords, field)
+			continue
+		}
+
+		name := field[idx+1:]
+		if len(name) == 0 {
+			return nil, ErrInvalidIssueSearchQuery{query, "missing username of " + field}
+		}
+		var userID int64
+		if strings.ToLower(name) == "me" {
+			if doer == nil {
+				return nil, ErrUserNotExist{0, name, 0}
+			}
+			userID = doer.ID
+		} else {
+			user, err := GetUserByName(name)
+			if err != nil {
+				return nil, err
+			}
+			userID = user.ID
+		}
+		if q.InvolvedID > 0 && q.InvolvedID != userID {
+			return nil, ErrInvalidIssueSearchQuery{query, "qualifiers name different users"}
+		}
+		q.InvolvedID = userID
+		q.Involvement |= involvement
+	}
+	q.Keyword = strings.Join(keywords, " ")
+	return q, nil
+}
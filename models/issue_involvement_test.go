@@ -0,0 +1,99 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueSearchQuery(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	query, err := ParseIssueSearchQuery("crash on startup", doer)
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueSearchQuery{Keyword: "crash on startup"}, query)
+
+	query, err = ParseIssueSearchQuery("crash Author:me reviewed-by:user2 http://localhost", doer)
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueSearchQuery{
+		Keyword:     "crash http://localhost",
+		InvolvedID:  2,
+		Involvement: IssueInvolvementAuthor | IssueInvolvementReviewer,
+	}, query)
+
+	query, err = ParseIssueSearchQuery("review-requested:me", doer)
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueSearchQuery{InvolvedID: 2, Involvement: IssueInvolvementReviewRequested}, query)
+
+	query, err = ParseIssueSearchQuery("involves:user4", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueSearchQuery{InvolvedID: 4, Involvement: IssueInvolvementAny}, query)
+
+	_, err = ParseIssueSearchQuery("involves:me", nil)
+	assert.True(t, IsErrUserNotExist(err))
+
+	_, err = ParseIssueSearchQuery("assignee:nobody", doer)
+	assert.True(t, IsErrUserNotExist(err))
+
+	_, err = ParseIssueSearchQuery("author:user2 assignee:user4", doer)
+	assert.True(t, IsErrInvalidIssueSearchQuery(err))
+
+	_, err = ParseIssueSearchQuery("mentions:", doer)
+	assert.True(t, IsErrInvalidIssueSearchQuery(err))
+}
+
+func TestIssues_Involvement(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user2 is mentioned in issue 1
+	_, err := x.ID(2).Cols("is_mentioned").Update(&IssueUser{IsMentioned: true})
+	assert.NoError(t, err)
+	// the review of issue 2 is requested from user2 and user4
+	_, err = x.Insert(&Review{Type: ReviewTypeRequest, ReviewerID: 2, IssueID: 2},
+		&Review{Type: ReviewTypeRequest, ReviewerID: 4, IssueID: 2})
+	assert.NoError(t, err)
+
+	test := func(userID int64, involvement IssueInvolvement, expectedIssueIDs ...int64) {
+		issues, err := Issues(&IssuesOptions{
+			RepoIDs:     []int64{1},
+			SortType:    "oldest",
+			InvolvedID:  userID,
+			Involvement: involvement,
+		})
+		assert.NoError(t, err)
+		issueIDs := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+		assert.EqualValues(t, append([]int64{}, expectedIssueIDs...), issueIDs)
+
+		stats, err := GetIssueStats(&IssueStatsOptions{
+			RepoID:      1,
+			InvolvedID:  userID,
+			Involvement: involvement,
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(expectedIssueIDs), stats.OpenCount+stats.ClosedCount)
+	}
+
+	test(2, IssueInvolvementAuthor, 5)
+	test(2, IssueInvolvementAssignee)
+	test(1, IssueInvolvementAssignee, 1)
+	test(2, IssueInvolvementMentioned, 1)
+	// the pending review of user2 does not count
+	test(2, IssueInvolvementReviewer, 3)
+	test(1, IssueInvolvementReviewer, 2, 3)
+	// requesting a review is not reviewing
+	test(4, IssueInvolvementReviewer, 3)
+	test(4, IssueInvolvementReviewRequested, 2)
+	test(1, IssueInvolvementReviewRequested)
+	test(2, IssueInvolvementAuthor|IssueInvolvementMentioned, 1, 5)
+	test(2, IssueInvolvementAny, 1, 2, 3, 5)
+	// each issue is listed once even if the user is involved in several ways
+	test(1, IssueInvolvementAny, 1, 2, 3)
+}
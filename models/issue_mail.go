@@ -8,8 +8,6 @@ package models
 import (
 	"fmt"
 
-	"github.com/Unknwon/com"
-
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
@@ -19,73 +17,138 @@ func (issue *Issue) mailSubject() string {
 	return fmt.Sprintf("[%s] %s (#%d)", issue.Repo.Name, issue.Title, issue.Index)
 }
 
-// mailIssueCommentToParticipants can be used for both new issue creation and comment.
-// This function sends two list of emails:
-// 1. Repository watchers and users who are participated in comments.
-// 2. Users who are not in 1. but get mentioned in current issue/comment.
-func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content string, comment *Comment, mentions []string) error {
-	if !setting.Service.EnableNotifyMail {
-		return nil
+// EmailNotificationEvents is a set of the kinds of issue events a user can be
+// notified about by email.
+type EmailNotificationEvents int
+
+// Enumerate all the kinds of issue events
+const (
+	// EmailNotifyWatching is any activity on the issues of the watched repositories
+	EmailNotifyWatching EmailNotificationEvents = 1 << iota
+	// EmailNotifyOwnThread is a reply on an issue the user has opened or commented on
+	EmailNotifyOwnThread
+	// EmailNotifyMention is an issue or a comment mentioning the user
+	EmailNotifyMention
+	// EmailNotifyAssigned is any activity on an issue assigned to the user
+	EmailNotifyAssigned
+	// EmailNotifyReviewRequested is any activity on a pull request the user is
+	// assigned to review
+	EmailNotifyReviewRequested
+
+	// EmailNotifyAll is the default set, every kind of events is notified
+	EmailNotifyAll = EmailNotifyWatching | EmailNotifyOwnThread | EmailNotifyMention | EmailNotifyAssigned | EmailNotifyReviewRequested
+)
+
+// Has returns true if the set contains any of the given events.
+func (events EmailNotificationEvents) Has(e EmailNotificationEvents) bool {
+	return events&e != 0
+}
+
+// getIssueMailRecipients returns the email addresses of the users to notify of a
+// new issue or comment posted by doer. The users who only have to be notified
+// because they are mentioned are returned in mentionTos, as they are sent an
+// email of their own. Each user only gets an email if the events they want to be
+// notified about contain one of the reasons they have to be notified.
+func getIssueMailRecipients(e Engine, issue *Issue, doer *User, mentions []string) (tos, mentionTos []string, err error) {
+	userIDs := make([]int64, 0, 10)
+	users := make(map[int64]*User, 10)
+	reasons := make(map[int64]EmailNotificationEvents, 10)
+	addRecipient := func(u *User, reason EmailNotificationEvents) {
+		if u.ID == doer.ID || u.IsOrganization() {
+			return
+		}
+		if _, ok := users[u.ID]; !ok {
+			userIDs = append(userIDs, u.ID)
+			users[u.ID] = u
+		}
+		reasons[u.ID] |= reason
 	}
 
 	watchers, err := getWatchers(e, issue.RepoID)
 	if err != nil {
-		return fmt.Errorf("getWatchers [repo_id: %d]: %v", issue.RepoID, err)
+		return nil, nil, fmt.Errorf("getWatchers [repo_id: %d]: %v", issue.RepoID, err)
 	}
+	for i := range watchers {
+		to, err := getUserByID(e, watchers[i].UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetUserByID [%d]: %v", watchers[i].UserID, err)
+		}
+		addRecipient(to, EmailNotifyWatching)
+	}
+
 	participants, err := getParticipantsByIssueID(e, issue.ID)
 	if err != nil {
-		return fmt.Errorf("getParticipantsByIssueID [issue_id: %d]: %v", issue.ID, err)
+		return nil, nil, fmt.Errorf("getParticipantsByIssueID [issue_id: %d]: %v", issue.ID, err)
+	}
+	for _, participant := range participants {
+		addRecipient(participant, EmailNotifyOwnThread)
 	}
 
 	// In case the issue poster is not watching the repository and is active,
 	// even if we have duplicated in watchers, can be safely filtered out.
 	poster, err := getUserByID(e, issue.PosterID)
 	if err != nil {
-		return fmt.Errorf("GetUserByID [%d]: %v", issue.PosterID, err)
+		return nil, nil, fmt.Errorf("GetUserByID [%d]: %v", issue.PosterID, err)
 	}
-	if issue.PosterID != doer.ID && poster.IsActive && !poster.ProhibitLogin {
-		participants = append(participants, issue.Poster)
+	if poster.IsActive && !poster.ProhibitLogin {
+		addRecipient(poster, EmailNotifyOwnThread)
 	}
 
-	// Assignees must receive any communications
+	// The assignees of a pull request are its reviewers
 	assignees, err := GetAssigneesByIssue(issue)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-
 	for _, assignee := range assignees {
-		if assignee.ID != doer.ID {
-			participants = append(participants, assignee)
+		if issue.IsPull {
+			addRecipient(assignee, EmailNotifyReviewRequested)
+		} else {
+			addRecipient(assignee, EmailNotifyAssigned)
 		}
 	}
-
-	tos := make([]string, 0, len(watchers)) // List of email addresses.
-	names := make([]string, 0, len(watchers))
-	for i := range watchers {
-		if watchers[i].UserID == doer.ID {
-			continue
+	if issue.IsPull {
+		reviewers, err := getReviewRequestsByPullID(e, issue.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getReviewRequestsByPullID [issue_id: %d]: %v", issue.ID, err)
+		}
+		for _, reviewer := range reviewers {
+			addRecipient(reviewer, EmailNotifyReviewRequested)
 		}
+	}
 
-		to, err := getUserByID(e, watchers[i].UserID)
+	for _, name := range mentions {
+		to, err := getUserByName(e, name)
 		if err != nil {
-			return fmt.Errorf("GetUserByID [%d]: %v", watchers[i].UserID, err)
-		}
-		if to.IsOrganization() {
 			continue
 		}
-
-		tos = append(tos, to.Email)
-		names = append(names, to.Name)
+		if to.IsMailable() {
+			addRecipient(to, EmailNotifyMention)
+		}
 	}
-	for i := range participants {
-		if participants[i].ID == doer.ID {
-			continue
-		} else if com.IsSliceContainsStr(names, participants[i].Name) {
-			continue
+
+	for _, userID := range userIDs {
+		to, reason := users[userID], reasons[userID]
+		if to.EmailNotifications.Has(reason &^ EmailNotifyMention) {
+			tos = append(tos, to.Email)
+		} else if reason.Has(EmailNotifyMention) && to.EmailNotifications.Has(EmailNotifyMention) {
+			mentionTos = append(mentionTos, to.Email)
 		}
+	}
+	return tos, mentionTos, nil
+}
+
+// mailIssueCommentToParticipants can be used for both new issue creation and comment.
+// This function sends two list of emails:
+// 1. Repository watchers, users who are participated in comments and assignees.
+// 2. Users who are not in 1. but get mentioned in current issue/comment.
+func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content string, comment *Comment, mentions []string) error {
+	if !setting.Service.EnableNotifyMail {
+		return nil
+	}
 
-		tos = append(tos, participants[i].Email)
-		names = append(names, participants[i].Name)
+	tos, mentionTos, err := getIssueMailRecipients(e, issue, doer, mentions)
+	if err != nil {
+		return err
 	}
 
 	for _, to := range tos {
@@ -93,19 +156,7 @@ func mailIssueCommentToParticipants(e Engine, issue *Issue, doer *User, content
 	}
 
 	// Mail mentioned people and exclude watchers.
-	names = append(names, doer.Name)
-	tos = make([]string, 0, len(mentions)) // list of user names.
-	for i := range mentions {
-		if com.IsSliceContainsStr(names, mentions[i]) {
-			continue
-		}
-
-		tos = append(tos, mentions[i])
-	}
-
-	emails := getUserEmailsByNames(e, tos)
-
-	for _, to := range emails {
+	for _, to := range mentionTos {
 		SendIssueMentionMail(issue, doer, content, comment, []string{to})
 	}
 
@@ -0,0 +1,71 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIssueMailRecipients(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user1 opened issue 1, is assigned to it and watches repo 1 like user4
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.Equal(t, EmailNotifyAll, user4.EmailNotifications)
+
+	tos, mentionTos, err := getIssueMailRecipients(x, issue, doer, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, tos, user1.Email)
+	assert.Contains(t, tos, user4.Email)
+	assert.NotContains(t, tos, doer.Email)
+	assert.Empty(t, mentionTos)
+
+	// mentioned watchers get the comment email only
+	tos, mentionTos, err = getIssueMailRecipients(x, issue, doer, []string{"user4"})
+	assert.NoError(t, err)
+	assert.Contains(t, tos, user4.Email)
+	assert.Empty(t, mentionTos)
+
+	user4.EmailNotifications = EmailNotifyMention
+	assert.NoError(t, UpdateUserCols(user4, "email_notifications"))
+
+	// a plain comment is not sent to the users only notified of their mentions
+	tos, mentionTos, err = getIssueMailRecipients(x, issue, doer, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, tos, user1.Email)
+	assert.NotContains(t, tos, user4.Email)
+	assert.Empty(t, mentionTos)
+
+	// but a comment mentioning them is
+	tos, mentionTos, err = getIssueMailRecipients(x, issue, doer, []string{"user4"})
+	assert.NoError(t, err)
+	assert.NotContains(t, tos, user4.Email)
+	assert.Equal(t, []string{user4.Email}, mentionTos)
+
+	// the assignee is still notified when not watching the other issues
+	user1.EmailNotifications = EmailNotifyAssigned
+	assert.NoError(t, UpdateUserCols(user1, "email_notifications"))
+	tos, _, err = getIssueMailRecipients(x, issue, doer, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, tos, user1.Email)
+
+	user1.EmailNotifications = 0
+	assert.NoError(t, UpdateUserCols(user1, "email_notifications"))
+	tos, mentionTos, err = getIssueMailRecipients(x, issue, doer, []string{"user1", "user4"})
+	assert.NoError(t, err)
+	assert.NotContains(t, tos, user1.Email)
+	assert.Equal(t, []string{user4.Email}, mentionTos)
+
+	// the doer is never notified
+	tos, mentionTos, err = getIssueMailRecipients(x, issue, user4, []string{"user4"})
+	assert.NoError(t, err)
+	assert.NotContains(t, tos, user4.Email)
+	assert.Empty(t, mentionTos)
+}
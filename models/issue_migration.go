@@ -0,0 +1,234 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IssueMigrationStatus is the state of the import of the issues of a repository.
+type IssueMigrationStatus int
+
+// Possible states of an issue migration.
+const (
+	IssueMigrationRunning IssueMigrationStatus = iota
+	IssueMigrationFinished
+	IssueMigrationFailed
+)
+
+// IssueMigration tracks the import of the issues of a repository migrated
+// from GitHub, so that an interrupted import is resumed without duplicating
+// the issues already imported.
+type IssueMigration struct {
+	ID          int64       `xorm:"pk autoincr"`
+	RepoID      int64       `xorm:"UNIQUE"`
+	Repo        *Repository `xorm:"-"`
+	DoerID      int64
+	Doer        *User  `xorm:"-"`
+	APIURL      string `xorm:"api_url"`
+	RemoteOwner string
+	RemoteRepo  string
+	// Token is the encrypted token authenticating the GitHub API requests,
+	// it is cleared once the migration is finished.
+	Token  string               `xorm:"TEXT"`
+	Status IssueMigrationStatus `xorm:"INDEX"`
+	// LastIssueIndex is the number of the last imported remote issue, the
+	// remote issues are imported in ascending order.
+	LastIssueIndex int64
+	Error          string `xorm:"TEXT"`
+
+	CreatedUnix util.TimeStamp `xorm:"created"`
+	UpdatedUnix util.TimeStamp `xorm:"updated"`
+}
+
+// LoadAttributes loads the repository and the user importing the issues.
+func (m *IssueMigration) LoadAttributes() (err error) {
+	if m.Repo == nil {
+		if m.Repo, err = GetRepositoryByID(m.RepoID); err != nil {
+			return fmt.Errorf("GetRepositoryByID [%d]: %v", m.RepoID, err)
+		}
+	}
+	if m.Doer == nil {
+		if m.Doer, err = GetUserByID(m.DoerID); err != nil {
+			return fmt.Errorf("GetUserByID [%d]: %v", m.DoerID, err)
+		}
+	}
+	return nil
+}
+
+func (m *IssueMigration) getEncryptionKey() []byte {
+	k := md5.Sum([]byte(setting.SecretKey))
+	return k[:]
+}
+
+// SetToken encrypts and sets the token authenticating the API requests.
+func (m *IssueMigration) SetToken(token string) error {
+	if len(token) == 0 {
+		m.Token = ""
+		return nil
+	}
+	tokenBytes, err := aesEncrypt(m.getEncryptionKey(), []byte(token))
+	if err != nil {
+		return err
+	}
+	m.Token = base64.StdEncoding.EncodeToString(tokenBytes)
+	return nil
+}
+
+// GetToken returns the decrypted token authenticating the API requests.
+func (m *IssueMigration) GetToken() (string, error) {
+	if len(m.Token) == 0 {
+		return "", nil
+	}
+	decodedToken, err := base64.StdEncoding.DecodeString(m.Token)
+	if err != nil {
+		return "", err
+	}
+	token, err := aesDecrypt(m.getEncryptionKey(), decodedToken)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// NewIssueMigration inserts a new issue migration.
+func NewIssueMigration(m *IssueMigration) error {
+	_, err := x.Insert(m)
+	return err
+}
+
+// UpdateIssueMigrationCols updates the given columns of an issue migration.
+func UpdateIssueMigrationCols(m *IssueMigration, cols ...string) error {
+	_, err := x.ID(m.ID).Cols(cols...).Update(m)
+	return err
+}
+
+// GetIssueMigrationByRepoID returns the issue migration of the repository.
+func GetIssueMigrationByRepoID(repoID int64) (*IssueMigration, error) {
+	m := &IssueMigration{RepoID: repoID}
+	has, err := x.Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueMigrationNotExist{repoID}
+	}
+	return m, nil
+}
+
+// GetRunningIssueMigrations returns the issue migrations which are not
+// finished, including the ones interrupted by a shutdown.
+func GetRunningIssueMigrations() ([]*IssueMigration, error) {
+	migrations := make([]*IssueMigration, 0, 5)
+	return migrations, x.Where("status = ?", IssueMigrationRunning).Find(&migrations)
+}
+
+// RetryIssueMigration sets the failed issue migration of the repository as
+// running again, so that it is resumed where it stopped.
+func RetryIssueMigration(repoID int64) (*IssueMigration, error) {
+	m, err := GetIssueMigrationByRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the status is checked by the update so that a migration is not
+	// retried twice at once
+	m.Status = IssueMigrationRunning
+	m.Error = ""
+	affected, err := x.Where("id = ? AND status = ?", m.ID, IssueMigrationFailed).
+		Cols("status", "error").Update(m)
+	if err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, ErrIssueMigrationNotFailed{repoID}
+	}
+	return m, nil
+}
+
+// MigratedIssueOptions represents an issue imported from another service.
+type MigratedIssueOptions struct {
+	// Index is the number of the issue on the other service.
+	Index       int64
+	Title       string
+	Content     string
+	LabelIDs    []int64
+	MilestoneID int64
+	Comments    []string
+	IsClosed    bool
+}
+
+// InsertMigratedIssue creates an imported issue with its comments, posted by
+// the user importing the issues, and records it as the last imported issue of
+// the migration within the same transaction.
+func InsertMigratedIssue(m *IssueMigration, opts MigratedIssueOptions) (*Issue, error) {
+	if err := m.LoadAttributes(); err != nil {
+		return nil, err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	// the issue index is computed from the issue counts of the repository,
+	// which each imported issue increases
+	repo, err := getRepositoryByID(sess, m.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	m.Repo = repo
+
+	issue := &Issue{
+		RepoID:      m.RepoID,
+		Repo:        m.Repo,
+		Title:       opts.Title,
+		Content:     opts.Content,
+		PosterID:    m.DoerID,
+		Poster:      m.Doer,
+		MilestoneID: opts.MilestoneID,
+	}
+	if err := newIssue(sess, m.Doer, NewIssueOptions{
+		Repo:     m.Repo,
+		Issue:    issue,
+		LabelIDs: opts.LabelIDs,
+	}); err != nil {
+		return nil, fmt.Errorf("newIssue: %v", err)
+	}
+
+	for _, content := range opts.Comments {
+		if _, err := createComment(sess, &CreateCommentOptions{
+			Type:    CommentTypeComment,
+			Doer:    m.Doer,
+			Repo:    m.Repo,
+			Issue:   issue,
+			Content: content,
+		}); err != nil {
+			return nil, fmt.Errorf("createComment: %v", err)
+		}
+	}
+
+	if opts.IsClosed {
+		if err := issue.changeStatus(sess, m.Doer, true); err != nil {
+			return nil, fmt.Errorf("changeStatus: %v", err)
+		}
+	}
+
+	m.LastIssueIndex = opts.Index
+	if _, err := sess.ID(m.ID).Cols("last_issue_index").Update(m); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Commit(); err != nil {
+		return nil, fmt.Errorf("Commit: %v", err)
+	}
+
+	UpdateIssueIndexer(issue.ID)
+	return issue, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertMigratedIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	m := &IssueMigration{RepoID: 1, DoerID: 2, Status: IssueMigrationRunning}
+	assert.NoError(t, NewIssueMigration(m))
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	issue, err := InsertMigratedIssue(m, MigratedIssueOptions{
+		Index:    5,
+		Title:    "imported issue",
+		Content:  "content",
+		LabelIDs: []int64{1},
+		Comments: []string{"first comment", "second comment"},
+		IsClosed: true,
+	})
+	assert.NoError(t, err)
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: issue.ID}).(*Issue)
+	assert.EqualValues(t, repo.NextIssueIndex(), issue.Index)
+	assert.EqualValues(t, 2, issue.PosterID)
+	assert.True(t, issue.IsClosed)
+	assert.EqualValues(t, 2, issue.NumComments)
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: 1})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Type: CommentTypeComment, Content: "second comment"})
+	AssertExistsAndLoadBean(t, &IssueMigration{ID: m.ID, LastIssueIndex: 5})
+
+	// the issue counts of the repository are reloaded for each issue
+	issue, err = InsertMigratedIssue(m, MigratedIssueOptions{Index: 6, Title: "second imported issue"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, repo.NextIssueIndex()+1, issue.Index)
+	assert.False(t, issue.IsClosed)
+
+	migrations, err := GetRunningIssueMigrations()
+	assert.NoError(t, err)
+	if assert.Len(t, migrations, 1) {
+		assert.EqualValues(t, 6, migrations[0].LastIssueIndex)
+	}
+}
+
+func TestIssueMigration_SetToken(t *testing.T) {
+	m := &IssueMigration{}
+	assert.NoError(t, m.SetToken("secret token"))
+	assert.NotEmpty(t, m.Token)
+	assert.NotContains(t, m.Token, "secret token")
+	token, err := m.GetToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "secret token", token)
+
+	assert.NoError(t, m.SetToken(""))
+	assert.Empty(t, m.Token)
+	token, err = m.GetToken()
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+}
+
+func TestRetryIssueMigration(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := RetryIssueMigration(1)
+	assert.True(t, IsErrIssueMigrationNotExist(err))
+
+	m := &IssueMigration{RepoID: 1, DoerID: 2, Status: IssueMigrationRunning}
+	assert.NoError(t, NewIssueMigration(m))
+	_, err = RetryIssueMigration(1)
+	assert.True(t, IsErrIssueMigrationNotFailed(err))
+
+	m.Status = IssueMigrationFailed
+	m.Error = "rate limit exceeded"
+	assert.NoError(t, UpdateIssueMigrationCols(m, "status", "error"))
+	retried, err := RetryIssueMigration(1)
+	assert.NoError(t, err)
+	assert.Equal(t, m.ID, retried.ID)
+	AssertExistsAndLoadBean(t, &IssueMigration{ID: m.ID}, Cond("status = ? AND error = ?", IssueMigrationRunning, ""))
+
+	// a running migration is not retried again
+	_, err = RetryIssueMigration(1)
+	assert.True(t, IsErrIssueMigrationNotFailed(err))
+}
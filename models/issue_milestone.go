@@ -14,6 +14,16 @@ import (
 	"github.com/go-xorm/xorm"
 )
 
+// MilestoneProgressMode defines how the completeness of a milestone is computed.
+type MilestoneProgressMode int
+
+const (
+	// MilestoneProgressCount computes the share of closed issues
+	MilestoneProgressCount MilestoneProgressMode = iota
+	// MilestoneProgressWeighted computes the share of points of closed issues
+	MilestoneProgressWeighted
+)
+
 // Milestone represents a milestone of repository.
 type Milestone struct {
 	ID              int64 `xorm:"pk autoincr"`
@@ -24,9 +34,12 @@ type Milestone struct {
 	IsClosed        bool
 	NumIssues       int
 	NumClosedIssues int
-	NumOpenIssues   int  `xorm:"-"`
-	Completeness    int  // Percentage(1-100).
-	IsOverdue       bool `xorm:"-"`
+	NumOpenIssues   int                   `xorm:"-"`
+	NumPoints       int                   `xorm:"NOT NULL DEFAULT 0"`
+	NumClosedPoints int                   `xorm:"NOT NULL DEFAULT 0"`
+	ProgressMode    MilestoneProgressMode `xorm:"NOT NULL DEFAULT 0"`
+	Completeness    int                   // Percentage(1-100).
+	IsOverdue       bool                  `xorm:"-"`
 
 	DeadlineString string `xorm:"-"`
 	DeadlineUnix   util.TimeStamp
@@ -37,13 +50,22 @@ type Milestone struct {
 
 // BeforeUpdate is invoked from XORM before updating this object.
 func (m *Milestone) BeforeUpdate() {
-	if m.NumIssues > 0 {
+	if m.IsWeighted() {
+		m.Completeness = m.NumClosedPoints * 100 / m.NumPoints
+	} else if m.NumIssues > 0 {
 		m.Completeness = m.NumClosedIssues * 100 / m.NumIssues
 	} else {
 		m.Completeness = 0
 	}
 }
 
+// IsWeighted returns true if the completeness of the milestone is computed
+// from the points of its issues. A weighted milestone none of whose issues
+// has points falls back to the number of issues.
+func (m *Milestone) IsWeighted() bool {
+	return m.ProgressMode == MilestoneProgressWeighted && m.NumPoints > 0
+}
+
 // AfterLoad is invoked from XORM after setting the value of a field of
 // this object.
 func (m *Milestone) AfterLoad() {
@@ -308,9 +330,11 @@ func changeMilestoneIssueStats(e *xorm.Session, issue *Issue) error {
 	if issue.IsClosed {
 		m.NumOpenIssues--
 		m.NumClosedIssues++
+		m.NumClosedPoints += issue.Points
 	} else {
 		m.NumOpenIssues++
 		m.NumClosedIssues--
+		m.NumClosedPoints -= issue.Points
 	}
 
 	return updateMilestone(e, m)
@@ -324,8 +348,10 @@ func changeMilestoneAssign(e *xorm.Session, doer *User, issue *Issue, oldMilesto
 		}
 
 		m.NumIssues--
+		m.NumPoints -= issue.Points
 		if issue.IsClosed {
 			m.NumClosedIssues--
+			m.NumClosedPoints -= issue.Points
 		}
 
 		if err = updateMilestone(e, m); err != nil {
@@ -340,8 +366,10 @@ func changeMilestoneAssign(e *xorm.Session, doer *User, issue *Issue, oldMilesto
 		}
 
 		m.NumIssues++
+		m.NumPoints += issue.Points
 		if issue.IsClosed {
 			m.NumClosedIssues++
+			m.NumClosedPoints += issue.Points
 		}
 
 		if err = updateMilestone(e, m); err != nil {
@@ -42,6 +42,77 @@ func TestMilestone_APIFormat(t *testing.T) {
 	}, *milestone.APIFormat())
 }
 
+func TestMilestone_BeforeUpdate(t *testing.T) {
+	milestone := &Milestone{
+		NumIssues:       4,
+		NumClosedIssues: 1,
+		NumPoints:       10,
+		NumClosedPoints: 8,
+	}
+	milestone.BeforeUpdate()
+	assert.Equal(t, 25, milestone.Completeness)
+
+	milestone.ProgressMode = MilestoneProgressWeighted
+	milestone.BeforeUpdate()
+	assert.True(t, milestone.IsWeighted())
+	assert.Equal(t, 80, milestone.Completeness)
+
+	// falls back to the number of issues when no issue has points
+	milestone.NumPoints = 0
+	milestone.NumClosedPoints = 0
+	milestone.BeforeUpdate()
+	assert.False(t, milestone.IsWeighted())
+	assert.Equal(t, 25, milestone.Completeness)
+
+	milestone.NumIssues = 0
+	milestone.NumClosedIssues = 0
+	milestone.BeforeUpdate()
+	assert.Equal(t, 0, milestone.Completeness)
+}
+
+func TestWeightedMilestoneProgress(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 2}).(*Milestone)
+	milestone.ProgressMode = MilestoneProgressWeighted
+	assert.NoError(t, UpdateMilestone(milestone))
+
+	open := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	closed := AssertExistsAndLoadBean(t, &Issue{ID: 5}).(*Issue)
+	for _, issue := range []*Issue{open, closed} {
+		issue.MilestoneID = milestone.ID
+		assert.NoError(t, ChangeMilestoneAssign(issue, doer, 0))
+	}
+	assertCompleteness := func(expected int) {
+		milestone := AssertExistsAndLoadBean(t, &Milestone{ID: 2}).(*Milestone)
+		assert.Equal(t, expected, milestone.Completeness)
+		CheckConsistencyFor(t, &Milestone{})
+	}
+
+	// no issue has points yet
+	assertCompleteness(50)
+
+	// mixed pointed and unpointed issues
+	assert.NoError(t, closed.ChangePoints(3))
+	assertCompleteness(100)
+	assert.NoError(t, open.ChangePoints(1))
+	assertCompleteness(75)
+
+	assert.NoError(t, open.ChangeStatus(doer, true))
+	assertCompleteness(100)
+	assert.NoError(t, closed.ChangeStatus(doer, false))
+	assertCompleteness(25)
+
+	// moving an issue out of the milestone takes its points along
+	oldMilestoneID := closed.MilestoneID
+	closed.MilestoneID = 0
+	assert.NoError(t, ChangeMilestoneAssign(closed, doer, oldMilestoneID))
+	assertCompleteness(100)
+
+	assert.NoError(t, open.ChangePoints(0))
+	assertCompleteness(100)
+}
+
 func TestNewMilestone(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	milestone := &Milestone{
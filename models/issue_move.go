@@ -0,0 +1,129 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+)
+
+// MoveIssue moves an issue to the target repository. A new issue is created
+// there with the content, plain comments, reactions and attachments of the
+// original one. Labels and milestone are matched by name in the target
+// repository and silently dropped when they don't exist. The original issue
+// is closed with a comment referencing the new issue, which is returned.
+func MoveIssue(doer *User, issue *Issue, target *Repository) (*Issue, error) {
+	if issue.IsPull || issue.RepoID == target.ID {
+		return nil, ErrIssueCannotBeMoved{issue.ID, target.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	if err := issue.loadRepo(sess); err != nil {
+		return nil, err
+	}
+	if err := issue.loadPoster(sess); err != nil {
+		return nil, err
+	}
+	if err := issue.getLabels(sess); err != nil {
+		return nil, err
+	}
+
+	// During the session, SQLite3 driver cannot handle retrieve objects after update something.
+	// So we have to look up the target labels and milestone first.
+	labelIDs := make([]int64, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		targetLabel, err := getLabelInRepoByName(sess, target.ID, label.Name)
+		if err != nil {
+			if IsErrLabelNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("getLabelInRepoByName: %v", err)
+		}
+		labelIDs = append(labelIDs, targetLabel.ID)
+	}
+
+	var milestoneID int64
+	if issue.MilestoneID > 0 {
+		milestone, err := getMilestoneByRepoID(sess, issue.RepoID, issue.MilestoneID)
+		if err != nil && !IsErrMilestoneNotExist(err) {
+			return nil, fmt.Errorf("getMilestoneByRepoID: %v", err)
+		}
+		if milestone != nil {
+			targetMilestone := &Milestone{RepoID: target.ID, Name: milestone.Name}
+			has, err := sess.Get(targetMilestone)
+			if err != nil {
+				return nil, fmt.Errorf("get target milestone: %v", err)
+			} else if has {
+				milestoneID = targetMilestone.ID
+			}
+		}
+	}
+
+	moved := &Issue{
+		RepoID:       target.ID,
+		Repo:         target,
+		Title:        issue.Title,
+		PosterID:     issue.PosterID,
+		Poster:       issue.Poster,
+		Content:      issue.Content,
+		MilestoneID:  milestoneID,
+		Points:       issue.Points,
+		DeadlineUnix: issue.DeadlineUnix,
+	}
+	if err := newIssue(sess, doer, NewIssueOptions{
+		Repo:     target,
+		Issue:    moved,
+		LabelIDs: labelIDs,
+	}); err != nil {
+		return nil, fmt.Errorf("newIssue: %v", err)
+	}
+
+	numComments, err := sess.
+		Where("issue_id = ? AND type = ?", issue.ID, CommentTypeComment).
+		Cols("issue_id").
+		Update(&Comment{IssueID: moved.ID})
+	if err != nil {
+		return nil, fmt.Errorf("move comments: %v", err)
+	}
+	if _, err = sess.Exec("UPDATE `issue` SET num_comments = num_comments - ? WHERE id = ?", numComments, issue.ID); err != nil {
+		return nil, err
+	}
+	if _, err = sess.Exec("UPDATE `issue` SET num_comments = num_comments + ? WHERE id = ?", numComments, moved.ID); err != nil {
+		return nil, err
+	}
+	if _, err = sess.Exec("UPDATE `reaction` SET issue_id = ? WHERE issue_id = ?", moved.ID, issue.ID); err != nil {
+		return nil, fmt.Errorf("move reactions: %v", err)
+	}
+	if _, err = sess.Exec("UPDATE `attachment` SET issue_id = ? WHERE issue_id = ?", moved.ID, issue.ID); err != nil {
+		return nil, fmt.Errorf("move attachments: %v", err)
+	}
+
+	if _, err = createComment(sess, &CreateCommentOptions{
+		Type:             CommentTypeIssueMoved,
+		Doer:             doer,
+		Repo:             issue.Repo,
+		Issue:            issue,
+		DependentIssueID: moved.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("createComment: %v", err)
+	}
+
+	if err = issue.changeStatus(sess, doer, true); err != nil {
+		return nil, err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, fmt.Errorf("Commit: %v", err)
+	}
+
+	UpdateIssueIndexer(issue.ID)
+	UpdateIssueIndexer(moved.ID)
+
+	return moved, nil
+}
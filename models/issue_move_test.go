@@ -0,0 +1,70 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	target := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+
+	// Only label1 exists in the target repository, label2 is dropped.
+	assert.NoError(t, issue.AddLabel(doer, AssertExistsAndLoadBean(t, &Label{ID: 2}).(*Label)))
+	targetLabel := &Label{RepoID: target.ID, Name: "label1", Color: "#abcdef"}
+	assert.NoError(t, NewLabel(targetLabel))
+
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	moved, err := MoveIssue(doer, issue, target)
+	assert.NoError(t, err)
+
+	moved = AssertExistsAndLoadBean(t, &Issue{ID: moved.ID, RepoID: target.ID}).(*Issue)
+	assert.Equal(t, issue.Title, moved.Title)
+	assert.Equal(t, issue.Content, moved.Content)
+	assert.EqualValues(t, issue.PosterID, moved.PosterID)
+	assert.False(t, moved.IsClosed)
+	assert.NoError(t, moved.LoadAttributes())
+	if assert.Len(t, moved.Labels, 1) {
+		assert.EqualValues(t, targetLabel.ID, moved.Labels[0].ID)
+	}
+
+	// Plain comments and attachments are carried over.
+	assert.EqualValues(t, 2, moved.NumComments)
+	AssertExistsAndLoadBean(t, &Comment{ID: 2, IssueID: moved.ID})
+	AssertExistsAndLoadBean(t, &Comment{ID: 3, IssueID: moved.ID})
+	AssertExistsAndLoadBean(t, &Attachment{ID: 1, IssueID: moved.ID})
+	AssertExistsAndLoadBean(t, &Attachment{ID: 2, IssueID: moved.ID})
+
+	// The original is closed and references the new issue.
+	original := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.True(t, original.IsClosed)
+	assert.EqualValues(t, 0, original.NumComments)
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeIssueMoved, IssueID: 1, DependentIssueID: moved.ID})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeClose, IssueID: 1})
+
+	CheckConsistencyFor(t, &Issue{}, &Repository{}, &Label{})
+}
+
+func TestMoveIssue_Invalid(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	target := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	_, err := MoveIssue(doer, issue, repo)
+	assert.True(t, IsErrIssueCannotBeMoved(err))
+
+	pull := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	_, err = MoveIssue(doer, pull, target)
+	assert.True(t, IsErrIssueCannotBeMoved(err))
+}
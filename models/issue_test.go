@@ -131,6 +131,21 @@ func TestIssue_AddLabels(t *testing.T) {
 	}
 }
 
+func TestIssue_UpdateLabels(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	label1 := AssertExistsAndLoadBean(t, &Label{ID: 1}).(*Label)
+	label2 := AssertExistsAndLoadBean(t, &Label{ID: 2}).(*Label)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	assert.NoError(t, issue.UpdateLabels(doer, []*Label{label2}, []*Label{label1}))
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: 1, LabelID: 2})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: 1, LabelID: 1})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeLabel, IssueID: 1, LabelID: 2, Content: "1"})
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeLabel, IssueID: 1, LabelID: 1})
+	CheckConsistencyFor(t, &Label{ID: 1}, &Label{ID: 2})
+}
+
 func TestIssue_ClearLabels(t *testing.T) {
 	var tests = []struct {
 		issueID int64
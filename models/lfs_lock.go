@@ -12,7 +12,10 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
+
+	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
 )
 
@@ -25,6 +28,8 @@ type LFSLock struct {
 	OwnerID int64       `xorm:"INDEX NOT NULL"`
 	Path    string      `xorm:"TEXT"`
 	Created time.Time   `xorm:"created"`
+	// ExpiresUnix is zero for locks which never expire.
+	ExpiresUnix util.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
 }
 
 // BeforeInsert is invoked from XORM before inserting an object of this type.
@@ -47,6 +52,16 @@ func (l *LFSLock) AfterLoad(session *xorm.Session) {
 	}
 }
 
+// IsExpired returns true if the lock has an expiry which has passed.
+func (l *LFSLock) IsExpired() bool {
+	return l.ExpiresUnix > 0 && l.ExpiresUnix <= util.TimeStampNow()
+}
+
+// activeLFSLockCond matches the locks which have not expired yet.
+func activeLFSLockCond() builder.Cond {
+	return builder.Eq{"expires_unix": 0}.Or(builder.Gt{"expires_unix": util.TimeStampNow()})
+}
+
 func cleanPath(p string) string {
 	return path.Clean(p)
 }
@@ -78,15 +93,36 @@ func CreateLFSLock(lock *LFSLock) (*LFSLock, error) {
 		return nil, err
 	}
 
-	_, err = x.InsertOne(lock)
-	return lock, err
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	// An expired lock which has not been cleaned up yet must not block the path.
+	expired := make([]*LFSLock, 0, 1)
+	if err = sess.Where("repo_id = ? AND lower(path) = ?", lock.Repo.ID, strings.ToLower(cleanPath(lock.Path))).
+		And(builder.Not{activeLFSLockCond()}).
+		Find(&expired); err != nil {
+		return nil, err
+	}
+	for _, l := range expired {
+		if err = releaseLFSLock(sess, l, nil, LFSLockReleaseExpired); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = sess.InsertOne(lock); err != nil {
+		return nil, err
+	}
+	return lock, sess.Commit()
 }
 
 // GetLFSLock returns release by given path.
 func GetLFSLock(repo *Repository, path string) (*LFSLock, error) {
 	path = cleanPath(path)
 	rel := &LFSLock{RepoID: repo.ID}
-	has, err := x.Where("lower(path) = ?", strings.ToLower(path)).Get(rel)
+	has, err := x.Where("lower(path) = ?", strings.ToLower(path)).And(activeLFSLockCond()).Get(rel)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +135,7 @@ func GetLFSLock(repo *Repository, path string) (*LFSLock, error) {
 // GetLFSLockByID returns release by given id.
 func GetLFSLockByID(id int64) (*LFSLock, error) {
 	lock := new(LFSLock)
-	has, err := x.ID(id).Get(lock)
+	has, err := x.ID(id).And(activeLFSLockCond()).Get(lock)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -108,9 +144,9 @@ func GetLFSLockByID(id int64) (*LFSLock, error) {
 	return lock, nil
 }
 
-// GetLFSLockByRepoID returns a list of locks of repository.
+// GetLFSLockByRepoID returns a list of active locks of repository.
 func GetLFSLockByRepoID(repoID int64) (locks []*LFSLock, err error) {
-	err = x.Where("repo_id = ?", repoID).Find(&locks)
+	err = x.Where("repo_id = ?", repoID).And(activeLFSLockCond()).Find(&locks)
 	return
 }
 
@@ -126,12 +162,109 @@ func DeleteLFSLockByID(id int64, u *User, force bool) (*LFSLock, error) {
 		return nil, err
 	}
 
-	if !force && u.ID != lock.OwnerID {
-		return nil, fmt.Errorf("user doesn't own lock and force flag is not set")
+	reason := LFSLockReleaseUnlocked
+	if u.ID != lock.OwnerID {
+		if !force {
+			return nil, fmt.Errorf("user doesn't own lock and force flag is not set")
+		}
+		reason = LFSLockReleaseForced
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return nil, err
+	}
+	if err = releaseLFSLock(sess, lock, u, reason); err != nil {
+		return nil, err
 	}
+	return lock, sess.Commit()
+}
+
+// LFSLockReleaseReason describes why a lock has been released.
+type LFSLockReleaseReason int
 
-	_, err = x.ID(id).Delete(new(LFSLock))
-	return lock, err
+// Possible reasons for releasing a lock.
+const (
+	LFSLockReleaseUnlocked LFSLockReleaseReason = iota + 1 // unlocked by its owner
+	LFSLockReleaseForced                                   // unlocked by another user
+	LFSLockReleaseExpired                                  // released by the system after it expired
+)
+
+// LFSLockRelease records the release of a git lfs lock.
+type LFSLockRelease struct {
+	ID          int64 `xorm:"pk autoincr"`
+	LockID      int64
+	RepoID      int64 `xorm:"INDEX NOT NULL"`
+	OwnerID     int64
+	Path        string `xorm:"TEXT"`
+	ReleaserID  int64  // zero if released by the system
+	Reason      LFSLockReleaseReason
+	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
+}
+
+// releaseLFSLock deletes the lock and records who released it and why.
+// A nil releaser means the system released the lock.
+func releaseLFSLock(e Engine, lock *LFSLock, releaser *User, reason LFSLockReleaseReason) error {
+	if _, err := e.ID(lock.ID).Delete(new(LFSLock)); err != nil {
+		return err
+	}
+
+	release := &LFSLockRelease{
+		LockID:  lock.ID,
+		RepoID:  lock.RepoID,
+		OwnerID: lock.OwnerID,
+		Path:    lock.Path,
+		Reason:  reason,
+	}
+	if releaser != nil {
+		release.ReleaserID = releaser.ID
+	}
+	_, err := e.Insert(release)
+	return err
+}
+
+// GetLFSLockReleasesByRepoID returns the recorded lock releases of a repository,
+// most recent first.
+func GetLFSLockReleasesByRepoID(repoID int64) (releases []*LFSLockRelease, err error) {
+	err = x.Where("repo_id = ?", repoID).Desc("id").Find(&releases)
+	return
+}
+
+// ReleaseExpiredLFSLocks releases all the locks which have expired.
+func ReleaseExpiredLFSLocks() {
+	if !taskStatusTable.StartIfNotRunning(`release_expired_lfs_locks`) {
+		return
+	}
+	defer taskStatusTable.Stop(`release_expired_lfs_locks`)
+
+	log.Trace("Doing: ReleaseExpiredLFSLocks")
+
+	locks := make([]*LFSLock, 0, 10)
+	if err := x.Where("expires_unix > 0 AND expires_unix <= ?", util.TimeStampNow()).Find(&locks); err != nil {
+		log.Error(4, "ReleaseExpiredLFSLocks: %v", err)
+		return
+	}
+
+	for _, lock := range locks {
+		if err := releaseExpiredLFSLock(lock); err != nil {
+			log.Error(4, "ReleaseExpiredLFSLocks [lock: %d]: %v", lock.ID, err)
+			continue
+		}
+		log.Trace("Released expired LFS lock %d on %s in repository %d", lock.ID, lock.Path, lock.RepoID)
+	}
+}
+
+func releaseExpiredLFSLock(lock *LFSLock) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := releaseLFSLock(sess, lock, nil, LFSLockReleaseExpired); err != nil {
+		return err
+	}
+	return sess.Commit()
 }
 
 //CheckLFSAccessForRepo check needed access mode base on action
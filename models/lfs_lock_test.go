@@ -0,0 +1,72 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFSLockExpiry(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	permanent, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "permanent.bin"})
+	assert.NoError(t, err)
+	assert.False(t, permanent.IsExpired())
+
+	expired, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "expired.bin", ExpiresUnix: util.TimeStampNow().Add(-60)})
+	assert.NoError(t, err)
+	assert.True(t, expired.IsExpired())
+
+	// expired locks are not active anymore
+	locks, err := GetLFSLockByRepoID(repo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, locks, 1) {
+		assert.EqualValues(t, permanent.ID, locks[0].ID)
+	}
+	_, err = GetLFSLock(repo, "expired.bin")
+	assert.True(t, IsErrLFSLockNotExist(err))
+
+	// and do not prevent locking the path again
+	relocked, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "expired.bin"})
+	assert.NoError(t, err)
+	AssertNotExistsBean(t, &LFSLock{ID: expired.ID})
+	AssertExistsAndLoadBean(t, &LFSLockRelease{LockID: expired.ID, ReleaserID: 0, Reason: LFSLockReleaseExpired})
+
+	_, err = DeleteLFSLockByID(relocked.ID, user, false)
+	assert.NoError(t, err)
+	AssertExistsAndLoadBean(t, &LFSLockRelease{LockID: relocked.ID, ReleaserID: user.ID, Reason: LFSLockReleaseUnlocked})
+}
+
+func TestReleaseExpiredLFSLocks(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	expired, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "expired.bin", ExpiresUnix: util.TimeStampNow().Add(-60)})
+	assert.NoError(t, err)
+	pending, err := CreateLFSLock(&LFSLock{Repo: repo, Owner: user, Path: "pending.bin", ExpiresUnix: util.TimeStampNow().Add(3600)})
+	assert.NoError(t, err)
+
+	ReleaseExpiredLFSLocks()
+
+	AssertNotExistsBean(t, &LFSLock{ID: expired.ID})
+	AssertExistsAndLoadBean(t, &LFSLock{ID: pending.ID})
+
+	releases, err := GetLFSLockReleasesByRepoID(repo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, releases, 1) {
+		assert.EqualValues(t, expired.ID, releases[0].LockID)
+		assert.EqualValues(t, user.ID, releases[0].OwnerID)
+		assert.EqualValues(t, 0, releases[0].ReleaserID)
+		assert.Equal(t, LFSLockReleaseExpired, releases[0].Reason)
+		assert.Equal(t, "expired.bin", releases[0].Path)
+	}
+}
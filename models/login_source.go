@@ -126,6 +126,10 @@ type OAuth2Config struct {
 	ClientSecret                  string
 	OpenIDConnectAutoDiscoveryURL string
 	CustomURLMapping              *oauth2.CustomURLMapping
+	// Claim of the OpenID Connect ID token listing the groups of the user
+	GroupClaimName string
+	// JSON object mapping groups to organization teams, e.g. {"developers": {"org": ["team"]}}
+	GroupTeamMap string
 }
 
 // FromDB fills up an OAuth2Config from serialized format.
@@ -272,8 +276,8 @@ func CreateLoginSource(source *LoginSource) error {
 	} else if has {
 		return ErrLoginSourceAlreadyExist{source.Name}
 	}
-	// Synchronization is only aviable with LDAP for now
-	if !source.IsLDAP() {
+	// Synchronization is only aviable with LDAP and OpenID Connect for now
+	if !source.IsLDAP() && !(source.IsOAuth2() && source.OAuth2().Provider == "openidConnect") {
 		source.IsSyncEnabled = false
 	}
 
@@ -645,6 +649,13 @@ func UserSignIn(username, password string) (*User, error) {
 		switch user.LoginType {
 		case LoginNoType, LoginPlain, LoginOAuth2:
 			if user.ValidatePassword(password) {
+				// Take the chance to hash the password with the configured algorithm.
+				if user.PasswordNeedsRehash() {
+					user.HashPassword(password)
+					if err := UpdateUserCols(user, "passwd", "passwd_hash_algo"); err != nil {
+						return nil, err
+					}
+				}
 				return user, nil
 			}
 
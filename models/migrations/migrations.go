@@ -206,6 +206,46 @@ var migrations = []Migration{
 	NewMigration("clear nonused data which not deleted when user was deleted", clearNonusedData),
 	// v76 -> v77
 	NewMigration("add pull request rebase with merge commit", addPullRequestRebaseWithMerge),
+	// v77 -> v78
+	NewMigration("add protected file patterns to protected branches", addProtectedFilePatternsToProtectedBranches),
+	// v78 -> v79
+	NewMigration("add is_archived to repository", addIsArchivedToRepository),
+	// v79 -> v80
+	NewMigration("add expiry to lfs locks", addLFSLockExpiry),
+	// v80 -> v81
+	NewMigration("add is_draft to pull requests", addIsDraftToPullRequest),
+	// v81 -> v82
+	NewMigration("add oauth2 tokens to external login users", addOAuth2TokensToExternalLoginUser),
+	// v82 -> v83
+	NewMigration("add required status check contexts to protected branches", addStatusCheckContextsToProtectedBranches),
+	// v83 -> v84
+	NewMigration("add require two-factor authentication to organizations", addRequireTwoFactorToOrganizations),
+	// v84 -> v85
+	NewMigration("add approvals team to protected branches", addApprovalsTeamToProtectedBranches),
+	// v85 -> v86
+	NewMigration("add retry info to hook tasks", addRetryInfoToHookTasks),
+	// v86 -> v87
+	NewMigration("add user sessions", addUserSessions),
+	// v87 -> v88
+	NewMigration("add points to issues and milestones", addPointsToIssuesAndMilestones),
+	// v88 -> v89
+	NewMigration("add disabled repository units to organizations", addDisabledRepoUnitsToOrganizations),
+	// v89 -> v90
+	NewMigration("add password hash algorithm to users", addPasswdHashAlgoToUsers),
+	// v90 -> v91
+	NewMigration("add health check result to repositories", addHealthCheckResultToRepository),
+	// v91 -> v92
+	NewMigration("add protected tags", addProtectedTagTable),
+	// v92 -> v93
+	NewMigration("add email notification preferences to users", addEmailNotificationsToUsers),
+	// v93 -> v94
+	NewMigration("add repository size limit to users", addMaxRepoSizeToUsers),
+	// v94 -> v95
+	NewMigration("add issue migration table", addIssueMigrationTable),
+	// v95 -> v96
+	NewMigration("add hook task attempt table", addHookTaskAttemptTable),
+	// v96 -> v97
+	NewMigration("clear deleted approvals teams of protected branches", clearDeletedApprovalsTeams),
 }
 
 // Migrate database to current version
@@ -0,0 +1,16 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func addProtectedFilePatternsToProtectedBranches(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		ProtectedFilePatterns string  `xorm:"TEXT"`
+		ProtectedFilesUserIDs []int64 `xorm:"JSON TEXT"`
+		ProtectedFilesTeamIDs []int64 `xorm:"JSON TEXT"`
+	}
+	return x.Sync2(new(ProtectedBranch))
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addLFSLockExpiry(x *xorm.Engine) error {
+	type LFSLock struct {
+		ExpiresUnix util.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type LFSLockRelease struct {
+		ID          int64 `xorm:"pk autoincr"`
+		LockID      int64
+		RepoID      int64 `xorm:"INDEX NOT NULL"`
+		OwnerID     int64
+		Path        string `xorm:"TEXT"`
+		ReleaserID  int64
+		Reason      int
+		CreatedUnix util.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(LFSLock)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return x.Sync2(new(LFSLockRelease))
+}
@@ -0,0 +1,20 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addOAuth2TokensToExternalLoginUser(x *xorm.Engine) error {
+	type ExternalLoginUser struct {
+		AccessToken  string `xorm:"TEXT"`
+		RefreshToken string `xorm:"TEXT"`
+		ExpiresUnix  util.TimeStamp
+	}
+	return x.Sync2(new(ExternalLoginUser))
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func addRetryInfoToHookTasks(x *xorm.Engine) error {
+	type HookTask struct {
+		Attempts         int   `xorm:"NOT NULL DEFAULT 0"`
+		NextDeliveryUnix int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+	return x.Sync2(new(HookTask))
+}
@@ -0,0 +1,25 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addUserSessions(x *xorm.Engine) error {
+	type UserSession struct {
+		ID           int64  `xorm:"pk autoincr"`
+		UID          int64  `xorm:"INDEX NOT NULL"`
+		SessionHash  string `xorm:"UNIQUE NOT NULL"`
+		UserAgent    string `xorm:"TEXT"`
+		IP           string
+		IsRevoked    bool           `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix  util.TimeStamp `xorm:"INDEX created"`
+		LastSeenUnix util.TimeStamp `xorm:"INDEX"`
+	}
+	return x.Sync2(new(UserSession))
+}
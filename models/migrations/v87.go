@@ -0,0 +1,19 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func addPointsToIssuesAndMilestones(x *xorm.Engine) error {
+	type Issue struct {
+		Points int `xorm:"NOT NULL DEFAULT 0"`
+	}
+	type Milestone struct {
+		NumPoints       int `xorm:"NOT NULL DEFAULT 0"`
+		NumClosedPoints int `xorm:"NOT NULL DEFAULT 0"`
+		ProgressMode    int `xorm:"NOT NULL DEFAULT 0"`
+	}
+	return x.Sync2(new(Issue), new(Milestone))
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addProtectedTagTable(x *xorm.Engine) error {
+	type ProtectedTag struct {
+		ID               int64 `xorm:"pk autoincr"`
+		RepoID           int64 `xorm:"INDEX"`
+		NamePattern      string
+		WhitelistUserIDs []int64        `xorm:"JSON TEXT"`
+		WhitelistTeamIDs []int64        `xorm:"JSON TEXT"`
+		CreatedUnix      util.TimeStamp `xorm:"created"`
+		UpdatedUnix      util.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync2(new(ProtectedTag))
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func addEmailNotificationsToUsers(x *xorm.Engine) error {
+	type User struct {
+		// 31 sets all the kinds of events, the users keep being notified of everything
+		EmailNotifications int `xorm:"NOT NULL DEFAULT 31"`
+	}
+	return x.Sync2(new(User))
+}
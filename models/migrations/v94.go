@@ -0,0 +1,29 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-xorm/xorm"
+)
+
+func addIssueMigrationTable(x *xorm.Engine) error {
+	type IssueMigration struct {
+		ID             int64 `xorm:"pk autoincr"`
+		RepoID         int64 `xorm:"UNIQUE"`
+		DoerID         int64
+		APIURL         string `xorm:"api_url"`
+		RemoteOwner    string
+		RemoteRepo     string
+		Token          string `xorm:"TEXT"`
+		Status         int    `xorm:"INDEX"`
+		LastIssueIndex int64
+		Error          string         `xorm:"TEXT"`
+		CreatedUnix    util.TimeStamp `xorm:"created"`
+		UpdatedUnix    util.TimeStamp `xorm:"updated"`
+	}
+	return x.Sync2(new(IssueMigration))
+}
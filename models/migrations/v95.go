@@ -0,0 +1,22 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func addHookTaskAttemptTable(x *xorm.Engine) error {
+	type HookTaskAttempt struct {
+		ID              int64 `xorm:"pk autoincr"`
+		RepoID          int64 `xorm:"INDEX"`
+		HookID          int64 `xorm:"INDEX"`
+		HookTaskID      int64 `xorm:"INDEX"`
+		Attempt         int
+		Delivered       int64
+		IsSucceed       bool
+		RequestContent  string `xorm:"TEXT"`
+		ResponseContent string `xorm:"TEXT"`
+	}
+	return x.Sync2(new(HookTaskAttempt))
+}
@@ -0,0 +1,14 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import "github.com/go-xorm/xorm"
+
+func clearDeletedApprovalsTeams(x *xorm.Engine) error {
+	// the approvals teams deleted before their protected branches were
+	// updated on deletion block the pull requests forever
+	_, err := x.Exec("UPDATE protected_branch SET approvals_team_id = 0 WHERE approvals_team_id <> 0 AND approvals_team_id NOT IN (SELECT id FROM team)")
+	return err
+}
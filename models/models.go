@@ -96,6 +96,7 @@ func init() {
 		new(LoginSource),
 		new(Webhook),
 		new(HookTask),
+		new(HookTaskAttempt),
 		new(Team),
 		new(OrgUser),
 		new(TeamUser),
@@ -111,6 +112,7 @@ func init() {
 		new(RepoRedirect),
 		new(ExternalLoginUser),
 		new(ProtectedBranch),
+		new(ProtectedTag),
 		new(UserOpenID),
 		new(IssueWatch),
 		new(CommitStatus),
@@ -120,11 +122,14 @@ func init() {
 		new(RepoIndexerStatus),
 		new(IssueDependency),
 		new(LFSLock),
+		new(LFSLockRelease),
 		new(Reaction),
 		new(IssueAssignees),
 		new(U2FRegistration),
 		new(TeamUnit),
 		new(Review),
+		new(UserSession),
+		new(IssueMigration),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -318,7 +323,8 @@ type Statistic struct {
 		Issue, Comment, Oauth, Follow,
 		Mirror, Release, LoginSource, Webhook,
 		Milestone, Label, HookTask,
-		Team, UpdateTask, Attachment int64
+		Team, UpdateTask, Attachment,
+		OpenIssue, OpenPull, UndeliveredHookTask int64
 	}
 }
 
@@ -345,6 +351,9 @@ func GetStatistic() (stats Statistic) {
 	stats.Counter.HookTask, _ = x.Count(new(HookTask))
 	stats.Counter.Team, _ = x.Count(new(Team))
 	stats.Counter.Attachment, _ = x.Count(new(Attachment))
+	stats.Counter.OpenIssue, _ = x.Where("is_closed = ? AND is_pull = ?", false, false).Count(new(Issue))
+	stats.Counter.OpenPull, _ = x.Where("is_closed = ? AND is_pull = ?", false, true).Count(new(Issue))
+	stats.Counter.UndeliveredHookTask, _ = x.Where("is_delivered = ?", false).Count(new(HookTask))
 	return
 }
 
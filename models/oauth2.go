@@ -5,9 +5,12 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
 
 	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/log"
 )
 
 // OAuth2Provider describes the display values of a single OAuth2 provider
@@ -110,6 +113,145 @@ func InitOAuth2() error {
 	return nil
 }
 
+// RegisterOAuth2ProviderIfMissing registers the provider of the login source
+// unless it already is. This retries the OpenID Connect discovery of providers
+// whose discovery document was unreachable on start up.
+func RegisterOAuth2ProviderIfMissing(source *LoginSource) error {
+	if oauth2.HasProvider(source.Name) {
+		return nil
+	}
+	oAuth2Config := source.OAuth2()
+	err := oauth2.RegisterProvider(source.Name, oAuth2Config.Provider, oAuth2Config.ClientID, oAuth2Config.ClientSecret, oAuth2Config.OpenIDConnectAutoDiscoveryURL, oAuth2Config.CustomURLMapping)
+	return wrapOpenIDConnectInitializeError(err, source.Name, oAuth2Config)
+}
+
+// syncOAuth2ExternalUsers refreshes the tokens of the users linked to the
+// OpenID Connect login source, and synchronizes their teams with the groups of
+// the refreshed ID token, so they follow the changes made in the provider
+// without signing in again.
+func syncOAuth2ExternalUsers(source *LoginSource) {
+	if source.OAuth2().Provider != "openidConnect" {
+		return
+	}
+	log.Trace("Doing: SyncExternalUsers[%s]", source.Name)
+
+	externalLoginUsers := make([]*ExternalLoginUser, 0, 10)
+	if err := x.Where("login_source_id = ? AND refresh_token != ''", source.ID).Find(&externalLoginUsers); err != nil {
+		log.Error(4, "SyncExternalUsers[%s]: %v", source.Name, err)
+		return
+	}
+
+	for _, externalLoginUser := range externalLoginUsers {
+		gothUser, err := RefreshExternalLoginUserToken(externalLoginUser)
+		if err != nil {
+			log.Warn("SyncExternalUsers[%s]: Error refreshing the tokens of user %d: %v", source.Name, externalLoginUser.UserID, err)
+			continue
+		}
+		if gothUser.RawData == nil {
+			continue
+		}
+		u, err := GetUserByID(externalLoginUser.UserID)
+		if err != nil {
+			log.Error(4, "SyncExternalUsers[%s]: GetUserByID [%d]: %v", source.Name, externalLoginUser.UserID, err)
+			continue
+		}
+		if err = SyncOAuth2GroupTeams(source, u, gothUser.RawData); err != nil {
+			log.Error(4, "SyncExternalUsers[%s]: Error synchronizing the teams of user %s: %v", source.Name, u.Name, err)
+		}
+	}
+}
+
+// claimGroups returns the set of groups listed in the given claim
+func claimGroups(claims map[string]interface{}, claimName string) map[string]bool {
+	groups := make(map[string]bool)
+	switch value := claims[claimName].(type) {
+	case string:
+		groups[value] = true
+	case []interface{}:
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups[name] = true
+			}
+		}
+	}
+	return groups
+}
+
+// ParseOAuth2GroupTeamMap parses the JSON object mapping the groups of an
+// OAuth2 login source to the teams of organizations, whose members are the
+// users having the group.
+func ParseOAuth2GroupTeamMap(groupTeamMap string) (map[string]map[string][]string, error) {
+	if len(groupTeamMap) == 0 {
+		return nil, nil
+	}
+	var mapping map[string]map[string][]string
+	if err := json.Unmarshal([]byte(groupTeamMap), &mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// SyncOAuth2GroupTeams adds the user to the teams mapped to the groups listed
+// in the group claim of the login source, and removes the user from the mapped
+// teams of the groups that are not listed. Organizations and teams of the
+// mapping which don't exist are skipped.
+func SyncOAuth2GroupTeams(source *LoginSource, u *User, claims map[string]interface{}) error {
+	oAuth2Config := source.OAuth2()
+	if len(oAuth2Config.GroupClaimName) == 0 || len(oAuth2Config.GroupTeamMap) == 0 {
+		return nil
+	}
+
+	groupTeamMap, err := ParseOAuth2GroupTeamMap(oAuth2Config.GroupTeamMap)
+	if err != nil {
+		return fmt.Errorf("parse group team map of login source %s: %v", source.Name, err)
+	}
+	groups := claimGroups(claims, oAuth2Config.GroupClaimName)
+
+	// A team mapped to several groups is kept as long as one of them is listed.
+	teams := make(map[int64]*Team)
+	member := make(map[int64]bool)
+	for group, orgTeams := range groupTeamMap {
+		for orgName, teamNames := range orgTeams {
+			org, err := GetOrgByName(orgName)
+			if err != nil {
+				if IsErrOrgNotExist(err) {
+					log.Warn("SyncOAuth2GroupTeams: organization %s of login source %s does not exist", orgName, source.Name)
+					continue
+				}
+				return err
+			}
+			for _, teamName := range teamNames {
+				team, err := org.GetTeam(teamName)
+				if err != nil {
+					if err == ErrTeamNotExist {
+						log.Warn("SyncOAuth2GroupTeams: team %s/%s of login source %s does not exist", orgName, teamName, source.Name)
+						continue
+					}
+					return err
+				}
+				teams[team.ID] = team
+				member[team.ID] = member[team.ID] || groups[group]
+			}
+		}
+	}
+
+	for id, team := range teams {
+		isMember, err := IsTeamMember(team.OrgID, team.ID, u.ID)
+		if err != nil {
+			return err
+		}
+		if member[id] && !isMember {
+			err = team.AddMember(u.ID)
+		} else if !member[id] && isMember {
+			err = team.RemoveMember(u.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("sync membership of team %d: %v", team.ID, err)
+		}
+	}
+	return nil
+}
+
 // wrapOpenIDConnectInitializeError is used to wrap the error but this cannot be done in modules/auth/oauth2
 // inside oauth2: import cycle not allowed models -> modules/auth/oauth2 -> models
 func wrapOpenIDConnectInitializeError(err error, providerName string, oAuth2Config *OAuth2Config) error {
@@ -0,0 +1,58 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOAuth2GroupTeamMap(t *testing.T) {
+	mapping, err := ParseOAuth2GroupTeamMap(`{"developers": {"org": ["team1", "team2"]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string][]string{"developers": {"org": {"team1", "team2"}}}, mapping)
+
+	mapping, err = ParseOAuth2GroupTeamMap("")
+	assert.NoError(t, err)
+	assert.Empty(t, mapping)
+
+	for _, invalid := range []string{"{", `["developers"]`, `{"developers": ["team1"]}`, `{"developers": {"org": "team1"}}`} {
+		_, err = ParseOAuth2GroupTeamMap(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSyncOAuth2GroupTeams(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	source := &LoginSource{
+		Name: "oidc",
+		Type: LoginOAuth2,
+		Cfg: &OAuth2Config{
+			Provider:       "openidConnect",
+			GroupClaimName: "groups",
+			GroupTeamMap:   `{"admins": {"user3": ["Owners"]}, "developers": {"user3": ["team1"], "nonexistent": ["team"]}}`,
+		},
+	}
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	// user4 is a member of team1 but not of Owners, see fixtures/team_user.yml
+	assert.NoError(t, SyncOAuth2GroupTeams(source, user, map[string]interface{}{
+		"groups": []interface{}{"admins", "unmapped"},
+	}))
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 1, UID: 4})
+	AssertNotExistsBean(t, &TeamUser{TeamID: 2, UID: 4})
+
+	assert.NoError(t, SyncOAuth2GroupTeams(source, user, map[string]interface{}{
+		"groups": "developers",
+	}))
+	AssertNotExistsBean(t, &TeamUser{TeamID: 1, UID: 4})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: 4})
+	CheckConsistencyFor(t, &Team{ID: 1}, &Team{ID: 2})
+
+	source.Cfg.(*OAuth2Config).GroupTeamMap = "{"
+	assert.Error(t, SyncOAuth2GroupTeams(source, user, nil))
+}
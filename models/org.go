@@ -32,6 +32,32 @@ func (org *User) IsOrgMember(uid int64) (bool, error) {
 	return IsOrganizationMember(org.ID, uid)
 }
 
+// IsRepoUnitDisabled returns true if the unit is disabled by default on the
+// repositories created in the organization.
+func (org *User) IsRepoUnitDisabled(tp UnitType) bool {
+	if tp.IsMust() {
+		return false
+	}
+	for _, disabled := range org.DisabledRepoUnits {
+		if disabled == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRepoUnits returns the unit types enabled on the repositories created in
+// the organization.
+func (org *User) NewRepoUnits() []UnitType {
+	units := make([]UnitType, 0, len(defaultRepoUnits))
+	for _, tp := range defaultRepoUnits {
+		if !org.IsRepoUnitDisabled(tp) {
+			units = append(units, tp)
+		}
+	}
+	return units
+}
+
 func (org *User) getTeam(e Engine, name string) (*Team, error) {
 	return getTeam(e, org.ID, name)
 }
@@ -122,6 +148,7 @@ func CreateOrganization(org, owner *User) (err error) {
 	}
 	org.UseCustomAvatar = true
 	org.MaxRepoCreation = -1
+	org.MaxRepoSize = -1
 	org.NumTeams = 1
 	org.NumMembers = 1
 	org.Type = UserTypeOrganization
@@ -418,6 +445,20 @@ func ChangeOrgUserStatus(orgID, uid int64, public bool) error {
 	return err
 }
 
+// IsTwoFactorRequired returns true if the organization requires two-factor
+// authentication and the user, who is not a site admin, has not enabled it.
+func IsTwoFactorRequired(org, user *User) (bool, error) {
+	return isTwoFactorRequired(x, org, user)
+}
+
+func isTwoFactorRequired(e Engine, org, user *User) (bool, error) {
+	if !org.IsOrganization() || !org.RequireTwoFactor || user == nil || user.IsAdmin {
+		return false, nil
+	}
+	has, err := hasTwoFactorByUID(e, user.ID)
+	return !has, err
+}
+
 // AddOrgUser adds new user to given organization.
 func AddOrgUser(orgID, uid int64) error {
 	isAlreadyMember, err := IsOrganizationMember(orgID, uid)
@@ -425,6 +466,20 @@ func AddOrgUser(orgID, uid int64) error {
 		return err
 	}
 
+	org, err := GetUserByID(orgID)
+	if err != nil {
+		return err
+	}
+	user, err := GetUserByID(uid)
+	if err != nil {
+		return err
+	}
+	if required, err := isTwoFactorRequired(x, org, user); err != nil {
+		return err
+	} else if required {
+		return ErrTwoFactorRequired{orgID, uid}
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
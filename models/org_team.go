@@ -474,6 +474,14 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// The protected branches of the organization stop requiring its approval.
+	if _, err := sess.
+		Where("approvals_team_id=?", t.ID).
+		Cols("approvals_team_id").
+		Update(&ProtectedBranch{ApprovalsTeamID: 0}); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
@@ -234,12 +234,20 @@ func TestUpdateTeam2(t *testing.T) {
 func TestDeleteTeam(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
+	protectBranch := &ProtectedBranch{RepoID: 3, BranchName: "master", ApprovalsTeamID: 2, RequireTeamApproval: true}
+	_, err := x.Insert(protectBranch)
+	assert.NoError(t, err)
+
 	team := AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
 	assert.NoError(t, DeleteTeam(team))
 	AssertNotExistsBean(t, &Team{ID: team.ID})
 	AssertNotExistsBean(t, &TeamRepo{TeamID: team.ID})
 	AssertNotExistsBean(t, &TeamUser{TeamID: team.ID})
 
+	// the protected branch does not require the approval of the team anymore
+	protectBranch = AssertExistsAndLoadBean(t, &ProtectedBranch{ID: protectBranch.ID}).(*ProtectedBranch)
+	assert.Zero(t, protectBranch.ApprovalsTeamID)
+
 	// check that team members don't have "leftover" access to repos
 	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
 	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
@@ -0,0 +1,144 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ProtectedTag represents the tags of a repository only some users can create,
+// update or delete.
+type ProtectedTag struct {
+	ID               int64 `xorm:"pk autoincr"`
+	RepoID           int64 `xorm:"INDEX"`
+	NamePattern      string
+	WhitelistUserIDs []int64        `xorm:"JSON TEXT"`
+	WhitelistTeamIDs []int64        `xorm:"JSON TEXT"`
+	CreatedUnix      util.TimeStamp `xorm:"created"`
+	UpdatedUnix      util.TimeStamp `xorm:"updated"`
+}
+
+// Match returns if the tag name matches the pattern of the protected tag.
+func (protectTag *ProtectedTag) Match(tagName string) bool {
+	re, err := util.CompileGlob(protectTag.NamePattern, '/')
+	if err != nil {
+		log.Error(4, "CompileGlob [pattern: %s]: %v", protectTag.NamePattern, err)
+		return false
+	}
+	return re.MatchString(tagName)
+}
+
+// CanUserPush returns if some user could create, update or delete the tags
+// matching the protected tag.
+func (protectTag *ProtectedTag) CanUserPush(userID int64) bool {
+	if base.Int64sContains(protectTag.WhitelistUserIDs, userID) {
+		return true
+	}
+
+	if len(protectTag.WhitelistTeamIDs) == 0 {
+		return false
+	}
+
+	in, err := IsUserInTeams(userID, protectTag.WhitelistTeamIDs)
+	if err != nil {
+		log.Error(1, "IsUserInTeams:", err)
+		return false
+	}
+	return in
+}
+
+// ValidateNamePattern returns an error if the name pattern is not a valid glob.
+func (protectTag *ProtectedTag) ValidateNamePattern() error {
+	if len(protectTag.NamePattern) == 0 {
+		return ErrInvalidProtectedTagPattern{protectTag.NamePattern}
+	}
+	if _, err := util.CompileGlob(protectTag.NamePattern, '/'); err != nil {
+		return ErrInvalidProtectedTagPattern{protectTag.NamePattern}
+	}
+	return nil
+}
+
+// GetProtectedTags returns the protected tags of the repository.
+func (repo *Repository) GetProtectedTags() ([]*ProtectedTag, error) {
+	protectedTags := make([]*ProtectedTag, 0, 5)
+	return protectedTags, x.Where("repo_id = ?", repo.ID).Asc("id").Find(&protectedTags)
+}
+
+// GetProtectedTagByID returns the protected tag of the repository with the given ID.
+func (repo *Repository) GetProtectedTagByID(id int64) (*ProtectedTag, error) {
+	protectTag := &ProtectedTag{ID: id, RepoID: repo.ID}
+	has, err := x.Get(protectTag)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProtectedTagNotExist{id, repo.ID}
+	}
+	return protectTag, nil
+}
+
+// CanUserPushTag returns if the user is allowed to create, update or delete
+// the tag. Tags which do not match any protected tag are not restricted,
+// otherwise the user has to be whitelisted by one of the protected tags
+// the tag matches.
+func CanUserPushTag(repoID int64, tagName string, userID int64) (bool, error) {
+	protectedTags, err := (&Repository{ID: repoID}).GetProtectedTags()
+	if err != nil {
+		return false, err
+	}
+
+	isProtected := false
+	for _, protectTag := range protectedTags {
+		if !protectTag.Match(tagName) {
+			continue
+		}
+		if protectTag.CanUserPush(userID) {
+			return true, nil
+		}
+		isProtected = true
+	}
+	return !isProtected, nil
+}
+
+// UpdateProtectedTag saves a protected tag of the repository. If ID is 0, it
+// creates a new record. The whitelists only keep the users with write access
+// to the repository and the teams with access to it.
+func UpdateProtectedTag(repo *Repository, protectTag *ProtectedTag, userIDs, teamIDs []int64) (err error) {
+	if err = protectTag.ValidateNamePattern(); err != nil {
+		return err
+	}
+	if err = repo.GetOwner(); err != nil {
+		return err
+	}
+
+	if protectTag.WhitelistUserIDs, err = updateUserWhitelist(repo, protectTag.WhitelistUserIDs, userIDs); err != nil {
+		return err
+	}
+	if repo.Owner.IsOrganization() {
+		if protectTag.WhitelistTeamIDs, err = updateTeamWhitelist(repo, protectTag.WhitelistTeamIDs, teamIDs); err != nil {
+			return err
+		}
+	}
+
+	protectTag.RepoID = repo.ID
+	if protectTag.ID == 0 {
+		_, err = x.Insert(protectTag)
+		return err
+	}
+	_, err = x.ID(protectTag.ID).AllCols().Update(protectTag)
+	return err
+}
+
+// DeleteProtectedTag removes a protected tag of the repository.
+func (repo *Repository) DeleteProtectedTag(id int64) error {
+	affected, err := x.Delete(&ProtectedTag{ID: id, RepoID: repo.ID})
+	if err != nil {
+		return err
+	} else if affected != 1 {
+		return ErrProtectedTagNotExist{id, repo.ID}
+	}
+	return nil
+}
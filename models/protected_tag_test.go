@@ -0,0 +1,87 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedTag_Match(t *testing.T) {
+	protectTag := &ProtectedTag{NamePattern: "v*"}
+	assert.True(t, protectTag.Match("v1.0"))
+	assert.True(t, protectTag.Match("v"))
+	assert.False(t, protectTag.Match("release-v1.0"))
+	assert.False(t, protectTag.Match("v1/rc"))
+
+	protectTag.NamePattern = "release/**"
+	assert.True(t, protectTag.Match("release/1.0/rc1"))
+	assert.False(t, protectTag.Match("release"))
+
+	protectTag.NamePattern = "release/[z-a]"
+	assert.False(t, protectTag.Match("release/a"))
+}
+
+func TestProtectedTag_ValidateNamePattern(t *testing.T) {
+	for _, pattern := range []string{"v*", "release/**", "v[0-9].*"} {
+		assert.NoError(t, (&ProtectedTag{NamePattern: pattern}).ValidateNamePattern(), pattern)
+	}
+	for _, pattern := range []string{"", "release/[z-a]"} {
+		err := (&ProtectedTag{NamePattern: pattern}).ValidateNamePattern()
+		assert.True(t, IsErrInvalidProtectedTagPattern(err), pattern)
+	}
+}
+
+func TestCanUserPushTag(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	protectTag := &ProtectedTag{NamePattern: "v*"}
+	assert.NoError(t, UpdateProtectedTag(repo, protectTag, []int64{2, 5}, nil))
+	// only the users with write access to the repository are kept
+	assert.Equal(t, []int64{2}, protectTag.WhitelistUserIDs)
+	assert.NoError(t, UpdateProtectedTag(repo, &ProtectedTag{NamePattern: "release-*"}, nil, []int64{2}))
+
+	// user2 is whitelisted for both patterns, user4 only through team 2
+	for _, c := range []struct {
+		userID  int64
+		tagName string
+		canPush bool
+	}{
+		{2, "v1.0", true},
+		{4, "v1.0", false},
+		{2, "release-1.0", true},
+		{4, "release-1.0", true},
+		{5, "release-1.0", false},
+		{4, "v1/rc", true},
+		{4, "other", true},
+	} {
+		canPush, err := CanUserPushTag(repo.ID, c.tagName, c.userID)
+		assert.NoError(t, err)
+		assert.Equal(t, c.canPush, canPush, "user %d, tag %s", c.userID, c.tagName)
+	}
+
+	// the tags of other repositories are not restricted
+	canPush, err := CanUserPushTag(1, "v1.0", 4)
+	assert.NoError(t, err)
+	assert.True(t, canPush)
+
+	// a tag matching several patterns can be pushed if any of them allows it
+	assert.NoError(t, UpdateProtectedTag(repo, &ProtectedTag{NamePattern: "release-1.*"}, nil, nil))
+	canPush, err = CanUserPushTag(repo.ID, "release-1.0", 4)
+	assert.NoError(t, err)
+	assert.True(t, canPush)
+
+	assert.True(t, IsErrInvalidProtectedTagPattern(UpdateProtectedTag(repo, &ProtectedTag{NamePattern: ""}, nil, nil)))
+
+	protectTags, err := repo.GetProtectedTags()
+	assert.NoError(t, err)
+	assert.Len(t, protectTags, 3)
+	assert.NoError(t, repo.DeleteProtectedTag(protectTags[0].ID))
+	assert.True(t, IsErrProtectedTagNotExist(repo.DeleteProtectedTag(protectTags[0].ID)))
+	_, err = repo.GetProtectedTagByID(protectTags[0].ID)
+	assert.True(t, IsErrProtectedTagNotExist(err))
+}
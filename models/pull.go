@@ -30,6 +30,11 @@ import (
 
 var pullRequestQueue = sync.NewUniqueQueue(setting.Repository.PullRequestQueueLength)
 
+// PullRequestQueueLen returns the number of pull requests waiting to be tested.
+func PullRequestQueueLen() int {
+	return pullRequestQueue.Len()
+}
+
 // PullRequestType defines pull request type
 type PullRequestType int
 
@@ -59,6 +64,7 @@ type PullRequest struct {
 	IssueID int64  `xorm:"INDEX"`
 	Issue   *Issue `xorm:"-"`
 	Index   int64
+	IsDraft bool `xorm:"NOT NULL DEFAULT false"`
 
 	HeadRepoID      int64       `xorm:"INDEX"`
 	HeadRepo        *Repository `xorm:"-"`
@@ -153,6 +159,14 @@ func (pr *PullRequest) GetGitRefName() string {
 	return fmt.Sprintf("refs/pull/%d/head", pr.Index)
 }
 
+// GetChangedFiles returns the paths of the files changed by the pull request.
+func (pr *PullRequest) GetChangedFiles() ([]string, error) {
+	if err := pr.GetBaseRepo(); err != nil {
+		return nil, fmt.Errorf("GetBaseRepo: %v", err)
+	}
+	return GetChangedFilesBetween(pr.BaseRepo.RepoPath(), pr.MergeBase, pr.GetGitRefName())
+}
+
 // APIFormat assumes following fields have been assigned with valid values:
 // Required - Issue
 // Optional - Merger
@@ -241,7 +255,7 @@ func (pr *PullRequest) apiFormat(e Engine) *api.PullRequest {
 	}
 
 	if pr.Status != PullRequestStatusChecking {
-		mergeable := pr.Status != PullRequestStatusConflict && !pr.IsWorkInProgress()
+		mergeable := pr.Status != PullRequestStatusConflict && !pr.IsWorkInProgress() && !pr.IsDraft
 		apiPullRequest.Mergeable = mergeable
 	}
 	if pr.HasMerged {
@@ -328,9 +342,39 @@ func (pr *PullRequest) CheckUserAllowedToMerge(doer *User) (err error) {
 	return nil
 }
 
+// getSingleCommitAuthor returns the author of the commits of headBranch which
+// are not in baseBranch, or nil if they have several authors.
+func getSingleCommitAuthor(repoPath, baseBranch, headBranch string) (*git.Signature, error) {
+	stdout, stderr, err := process.GetManager().ExecDir(-1, repoPath,
+		fmt.Sprintf("PullRequest.Merge (git log): %s", repoPath),
+		"git", "log", "--format=%aN%x00%aE", baseBranch+".."+headBranch)
+	if err != nil {
+		return nil, fmt.Errorf("git log [%s]: %v - %s", repoPath, err, stderr)
+	}
+
+	var author *git.Signature
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := strings.SplitN(line, "\x00", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		// commits are listed from the latest, whose author name is kept
+		if author == nil {
+			author = &git.Signature{Name: fields[0], Email: fields[1]}
+		} else if !strings.EqualFold(author.Email, fields[1]) {
+			return nil, nil
+		}
+	}
+	return author, nil
+}
+
 // Merge merges pull request to base repository.
 // FIXME: add repoWorkingPull make sure two merges does not happen at same time.
 func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle MergeStyle, message string) (err error) {
+	if pr.IsDraft {
+		return ErrPullRequestIsDraft{pr.ID}
+	}
+
 	if err = pr.GetHeadRepo(); err != nil {
 		return fmt.Errorf("GetHeadRepo: %v", err)
 	} else if err = pr.GetBaseRepo(); err != nil {
@@ -478,9 +522,28 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 			"git", "merge", "-q", "--squash", "head_repo/"+pr.HeadBranch); err != nil {
 			return fmt.Errorf("git merge --squash [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
 		}
+		if err = pr.Issue.LoadPoster(); err != nil {
+			return fmt.Errorf("LoadPoster: %v", err)
+		}
 		sig := pr.Issue.Poster.NewGitSig()
-		if _, stderr, err = process.GetManager().ExecDir(-1, tmpBasePath,
-			fmt.Sprintf("PullRequest.Merge (git squash): %s", tmpBasePath),
+		var env []string
+		if prConfig.SquashUseCommitAuthor {
+			// Attribute the commit to the author of the squashed commits when
+			// there is only one, the committer being the merger.
+			author, err := getSingleCommitAuthor(tmpBasePath, pr.BaseBranch, "head_repo/"+pr.HeadBranch)
+			if err != nil {
+				return err
+			} else if author != nil {
+				sig = author
+			}
+			committer := doer.NewGitSig()
+			env = append(os.Environ(),
+				"GIT_COMMITTER_NAME="+committer.Name,
+				"GIT_COMMITTER_EMAIL="+committer.Email,
+			)
+		}
+		if _, stderr, err = process.GetManager().ExecDirEnv(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git squash): %s", tmpBasePath), env,
 			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
 			"-m", message); err != nil {
 			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
@@ -1171,6 +1234,43 @@ func (prs PullRequestList) LoadAttributes() error {
 	return prs.loadAttributes(x)
 }
 
+// GetCombinedCommitStatuses returns the combined commit status of the head
+// commit of the pull requests of the base repository by pull request ID.
+// Pull requests without any status are left out.
+func (prs PullRequestList) GetCombinedCommitStatuses(baseRepo *Repository) (map[int64]*CommitStatus, error) {
+	commitStatuses := make(map[int64]*CommitStatus, len(prs))
+	if len(prs) == 0 {
+		return commitStatuses, nil
+	}
+
+	gitRepo, err := git.OpenRepository(baseRepo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	headCommitIDs := make(map[int64]string, len(prs))
+	shas := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		sha, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+		if err != nil {
+			// The head reference is missing until the pull request is pushed.
+			continue
+		}
+		headCommitIDs[pr.ID] = sha
+		shas = append(shas, sha)
+	}
+
+	statuses, err := GetCombinedCommitStatuses(baseRepo, shas)
+	if err != nil {
+		return nil, err
+	}
+	for id, sha := range headCommitIDs {
+		if status, ok := statuses[sha]; ok {
+			commitStatuses[id] = status
+		}
+	}
+	return commitStatuses, nil
+}
+
 func (prs PullRequestList) invalidateCodeComments(e Engine, doer *User, repo *git.Repository, branch string) error {
 	if len(prs) == 0 {
 		return nil
@@ -1205,6 +1305,8 @@ func addHeadRepoTasks(prs []*PullRequest) {
 		} else if err := pr.PushToBaseRepo(); err != nil {
 			log.Error(4, "PushToBaseRepo: %v", err)
 			continue
+		} else if err := pr.RequestCodeOwnerReviews(); err != nil {
+			log.Error(4, "RequestCodeOwnerReviews [pr_id: %d]: %v", pr.ID, err)
 		}
 
 		pr.AddToTaskQueue()
@@ -1326,6 +1428,22 @@ func (pr *PullRequest) IsWorkInProgress() bool {
 	return false
 }
 
+// MarkReadyForReview takes the pull request out of the draft state, so that
+// it can be merged. Closed and merged pull requests are left as they are.
+func (pr *PullRequest) MarkReadyForReview() error {
+	if !pr.IsDraft {
+		return nil
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return ErrPullRequestNotOpen{pr.ID}
+	}
+	pr.IsDraft = false
+	return pr.UpdateCols("is_draft")
+}
+
 // GetWorkInProgressPrefix returns the prefix used to mark the pull request as a work in progress.
 // It returns an empty string when none were found
 func (pr *PullRequest) GetWorkInProgressPrefix() string {
@@ -0,0 +1,158 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/process"
+)
+
+// PullRequestDivergenceState describes how the head branch of a pull request
+// relates to its base branch.
+type PullRequestDivergenceState string
+
+// Enumerate all the divergence states
+const (
+	// The head branch contains all the commits of the base branch.
+	PullRequestDivergenceUpToDate PullRequestDivergenceState = "up_to_date"
+	// The base branch has commits the head branch does not have, and the
+	// head branch has no commits of its own.
+	PullRequestDivergenceBehind PullRequestDivergenceState = "behind"
+	// Both branches have commits the other one does not have.
+	PullRequestDivergenceDiverged PullRequestDivergenceState = "diverged"
+	// The head repository or branch does not exist anymore.
+	PullRequestDivergenceHeadDeleted PullRequestDivergenceState = "head_deleted"
+	// The base branch does not exist anymore.
+	PullRequestDivergenceBaseDeleted PullRequestDivergenceState = "base_deleted"
+)
+
+// PullRequestDivergence represents how far the head branch of a pull request
+// diverged from its base branch.
+type PullRequestDivergence struct {
+	State PullRequestDivergenceState `json:"state"`
+	// number of commits of the head branch missing in the base branch
+	Ahead int `json:"ahead_by"`
+	// number of commits of the base branch missing in the head branch
+	Behind int `json:"behind_by"`
+	// CanFastForward is set when the head branch can be updated to the base
+	// branch without a merge commit.
+	CanFastForward    bool   `json:"can_fast_forward"`
+	BaseCommitID      string `json:"base_commit,omitempty"`
+	HeadCommitID      string `json:"head_commit,omitempty"`
+	MergeBaseCommitID string `json:"merge_base_commit,omitempty"`
+}
+
+func pullDivergenceCacheKey(repoID int64, baseCommitID, headCommitID string) string {
+	return fmt.Sprintf("pull_divergence_%d_%s_%s", repoID, baseCommitID, headCommitID)
+}
+
+// GetDivergence returns how far the head branch of the pull request diverged
+// from its base branch. The branches are compared as they are now, the head
+// branch being possibly in another repository whose objects are then only
+// read alongside those of the base repository.
+func (pr *PullRequest) GetDivergence() (*PullRequestDivergence, error) {
+	if err := pr.GetHeadRepo(); err != nil {
+		return nil, err
+	}
+	if err := pr.GetBaseRepo(); err != nil {
+		return nil, err
+	}
+
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !baseGitRepo.IsBranchExist(pr.BaseBranch) {
+		return &PullRequestDivergence{State: PullRequestDivergenceBaseDeleted}, nil
+	}
+	baseCommitID, err := baseGitRepo.GetBranchCommitID(pr.BaseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetBranchCommitID(base): %v", err)
+	}
+
+	if pr.HeadRepo == nil {
+		return &PullRequestDivergence{State: PullRequestDivergenceHeadDeleted, BaseCommitID: baseCommitID}, nil
+	}
+	headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	if !headGitRepo.IsBranchExist(pr.HeadBranch) {
+		return &PullRequestDivergence{State: PullRequestDivergenceHeadDeleted, BaseCommitID: baseCommitID}, nil
+	}
+	headCommitID, err := headGitRepo.GetBranchCommitID(pr.HeadBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetBranchCommitID(head): %v", err)
+	}
+
+	env := []string{"GIT_DIR=" + baseGitRepo.Path}
+	if pr.HeadRepoID != pr.BaseRepoID {
+		env = append(env, "GIT_ALTERNATE_OBJECT_DIRECTORIES="+filepath.Join(headGitRepo.Path, "objects"))
+	}
+	data, err := cache.GetString(pullDivergenceCacheKey(pr.BaseRepoID, baseCommitID, headCommitID), func() (string, error) {
+		divergence, err := getDivergence(env, baseCommitID, headCommitID)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(divergence)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var divergence *PullRequestDivergence
+	if err = json.Unmarshal([]byte(data), &divergence); err != nil {
+		return nil, fmt.Errorf("decode divergence [pull_id: %d]: %v", pr.ID, err)
+	}
+	return divergence, nil
+}
+
+// getDivergence returns how far the head commit diverged from the base
+// commit, git being run with the given environment.
+func getDivergence(env []string, baseCommitID, headCommitID string) (*PullRequestDivergence, error) {
+	divergence := &PullRequestDivergence{
+		BaseCommitID: baseCommitID,
+		HeadCommitID: headCommitID,
+	}
+	// Unrelated histories have no merge base but can still be counted.
+	mergeBase, _, _ := process.GetManager().ExecDirEnv(-1, "", fmt.Sprintf("getDivergence (git merge-base): %s %s", baseCommitID, headCommitID),
+		env, "git", "merge-base", baseCommitID, headCommitID)
+	divergence.MergeBaseCommitID = strings.TrimSpace(mergeBase)
+
+	stdout, stderr, err := process.GetManager().ExecDirEnv(-1, "", fmt.Sprintf("getDivergence (git rev-list): %s %s", baseCommitID, headCommitID),
+		env, "git", "rev-list", "--left-right", "--count", baseCommitID+"..."+headCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list: %v %v", stderr, err)
+	}
+	counts := strings.Fields(stdout)
+	if len(counts) != 2 {
+		return nil, fmt.Errorf("unexpected rev-list output: %q", stdout)
+	}
+	if divergence.Behind, err = strconv.Atoi(counts[0]); err != nil {
+		return nil, fmt.Errorf("unexpected rev-list output: %q", stdout)
+	}
+	if divergence.Ahead, err = strconv.Atoi(counts[1]); err != nil {
+		return nil, fmt.Errorf("unexpected rev-list output: %q", stdout)
+	}
+
+	switch {
+	case divergence.Behind == 0:
+		divergence.State = PullRequestDivergenceUpToDate
+	case divergence.Ahead == 0:
+		divergence.State = PullRequestDivergenceBehind
+		divergence.CanFastForward = true
+	default:
+		divergence.State = PullRequestDivergenceDiverged
+	}
+	return divergence, nil
+}
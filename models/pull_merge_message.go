@@ -0,0 +1,135 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+var mergeMessagePlaceholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// MergeMessagePlaceholders are the placeholders a merge message template may
+// use, written as {name}.
+var MergeMessagePlaceholders = []string{
+	"title",
+	"index",
+	"author",
+	"approvers",
+	"head_branch",
+	"head_repo",
+	"base_branch",
+	"base_repo",
+	"default_message",
+}
+
+// ValidateMergeMessageTemplate returns an error if the merge message template
+// uses an unknown placeholder.
+func ValidateMergeMessageTemplate(tmpl string) error {
+	for _, match := range mergeMessagePlaceholderPattern.FindAllStringSubmatch(tmpl, -1) {
+		known := false
+		for _, name := range MergeMessagePlaceholders {
+			if match[1] == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return ErrUnknownMergeMessagePlaceholder{match[1]}
+		}
+	}
+	return nil
+}
+
+func (pr *PullRequest) getDefaultMergeMessage(mergeStyle MergeStyle) string {
+	if mergeStyle == MergeStyleSquash {
+		return pr.GetDefaultSquashMessage()
+	}
+	return pr.GetDefaultMergeMessage()
+}
+
+// GetMergeMessage returns the message of the commit created when merging the
+// pull request with given style. It is rendered from the merge message
+// template of the base repository, or is the default message if there is no
+// valid template. Rebasing creates no commit, so its message is empty.
+func (pr *PullRequest) GetMergeMessage(mergeStyle MergeStyle) string {
+	if mergeStyle == MergeStyleRebase {
+		return ""
+	}
+
+	defaultMessage := pr.getDefaultMergeMessage(mergeStyle)
+	if err := pr.GetBaseRepo(); err != nil {
+		log.Error(4, "GetBaseRepo: %v", err)
+		return defaultMessage
+	}
+	prUnit, err := pr.BaseRepo.GetUnit(UnitTypePullRequests)
+	if err != nil {
+		log.Error(4, "GetUnit: %v", err)
+		return defaultMessage
+	}
+	tmpl := prUnit.PullRequestsConfig().MergeMessageTemplate
+	if len(strings.TrimSpace(tmpl)) == 0 {
+		return defaultMessage
+	}
+
+	message, err := pr.renderMergeMessage(tmpl, defaultMessage)
+	if err != nil {
+		log.Error(4, "renderMergeMessage [pr_id: %d]: %v", pr.ID, err)
+		return defaultMessage
+	}
+	return message
+}
+
+func (pr *PullRequest) renderMergeMessage(tmpl, defaultMessage string) (string, error) {
+	if err := ValidateMergeMessageTemplate(tmpl); err != nil {
+		return "", err
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return "", fmt.Errorf("LoadIssue: %v", err)
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return "", fmt.Errorf("LoadPoster: %v", err)
+	}
+	reviewers, err := GetReviewersByPullID(pr.Issue.ID)
+	if err != nil {
+		return "", fmt.Errorf("GetReviewersByPullID: %v", err)
+	}
+	approvers := make([]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		if reviewer.Type == ReviewTypeApprove {
+			approvers = append(approvers, reviewer.Name)
+		}
+	}
+	sort.Strings(approvers)
+
+	if pr.HeadRepo == nil {
+		if err = pr.GetHeadRepo(); err != nil {
+			return "", err
+		}
+	}
+	headRepo := pr.HeadUserName
+	if pr.HeadRepo != nil {
+		headRepo += "/" + pr.HeadRepo.Name
+	}
+	values := map[string]string{
+		"title":           pr.Issue.Title,
+		"index":           fmt.Sprintf("%d", pr.Index),
+		"author":          pr.Issue.Poster.Name,
+		"approvers":       strings.Join(approvers, ", "),
+		"head_branch":     pr.HeadBranch,
+		"head_repo":       headRepo,
+		"base_branch":     pr.BaseBranch,
+		"base_repo":       pr.BaseRepo.FullName(),
+		"default_message": defaultMessage,
+	}
+	message := mergeMessagePlaceholderPattern.ReplaceAllStringFunc(tmpl, func(placeholder string) string {
+		return values[placeholder[1:len(placeholder)-1]]
+	})
+	return strings.TrimSpace(message), nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMergeMessageTemplate(t *testing.T) {
+	assert.NoError(t, ValidateMergeMessageTemplate(""))
+	assert.NoError(t, ValidateMergeMessageTemplate("{title} (#{index})\n\nChangelog: {default_message}"))
+	assert.NoError(t, ValidateMergeMessageTemplate("Keeps {Braces} and { spaces }"))
+
+	err := ValidateMergeMessageTemplate("{title} by {poster}")
+	assert.True(t, IsErrUnknownMergeMessagePlaceholder(err))
+	assert.Equal(t, "poster", err.(ErrUnknownMergeMessagePlaceholder).Name)
+}
+
+func setMergeMessageTemplate(t *testing.T, repoID int64, tmpl string) {
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: repoID}).(*Repository)
+	prUnit, err := repo.GetUnit(UnitTypePullRequests)
+	assert.NoError(t, err)
+	prUnit.PullRequestsConfig().MergeMessageTemplate = tmpl
+	assert.NoError(t, UpdateRepositoryUnits(repo, []RepoUnit{*prUnit}))
+}
+
+func TestPullRequest_GetMergeMessage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	loadPR := func() *PullRequest {
+		return AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	}
+
+	// without a template the default messages are used
+	pr := loadPR()
+	assert.Equal(t, pr.GetDefaultMergeMessage(), pr.GetMergeMessage(MergeStyleMerge))
+	assert.Equal(t, pr.GetDefaultMergeMessage(), pr.GetMergeMessage(MergeStyleRebaseMerge))
+	assert.Equal(t, pr.GetDefaultSquashMessage(), pr.GetMergeMessage(MergeStyleSquash))
+
+	setMergeMessageTemplate(t, 1, "{title} (#{index})\n\n{default_message}\n\nAuthor: {author}\nApproved-by: {approvers}\nBranch: {head_repo}:{head_branch} -> {base_repo}:{base_branch}")
+	for _, test := range []struct {
+		style    MergeStyle
+		expected string
+	}{
+		{MergeStyleMerge, "issue3 (#3)\n\nMerge branch 'branch2' of user1/repo1 into master\n\nAuthor: user1\nApproved-by: user4\nBranch: user1/repo1:branch2 -> user2/repo1:master"},
+		{MergeStyleRebaseMerge, "issue3 (#3)\n\nMerge branch 'branch2' of user1/repo1 into master\n\nAuthor: user1\nApproved-by: user4\nBranch: user1/repo1:branch2 -> user2/repo1:master"},
+		{MergeStyleSquash, "issue3 (#3)\n\nissue3 (#3)\n\nAuthor: user1\nApproved-by: user4\nBranch: user1/repo1:branch2 -> user2/repo1:master"},
+		{MergeStyleRebase, ""},
+	} {
+		assert.Equal(t, test.expected, loadPR().GetMergeMessage(test.style), "style %s", test.style)
+	}
+
+	// an unknown placeholder falls back to the default message
+	setMergeMessageTemplate(t, 1, "{title} {unknown}")
+	pr = loadPR()
+	assert.Equal(t, pr.GetDefaultMergeMessage(), pr.GetMergeMessage(MergeStyleMerge))
+	assert.Equal(t, pr.GetDefaultSquashMessage(), pr.GetMergeMessage(MergeStyleSquash))
+}
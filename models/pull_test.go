@@ -268,3 +268,55 @@ func TestPullRequest_GetWorkInProgressPrefixWorkInProgress(t *testing.T) {
 	pr.Issue.Title = "[wip] " + original
 	assert.Equal(t, "[wip]", pr.GetWorkInProgressPrefix())
 }
+
+func TestPullRequest_Merge_Draft(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.IsDraft = true
+	assert.NoError(t, pr.UpdateCols("is_draft"))
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.True(t, pr.CanAutoMerge())
+	err := pr.Merge(doer, nil, MergeStyleMerge, "")
+	assert.True(t, IsErrPullRequestIsDraft(err))
+
+	assert.NoError(t, pr.MarkReadyForReview())
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.False(t, pr.IsDraft)
+
+	// a merged draft stays a draft
+	pr = AssertExistsAndLoadBean(t, &PullRequest{ID: 1}).(*PullRequest)
+	pr.IsDraft = true
+	assert.NoError(t, pr.UpdateCols("is_draft"))
+	err = pr.MarkReadyForReview()
+	assert.True(t, IsErrPullRequestNotOpen(err))
+	AssertExistsAndLoadBean(t, &PullRequest{ID: 1, IsDraft: true})
+}
+
+func TestPullRequest_GetDivergence(t *testing.T) {
+	PrepareTestEnv(t)
+
+	// the head branch of the fixture does not exist
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	divergence, err := pr.GetDivergence()
+	assert.NoError(t, err)
+	assert.Equal(t, PullRequestDivergenceHeadDeleted, divergence.State)
+	assert.NotEmpty(t, divergence.BaseCommitID)
+
+	pr.BaseBranch = "unknown"
+	divergence, err = pr.GetDivergence()
+	assert.NoError(t, err)
+	assert.Equal(t, PullRequestDivergenceBaseDeleted, divergence.State)
+
+	// a pull request between two branches of the same repository
+	pr.BaseBranch = "master"
+	pr.HeadBranch = "develop"
+	divergence, err = pr.GetDivergence()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, divergence.Ahead)
+	assert.Equal(t, 0, divergence.Behind)
+	assert.Equal(t, PullRequestDivergenceUpToDate, divergence.State)
+	assert.Equal(t, divergence.BaseCommitID, divergence.HeadCommitID)
+}
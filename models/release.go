@@ -6,6 +6,7 @@ package models
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
@@ -111,10 +112,17 @@ func IsReleaseExist(repoID int64, tagName string) (bool, error) {
 	return x.Get(&Release{RepoID: repoID, LowerTagName: strings.ToLower(tagName)})
 }
 
-func createTag(gitRepo *git.Repository, rel *Release) error {
+func createTag(gitRepo *git.Repository, rel *Release, doerID int64) error {
 	// Only actual create when publish.
 	if !rel.IsDraft {
 		if !gitRepo.IsTagExist(rel.TagName) {
+			canPush, err := CanUserPushTag(rel.RepoID, rel.TagName, doerID)
+			if err != nil {
+				return fmt.Errorf("CanUserPushTag: %v", err)
+			} else if !canPush {
+				return ErrProtectedTagName{rel.TagName}
+			}
+
 			commit, err := gitRepo.GetCommit(rel.Target)
 			if err != nil {
 				return fmt.Errorf("GetCommit: %v", err)
@@ -172,6 +180,43 @@ func addReleaseAttachments(releaseID int64, attachmentUUIDs []string) (err error
 	return
 }
 
+func isReleaseAttachmentNameExist(e Engine, releaseID int64, name string, excludeID int64) (bool, error) {
+	return e.Where("release_id = ? AND name = ? AND id != ?", releaseID, name, excludeID).Exist(new(Attachment))
+}
+
+// NewReleaseAttachment stores the given file as a new attachment of the
+// release. Attachment names must be unique within a release.
+func NewReleaseAttachment(rel *Release, name string, buf []byte, file io.Reader) (*Attachment, error) {
+	if has, err := isReleaseAttachmentNameExist(x, rel.ID, name, 0); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrReleaseAttachmentAlreadyExist{rel.ID, name}
+	}
+
+	attach, err := NewAttachment(name, buf, file)
+	if err != nil {
+		return nil, err
+	}
+	attach.ReleaseID = rel.ID
+	if _, err = x.ID(attach.ID).Cols("release_id").Update(attach); err != nil {
+		return nil, fmt.Errorf("update attachment [%d]: %v", attach.ID, err)
+	}
+	return attach, nil
+}
+
+// RenameReleaseAttachment changes the name of a release attachment.
+func RenameReleaseAttachment(attach *Attachment, name string) error {
+	if has, err := isReleaseAttachmentNameExist(x, attach.ReleaseID, name, attach.ID); err != nil {
+		return err
+	} else if has {
+		return ErrReleaseAttachmentAlreadyExist{attach.ReleaseID, name}
+	}
+
+	attach.Name = name
+	_, err := x.ID(attach.ID).Cols("name").Update(attach)
+	return err
+}
+
 // CreateRelease creates a new release of repository.
 func CreateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) error {
 	isExist, err := IsReleaseExist(rel.RepoID, rel.TagName)
@@ -181,7 +226,7 @@ func CreateRelease(gitRepo *git.Repository, rel *Release, attachmentUUIDs []stri
 		return ErrReleaseAlreadyExist{rel.TagName}
 	}
 
-	if err = createTag(gitRepo, rel); err != nil {
+	if err = createTag(gitRepo, rel, rel.PublisherID); err != nil {
 		return err
 	}
 	rel.LowerTagName = strings.ToLower(rel.TagName)
@@ -375,7 +420,7 @@ func SortReleases(rels []*Release) {
 
 // UpdateRelease updates information of a release.
 func UpdateRelease(doer *User, gitRepo *git.Repository, rel *Release, attachmentUUIDs []string) (err error) {
-	if err = createTag(gitRepo, rel); err != nil {
+	if err = createTag(gitRepo, rel, doer.ID); err != nil {
 		return err
 	}
 	rel.LowerTagName = strings.ToLower(rel.TagName)
@@ -420,6 +465,13 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 	}
 
 	if delTag {
+		canPush, err := CanUserPushTag(rel.RepoID, rel.TagName, u.ID)
+		if err != nil {
+			return fmt.Errorf("CanUserPushTag: %v", err)
+		} else if !canPush {
+			return ErrProtectedTagName{rel.TagName}
+		}
+
 		_, stderr, err := process.GetManager().ExecDir(-1, repo.RepoPath(),
 			fmt.Sprintf("DeleteReleaseByID (git tag -d): %d", rel.ID),
 			"git", "tag", "-d", rel.TagName)
@@ -447,6 +499,10 @@ func DeleteReleaseByID(id int64, u *User, delTag bool) error {
 		return fmt.Errorf("LoadAttributes: %v", err)
 	}
 
+	if _, err = DeleteAttachments(rel.Attachments, true); err != nil {
+		return fmt.Errorf("DeleteAttachments: %v", err)
+	}
+
 	mode, _ := AccessLevel(u, rel.Repo)
 	if err := PrepareWebhooks(rel.Repo, HookEventRelease, &api.ReleasePayload{
 		Action:     api.HookReleaseDeleted,
@@ -5,9 +5,14 @@
 package models
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -149,3 +154,52 @@ func TestRelease_MirrorDelete(t *testing.T) {
 	count, err = GetReleaseCountByRepoID(mirror.ID, findOptions)
 	assert.EqualValues(t, initCount, count)
 }
+
+func TestReleaseAttachments(t *testing.T) {
+	PrepareTestEnv(t)
+	defer func(attachmentPath string) {
+		setting.AttachmentPath = attachmentPath
+	}(setting.AttachmentPath)
+	setting.AttachmentPath = filepath.Join(setting.AppDataPath, "attachments")
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+
+	rel := &Release{
+		RepoID:      repo.ID,
+		PublisherID: user.ID,
+		TagName:     "v0.3",
+		Target:      "master",
+		Title:       "v0.3 is released",
+	}
+	assert.NoError(t, CreateRelease(gitRepo, rel, nil))
+
+	attach, err := NewReleaseAttachment(rel, "asset.zip", []byte("PK"), strings.NewReader("content"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 9, attach.Size)
+	AssertExistsAndLoadBean(t, &Attachment{ID: attach.ID, ReleaseID: rel.ID, Name: "asset.zip"})
+
+	_, err = NewReleaseAttachment(rel, "asset.zip", nil, strings.NewReader("other"))
+	assert.True(t, IsErrReleaseAttachmentAlreadyExist(err))
+
+	other, err := NewReleaseAttachment(rel, "other.zip", nil, strings.NewReader("other"))
+	assert.NoError(t, err)
+	assert.True(t, IsErrReleaseAttachmentAlreadyExist(RenameReleaseAttachment(other, "asset.zip")))
+	assert.NoError(t, RenameReleaseAttachment(other, "renamed.zip"))
+	AssertExistsAndLoadBean(t, &Attachment{ID: other.ID, Name: "renamed.zip"})
+
+	assert.NoError(t, attach.ReplaceContent([]byte("new "), strings.NewReader("content")))
+	data, err := ioutil.ReadFile(attach.LocalPath())
+	assert.NoError(t, err)
+	assert.Equal(t, "new content", string(data))
+	AssertExistsAndLoadBean(t, &Attachment{ID: attach.ID, Size: 11})
+
+	assert.NoError(t, DeleteReleaseByID(rel.ID, user, true))
+	for _, a := range []*Attachment{attach, other} {
+		AssertNotExistsBean(t, &Attachment{ID: a.ID})
+		_, err = os.Stat(a.LocalPath())
+		assert.True(t, os.IsNotExist(err))
+	}
+}
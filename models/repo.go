@@ -192,6 +192,8 @@ type Repository struct {
 	IsMirror bool `xorm:"INDEX"`
 	*Mirror  `xorm:"-"`
 
+	IsArchived bool `xorm:"INDEX"`
+
 	ExternalMetas map[string]string `xorm:"-"`
 	Units         []*RepoUnit       `xorm:"-"`
 
@@ -203,6 +205,11 @@ type Repository struct {
 	IsFsckEnabled bool               `xorm:"NOT NULL DEFAULT true"`
 	Topics        []string           `xorm:"TEXT JSON"`
 
+	// Result of the last health check
+	HealthCheckStatus RepoHealthCheckStatus `xorm:"NOT NULL DEFAULT 0"`
+	HealthCheckErrors string                `xorm:"TEXT"`
+	HealthCheckUnix   util.TimeStamp
+
 	CreatedUnix util.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix util.TimeStamp `xorm:"INDEX updated"`
 }
@@ -296,6 +303,7 @@ func (repo *Repository) innerAPIFormat(e Engine, mode AccessMode, isParent bool)
 		Fork:          repo.IsFork,
 		Parent:        parent,
 		Mirror:        repo.IsMirror,
+		Archived:      repo.IsArchived,
 		HTMLURL:       repo.HTMLURL(),
 		SSHURL:        cloneLink.SSH,
 		CloneURL:      cloneLink.HTTPS,
@@ -381,6 +389,11 @@ func (repo *Repository) MustGetUnit(tp UnitType) *RepoUnit {
 			Type:   tp,
 			Config: new(PullRequestsConfig),
 		}
+	} else if tp == UnitTypeIssues {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(IssuesConfig),
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -1076,7 +1089,7 @@ func CleanUpMigrateInfo(repo *Repository) (*Repository, error) {
 }
 
 // initRepoCommit temporarily changes with work directory.
-func initRepoCommit(tmpPath string, sig *git.Signature) (err error) {
+func initRepoCommit(tmpPath, branch string, sig *git.Signature) (err error) {
 	var stderr string
 	if _, stderr, err = process.GetManager().ExecDir(-1,
 		tmpPath, fmt.Sprintf("initRepoCommit (git add): %s", tmpPath),
@@ -1093,12 +1106,21 @@ func initRepoCommit(tmpPath string, sig *git.Signature) (err error) {
 
 	if _, stderr, err = process.GetManager().ExecDir(-1,
 		tmpPath, fmt.Sprintf("initRepoCommit (git push): %s", tmpPath),
-		"git", "push", "origin", "master"); err != nil {
+		"git", "push", "origin", "HEAD:"+git.BranchPrefix+branch); err != nil {
 		return fmt.Errorf("git push: %s", stderr)
 	}
 	return nil
 }
 
+// defaultBranchName returns the name of the branch new repositories are
+// initialized with.
+func defaultBranchName() string {
+	if len(setting.Repository.DefaultBranch) == 0 {
+		return "master"
+	}
+	return setting.Repository.DefaultBranch
+}
+
 // CreateRepoOptions contains the create repository options
 type CreateRepoOptions struct {
 	Name        string
@@ -1213,6 +1235,11 @@ func initRepository(e Engine, repoPath string, u *User, repo *Repository, opts C
 		return fmt.Errorf("createDelegateHooks: %v", err)
 	}
 
+	branch := repo.DefaultBranch
+	if _, err = git.NewCommand("symbolic-ref", "HEAD", git.BranchPrefix+branch).RunInDir(repoPath); err != nil {
+		return fmt.Errorf("symbolic-ref: %v", err)
+	}
+
 	tmpDir := filepath.Join(os.TempDir(), "gitea-"+repo.Name+"-"+com.ToStr(time.Now().Nanosecond()))
 
 	// Initialize repository according to user's choice.
@@ -1229,7 +1256,7 @@ func initRepository(e Engine, repoPath string, u *User, repo *Repository, opts C
 		}
 
 		// Apply changes and commit.
-		if err = initRepoCommit(tmpDir, u.NewGitSig()); err != nil {
+		if err = initRepoCommit(tmpDir, branch, u.NewGitSig()); err != nil {
 			return fmt.Errorf("initRepoCommit: %v", err)
 		}
 	}
@@ -1244,7 +1271,7 @@ func initRepository(e Engine, repoPath string, u *User, repo *Repository, opts C
 		repo.IsBare = true
 	}
 
-	repo.DefaultBranch = "master"
+	repo.DefaultBranch = branch
 	if err = updateRepository(e, repo, false); err != nil {
 		return fmt.Errorf("updateRepository: %v", err)
 	}
@@ -1282,8 +1309,9 @@ func createRepository(e *xorm.Session, doer, u *User, repo *Repository) (err err
 	}
 
 	// insert units for repo
-	var units = make([]RepoUnit, 0, len(defaultRepoUnits))
-	for _, tp := range defaultRepoUnits {
+	unitTypes := u.NewRepoUnits()
+	var units = make([]RepoUnit, 0, len(unitTypes))
+	for _, tp := range unitTypes {
 		if tp == UnitTypeIssues {
 			units = append(units, RepoUnit{
 				RepoID: repo.ID,
@@ -1359,12 +1387,13 @@ func CreateRepository(doer, u *User, opts CreateRepoOptions) (_ *Repository, err
 	}
 
 	repo := &Repository{
-		OwnerID:     u.ID,
-		Owner:       u,
-		Name:        opts.Name,
-		LowerName:   strings.ToLower(opts.Name),
-		Description: opts.Description,
-		IsPrivate:   opts.IsPrivate,
+		OwnerID:       u.ID,
+		Owner:         u,
+		Name:          opts.Name,
+		LowerName:     strings.ToLower(opts.Name),
+		Description:   opts.Description,
+		IsPrivate:     opts.IsPrivate,
+		DefaultBranch: defaultBranchName(),
 	}
 
 	sess := x.NewSession()
@@ -1436,6 +1465,77 @@ func RepoPath(userName, repoName string) string {
 	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".git")
 }
 
+// RepoTransferProblem is a precondition of a repository transfer which is not met.
+type RepoTransferProblem string
+
+// enumerates all the problems which prevent a repository transfer
+const (
+	// RepoTransferProblemOwnerNotExist the new owner does not exist
+	RepoTransferProblemOwnerNotExist RepoTransferProblem = "owner_not_exist"
+	// RepoTransferProblemSameOwner the repository already belongs to the new owner
+	RepoTransferProblemSameOwner RepoTransferProblem = "same_owner"
+	// RepoTransferProblemNoPermission the doer may not create repositories for the new owner
+	RepoTransferProblemNoPermission RepoTransferProblem = "no_permission"
+	// RepoTransferProblemNameCollision the new owner already has a repository,
+	// or a repository or wiki directory, with the same name
+	RepoTransferProblemNameCollision RepoTransferProblem = "name_collision"
+	// RepoTransferProblemLFSObjectMissing some LFS objects of the repository
+	// are missing from the content store
+	RepoTransferProblemLFSObjectMissing RepoTransferProblem = "lfs_object_missing"
+)
+
+// CheckTransferOwnership checks all the preconditions of transferring repo to
+// the new owner without changing anything, and returns the problems which
+// would make the transfer fail. An error is only returned if the checks
+// themselves failed.
+func CheckTransferOwnership(doer *User, newOwnerName string, repo *Repository) ([]RepoTransferProblem, error) {
+	newOwner, err := GetUserByName(newOwnerName)
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return []RepoTransferProblem{RepoTransferProblemOwnerNotExist}, nil
+		}
+		return nil, fmt.Errorf("get new owner '%s': %v", newOwnerName, err)
+	}
+	if newOwner.ID == repo.OwnerID {
+		return []RepoTransferProblem{RepoTransferProblemSameOwner}, nil
+	}
+
+	var problems []RepoTransferProblem
+	if newOwner.IsOrganization() && !doer.IsAdmin {
+		isOwner, err := newOwner.IsOwnedBy(doer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("IsOwnedBy: %v", err)
+		} else if !isOwner {
+			problems = append(problems, RepoTransferProblemNoPermission)
+		}
+	}
+
+	// Unlike IsRepositoryExist, a name is taken by either a database record or
+	// a directory alone.
+	has, err := x.Get(&Repository{OwnerID: newOwner.ID, LowerName: repo.LowerName})
+	if err != nil {
+		return nil, fmt.Errorf("get repository: %v", err)
+	} else if has || com.IsExist(RepoPath(newOwner.Name, repo.Name)) ||
+		com.IsExist(WikiPath(newOwner.Name, repo.Name)) {
+		problems = append(problems, RepoTransferProblemNameCollision)
+	}
+
+	if setting.LFS.StartServer {
+		var metas []*LFSMetaObject
+		if err = x.Where("repository_id = ?", repo.ID).Find(&metas); err != nil {
+			return nil, fmt.Errorf("find LFS objects: %v", err)
+		}
+		for _, meta := range metas {
+			if !com.IsFile(filepath.Join(setting.LFS.ContentPath, meta.RelativePath())) {
+				problems = append(problems, RepoTransferProblemLFSObjectMissing)
+				break
+			}
+		}
+	}
+
+	return problems, nil
+}
+
 // TransferOwnership transfers all corresponding setting from old user to new one.
 func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error {
 	newOwner, err := GetUserByName(newOwnerName)
@@ -1771,7 +1871,9 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&RepoRedirect{RedirectRepoID: repoID},
 		&Webhook{RepoID: repoID},
 		&HookTask{RepoID: repoID},
+		&HookTaskAttempt{RepoID: repoID},
 		&Notification{RepoID: repoID},
+		&IssueMigration{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -2180,14 +2282,9 @@ func GitFsck() {
 		Iterate(new(Repository),
 			func(idx int, bean interface{}) error {
 				repo := bean.(*Repository)
-				repoPath := repo.RepoPath()
-				log.Trace("Running health check on repository %s", repoPath)
-				if err := git.Fsck(repoPath, setting.Cron.RepoHealthCheck.Timeout, setting.Cron.RepoHealthCheck.Args...); err != nil {
-					desc := fmt.Sprintf("Failed to health check repository (%s): %v", repoPath, err)
-					log.Warn(desc)
-					if err = CreateRepositoryNotice(desc); err != nil {
-						log.Error(4, "CreateRepositoryNotice: %v", err)
-					}
+				log.Trace("Running health check on repository %s", repo.RepoPath())
+				if err := repo.CheckHealth(false); err != nil {
+					log.Error(4, "CheckHealth [repo_id: %d]: %v", repo.ID, err)
 				}
 				return nil
 			}); err != nil {
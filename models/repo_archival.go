@@ -0,0 +1,54 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// The actions of the repository webhook event sent when a repository is
+// archived or unarchived, the new state being also that of the repository of
+// the payload.
+const (
+	HookRepoArchived   api.HookRepoAction = "archived"
+	HookRepoUnarchived api.HookRepoAction = "unarchived"
+)
+
+// SetArchiveRepoState archives or unarchives the repository and fires the
+// repository webhook event of the change. Nothing happens if the repository is
+// already in the given state.
+func (repo *Repository) SetArchiveRepoState(doer *User, isArchived bool) (err error) {
+	if repo.IsArchived == isArchived {
+		return nil
+	}
+
+	repo.IsArchived = isArchived
+	if _, err = x.ID(repo.ID).Cols("is_archived").Update(repo); err != nil {
+		return fmt.Errorf("update is_archived: %v", err)
+	}
+
+	if err = repo.GetOwner(); err != nil {
+		return fmt.Errorf("GetOwner: %v", err)
+	}
+	action := HookRepoUnarchived
+	if isArchived {
+		action = HookRepoArchived
+	}
+	payload := &api.RepositoryPayload{
+		Action:     action,
+		Repository: repo.APIFormat(AccessModeOwner),
+		Sender:     doer.APIFormat(),
+	}
+	if repo.Owner.IsOrganization() {
+		payload.Organization = repo.Owner.APIFormat()
+	}
+	if err = PrepareWebhooks(repo, HookEventRepository, payload); err != nil {
+		return fmt.Errorf("PrepareWebhooks: %v", err)
+	}
+	go HookQueue.Add(repo.ID)
+	return nil
+}
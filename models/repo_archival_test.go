@@ -0,0 +1,85 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_SetArchiveRepoState(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	w := &Webhook{
+		RepoID:      repo.ID,
+		URL:         "www.example.com/archived",
+		ContentType: ContentTypeJSON,
+		IsActive:    true,
+		HookEvent:   &HookEvent{SendEverything: true},
+	}
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, CreateWebhook(w))
+
+	assertPayload := func(action api.HookRepoAction, isArchived bool) {
+		var hookTasks []*HookTask
+		assert.NoError(t, x.Where("hook_id = ?", w.ID).Desc("id").Find(&hookTasks))
+		if !assert.NotEmpty(t, hookTasks) {
+			return
+		}
+		assert.Equal(t, HookEventRepository, hookTasks[0].EventType)
+		var payload api.RepositoryPayload
+		assert.NoError(t, json.Unmarshal([]byte(hookTasks[0].PayloadContent), &payload))
+		assert.Equal(t, action, payload.Action)
+		assert.Equal(t, isArchived, payload.Repository.Archived)
+		assert.Equal(t, "user2", payload.Sender.UserName)
+		assert.Nil(t, payload.Organization)
+	}
+
+	assert.NoError(t, repo.SetArchiveRepoState(doer, true))
+	assert.True(t, AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository).IsArchived)
+	assertPayload(HookRepoArchived, true)
+
+	// archiving again changes nothing
+	assert.NoError(t, repo.SetArchiveRepoState(doer, true))
+	AssertCount(t, &HookTask{HookID: w.ID}, 1)
+
+	assert.NoError(t, repo.SetArchiveRepoState(doer, false))
+	assert.False(t, AssertExistsAndLoadBean(t, &Repository{ID: repo.ID}).(*Repository).IsArchived)
+	assertPayload(HookRepoUnarchived, false)
+	AssertCount(t, &HookTask{HookID: w.ID}, 2)
+}
+
+func TestRepositoryArchivalPayloads(t *testing.T) {
+	p := &api.RepositoryPayload{
+		Action: HookRepoArchived,
+		Repository: &api.Repository{
+			FullName: "user2/repo1",
+			HTMLURL:  "http://localhost:3000/user2/repo1",
+		},
+		Sender: &api.User{UserName: "user2"},
+	}
+
+	slack, err := GetSlackPayload(p, HookEventRepository, `{"channel":"#gitea"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "[user2/repo1] Repository archived by <https://try.gitea.io/user2|user2>", slack.Text)
+
+	discord, err := GetDiscordPayload(p, HookEventRepository, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "[user2/repo1] Repository archived", discord.Embeds[0].Title)
+	assert.Equal(t, "http://localhost:3000/user2/repo1", discord.Embeds[0].URL)
+
+	p.Action = HookRepoUnarchived
+	dingtalk, err := GetDingtalkPayload(p, HookEventRepository, "")
+	assert.NoError(t, err)
+	if assert.NotNil(t, dingtalk) {
+		assert.Equal(t, "[user2/repo1] Repository unarchived", dingtalk.ActionCard.Title)
+	}
+}
@@ -6,6 +6,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"code.gitea.io/git"
@@ -42,6 +43,34 @@ func GetBranchesByPath(path string) ([]*Branch, error) {
 	return branches, nil
 }
 
+// GetMergedBranches returns the branches whose tip is reachable from the tip
+// of the given branch, except the branch itself.
+func (repo *Repository) GetMergedBranches(into string) ([]*Branch, error) {
+	repoPath := repo.RepoPath()
+	if !git.IsBranchExist(repoPath, into) {
+		return nil, ErrBranchNotExist{into}
+	}
+
+	stdout, err := git.NewCommand("for-each-ref", "--format=%(refname)",
+		"--merged="+git.BranchPrefix+into, git.BranchPrefix).RunInDir(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %v", err)
+	}
+
+	branches := make([]*Branch, 0, 10)
+	for _, line := range strings.Split(stdout, "\n") {
+		name := strings.TrimPrefix(line, git.BranchPrefix)
+		if len(name) == 0 || name == into {
+			continue
+		}
+		branches = append(branches, &Branch{
+			Path: repoPath,
+			Name: name,
+		})
+	}
+	return branches, nil
+}
+
 // CanCreateBranch returns true if repository meets the requirements for creating new branches.
 func (repo *Repository) CanCreateBranch() bool {
 	return !repo.IsMirror
@@ -0,0 +1,182 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"strconv"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/cache"
+)
+
+// commitsWalkCacheSize is the number of commits of the newest pages of a
+// history whose IDs are cached, older pages being listed from the tip.
+var commitsWalkCacheSize = 5000
+
+func commitsCountCacheKey(repoID int64, commitID string) string {
+	return fmt.Sprintf("repo_commits_count_%d_%s", repoID, commitID)
+}
+
+func commitsWalkCacheKey(repoID int64, commitID string) string {
+	return fmt.Sprintf("repo_commits_walk_%d_%s", repoID, commitID)
+}
+
+// getCommitsCount returns the number of commits reachable from the given
+// commit. The history of a commit never changes, so it is counted once and
+// cached for paging through it.
+func (repo *Repository) getCommitsCount(commitID string) (int64, error) {
+	return cache.GetInt64(commitsCountCacheKey(repo.ID, commitID), func() (int64, error) {
+		return git.CommitsCount(repo.RepoPath(), commitID)
+	})
+}
+
+// getCommitsWalk returns the IDs of the newest commits of the history of the
+// given commit, up to commitsWalkCacheSize, walked once and cached as a string
+// of concatenated IDs.
+func (repo *Repository) getCommitsWalk(commitID string) (string, error) {
+	return cache.GetString(commitsWalkCacheKey(repo.ID, commitID), func() (string, error) {
+		stdout, err := git.NewCommand("rev-list", "--max-count="+strconv.Itoa(commitsWalkCacheSize), commitID).RunInDirBytes(repo.RepoPath())
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.Replace(stdout, []byte{'\n'}, nil, -1)), nil
+	})
+}
+
+// GetCommitsPage returns the commits of given page of the history of the
+// commit, pages being of pageSize commits, and the number of commits of the
+// history. The pages of the cached walk of the newest commits are read from
+// it, the older ones are walked from the tip.
+func (repo *Repository) GetCommitsPage(gitRepo *git.Repository, commitID string, page, pageSize int) (*list.List, int, error) {
+	count, err := repo.getCommitsCount(commitID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("CommitsCount: %v", err)
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	skip := (page - 1) * pageSize
+	if int64(skip) >= count {
+		return list.New(), int(count), nil
+	}
+
+	var cmd *git.Command
+	if skip+pageSize <= commitsWalkCacheSize || count <= int64(commitsWalkCacheSize) {
+		walk, err := repo.getCommitsWalk(commitID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("rev-list: %v", err)
+		}
+		cmd = git.NewCommand("log", "--no-walk=unsorted", "--pretty=raw", "-z")
+		for i := skip; i < skip+pageSize && (i+1)*40 <= len(walk); i++ {
+			cmd.AddArguments(walk[i*40 : (i+1)*40])
+		}
+	} else {
+		cmd = git.NewCommand("log", "--pretty=raw", "-z", "--skip="+strconv.Itoa(skip),
+			"--max-count="+strconv.Itoa(pageSize), commitID)
+	}
+	stdout, err := cmd.RunInDirBytes(gitRepo.Path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("log: %v", err)
+	}
+	commits, err := parseRawLog(gitRepo, stdout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return commits, int(count), nil
+}
+
+// parseRawLogSignature parses the author or committer of a commit, which looks
+// like "User <user@example.com> 1502042309 +0200".
+func parseRawLogSignature(line []byte) (*git.Signature, error) {
+	emailStart := bytes.IndexByte(line, '<')
+	emailEnd := bytes.IndexByte(line, '>')
+	if emailStart < 1 || emailEnd < emailStart {
+		return nil, fmt.Errorf("invalid signature: %s", line)
+	}
+	sig := &git.Signature{
+		Name:  string(line[:emailStart-1]),
+		Email: string(line[emailStart+1 : emailEnd]),
+		When:  time.Unix(0, 0),
+	}
+	if fields := bytes.Fields(line[emailEnd+1:]); len(fields) > 0 {
+		seconds, err := strconv.ParseInt(string(fields[0]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature time: %s", line)
+		}
+		sig.When = time.Unix(seconds, 0)
+	}
+	return sig, nil
+}
+
+// parseRawLog parses the commits of the output of git log --pretty=raw -z.
+// The commits hold what the listings of commits show, their parents are not
+// loaded. Signed commits are loaded from the repository, the signed data
+// of a commit not being part of the log.
+func parseRawLog(gitRepo *git.Repository, stdout []byte) (*list.List, error) {
+	commits := list.New()
+	for _, entry := range bytes.Split(stdout, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		commit := new(git.Commit)
+		isSigned := false
+		lines := bytes.Split(entry, []byte{'\n'})
+		i := 0
+		for ; i < len(lines) && len(lines[i]) > 0; i++ {
+			spacePos := bytes.IndexByte(lines[i], ' ')
+			if spacePos <= 0 {
+				continue
+			}
+			value := lines[i][spacePos+1:]
+			var err error
+			switch string(lines[i][:spacePos]) {
+			case "commit":
+				// the ID may be followed by the decoration of the commit
+				if len(value) > 40 {
+					value = value[:40]
+				}
+				commit.ID, err = git.NewIDFromString(string(value))
+			case "tree":
+				var treeID git.SHA1
+				if treeID, err = git.NewIDFromString(string(value)); err == nil {
+					commit.Tree = *git.NewTree(gitRepo, treeID)
+				}
+			case "author":
+				commit.Author, err = parseRawLogSignature(value)
+			case "committer":
+				commit.Committer, err = parseRawLogSignature(value)
+			case "gpgsig":
+				isSigned = true
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parse log of %s: %v", commit.ID, err)
+			}
+		}
+
+		if isSigned {
+			signedCommit, err := gitRepo.GetCommit(commit.ID.String())
+			if err != nil {
+				return nil, fmt.Errorf("GetCommit [%s]: %v", commit.ID, err)
+			}
+			commits.PushBack(signedCommit)
+			continue
+		}
+
+		// the lines of the message are indented by four spaces
+		var message bytes.Buffer
+		for _, line := range lines[i+1:] {
+			message.Write(bytes.TrimPrefix(line, []byte("    ")))
+			message.WriteByte('\n')
+		}
+		commit.CommitMessage = string(bytes.TrimSuffix(message.Bytes(), []byte{'\n'}))
+		commits.PushBack(commit)
+	}
+	return commits, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"container/list"
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetCommitsPage(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+
+	// the history of master of user2/repo16, newest first
+	const masterCommitID = "69554a64c1e6030f051e5c3f94bfbd773cd6a324"
+	expected := []string{
+		masterCommitID,
+		"27566bd5738fc8b4e3fef3c5e72cce608537bd95",
+		"5099b81332712fe655e34e8dd63574f503f61811",
+	}
+
+	commitIDs := func(commits *list.List) []string {
+		ids := make([]string, 0, commits.Len())
+		for e := commits.Front(); e != nil; e = e.Next() {
+			ids = append(ids, e.Value.(*git.Commit).ID.String())
+		}
+		return ids
+	}
+
+	var paged []string
+	for page := 1; page <= 2; page++ {
+		commits, total, err := repo.GetCommitsPage(gitRepo, masterCommitID, page, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, len(expected), total)
+		paged = append(paged, commitIDs(commits)...)
+	}
+	assert.Equal(t, expected, paged)
+
+	commits, total, err := repo.GetCommitsPage(gitRepo, masterCommitID, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), total)
+	assert.Equal(t, 0, commits.Len())
+
+	commits, _, err = repo.GetCommitsPage(gitRepo, masterCommitID, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[:1], commitIDs(commits))
+
+	// the pages older than the cached walk are walked from the tip
+	defer func(size int) { commitsWalkCacheSize = size }(commitsWalkCacheSize)
+	commitsWalkCacheSize = 1
+	paged = nil
+	for page := 1; page <= 3; page++ {
+		commits, _, err := repo.GetCommitsPage(gitRepo, masterCommitID, page, 1)
+		assert.NoError(t, err)
+		paged = append(paged, commitIDs(commits)...)
+	}
+	assert.Equal(t, expected, paged)
+}
+
+func TestParseRawLog(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+
+	stdout, err := git.NewCommand("log", "--pretty=raw", "-z", "69554a64c1e6030f051e5c3f94bfbd773cd6a324").RunInDirBytes(repo.RepoPath())
+	assert.NoError(t, err)
+	commits, err := parseRawLog(gitRepo, stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, commits.Len())
+
+	// the commits are the ones loaded from the repository, 27566bd5 being signed
+	expected, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	for e := commits.Front(); e != nil; e = e.Next() {
+		commit := e.Value.(*git.Commit)
+		expectedCommit, err := expected.GetCommit(commit.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCommit.Tree.ID, commit.Tree.ID)
+		assert.Equal(t, expectedCommit.Author, commit.Author)
+		assert.Equal(t, expectedCommit.Committer, commit.Committer)
+		assert.Equal(t, expectedCommit.CommitMessage, commit.CommitMessage)
+		assert.Equal(t, expectedCommit.Signature, commit.Signature)
+	}
+	assert.NotNil(t, commits.Front().Next().Value.(*git.Commit).Signature)
+
+	commits, err = parseRawLog(gitRepo, []byte("commit 69554a64c1e6030f051e5c3f94bfbd773cd6a324 (HEAD -> master)\n"+
+		"tree 24f83a471f77579fea57bac7255d6e64e70fce1c\n"+
+		"parent 27566bd5738fc8b4e3fef3c5e72cce608537bd95\n"+
+		"author User2 <user2@example.com> 1502042309 +0200\n"+
+		"committer User2 <user2@example.com> 1502042309 +0200\n"+
+		"\n"+
+		"    summary\n"+
+		"    \n"+
+		"    body\n"))
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, commits.Len()) {
+		commit := commits.Front().Value.(*git.Commit)
+		assert.Equal(t, "69554a64c1e6030f051e5c3f94bfbd773cd6a324", commit.ID.String())
+		assert.Equal(t, "summary\n\nbody\n", commit.CommitMessage)
+		assert.Equal(t, "user2@example.com", commit.Author.Email)
+		assert.EqualValues(t, 1502042309, commit.Committer.When.Unix())
+	}
+}
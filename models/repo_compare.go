@@ -0,0 +1,157 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	api "code.gitea.io/sdk/gitea"
+)
+
+// Comparison represents the changes between two commits of a repository.
+type Comparison struct {
+	BaseCommitID      string               `json:"base_commit"`
+	HeadCommitID      string               `json:"head_commit"`
+	MergeBaseCommitID string               `json:"merge_base_commit"`
+	Commits           []*api.PayloadCommit `json:"commits"`
+	Files             []*ComparisonFile    `json:"files"`
+	TotalAdditions    int                  `json:"total_additions"`
+	TotalDeletions    int                  `json:"total_deletions"`
+	// Truncated is set when the diff exceeds the configured git diff limits
+	// and some files or lines are missing.
+	Truncated bool `json:"truncated"`
+	// Patch is only filled in when requested.
+	Patch string `json:"patch,omitempty"`
+}
+
+// ComparisonFile represents a file changed between two commits.
+type ComparisonFile struct {
+	Filename  string `json:"filename"`
+	OldName   string `json:"previous_filename,omitempty"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	IsBinary  bool   `json:"binary"`
+	Truncated bool   `json:"truncated"`
+}
+
+func diffFileStatus(file *DiffFile) string {
+	switch file.Type {
+	case DiffFileAdd:
+		return "added"
+	case DiffFileDel:
+		return "deleted"
+	case DiffFileRename:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// isGitSilentFailure returns true if git exited with an error status without
+// a message, which is how some commands report they have no result.
+func isGitSilentFailure(err error, stderr *bytes.Buffer) bool {
+	_, ok := err.(*exec.ExitError)
+	return ok && stderr.Len() == 0
+}
+
+// ResolveCommitRef returns the ID of the commit a branch, tag, commit SHA or
+// any other revision git understands points to, or an empty string if there
+// is no such commit.
+func ResolveCommitRef(gitRepo *git.Repository, ref string) (string, error) {
+	// a revision is never an option of git rev-parse
+	if strings.HasPrefix(ref, "-") {
+		return "", nil
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	if err := git.NewCommand("rev-parse", "--verify", "--quiet", ref+"^{commit}").
+		RunInDirTimeoutPipeline(-1, gitRepo.Path, stdout, stderr); err != nil {
+		if isGitSilentFailure(err, stderr) {
+			return "", nil
+		}
+		return "", fmt.Errorf("rev-parse: %v - %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// getMergeBase returns the merge base of the two commits, or ErrNoMergeBase if
+// their histories are unrelated.
+func getMergeBase(gitRepo *git.Repository, baseCommitID, headCommitID string) (string, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	if err := git.NewCommand("merge-base", baseCommitID, headCommitID).
+		RunInDirTimeoutPipeline(-1, gitRepo.Path, stdout, stderr); err != nil {
+		if isGitSilentFailure(err, stderr) {
+			return "", ErrNoMergeBase{baseCommitID, headCommitID}
+		}
+		return "", fmt.Errorf("merge-base: %v - %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// CompareCommits compares the head commit to the base commit like
+// "git diff base...head" does: the commits and changes since the merge base
+// of both commits are returned.
+func (repo *Repository) CompareCommits(gitRepo *git.Repository, baseCommitID, headCommitID string, withPatch bool) (*Comparison, error) {
+	mergeBase, err := getMergeBase(gitRepo, baseCommitID, headCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{
+		BaseCommitID:      baseCommitID,
+		HeadCommitID:      headCommitID,
+		MergeBaseCommitID: mergeBase,
+		Commits:           []*api.PayloadCommit{},
+		Files:             []*ComparisonFile{},
+	}
+	if mergeBase == headCommitID {
+		return comparison, nil
+	}
+
+	commits, err := gitRepo.CommitsBetweenIDs(headCommitID, mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetweenIDs: %v", err)
+	}
+	comparison.Commits = ListToPushCommits(commits).ToAPIPayloadCommits(repo.HTMLURL())
+
+	diff, err := GetDiffRange(repo.RepoPath(), mergeBase, headCommitID,
+		setting.Git.MaxGitDiffLines, setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles)
+	if err != nil {
+		return nil, fmt.Errorf("GetDiffRange: %v", err)
+	}
+	comparison.TotalAdditions = diff.TotalAddition
+	comparison.TotalDeletions = diff.TotalDeletion
+	comparison.Truncated = diff.IsIncomplete
+	for _, file := range diff.Files {
+		comparisonFile := &ComparisonFile{
+			Filename:  file.Name,
+			Status:    diffFileStatus(file),
+			Additions: file.Addition,
+			Deletions: file.Deletion,
+			IsBinary:  file.IsBin,
+			Truncated: file.IsIncomplete,
+		}
+		if file.IsRenamed {
+			comparisonFile.OldName = file.OldName
+		}
+		comparison.Files = append(comparison.Files, comparisonFile)
+	}
+
+	if withPatch {
+		var patch bytes.Buffer
+		if err = GetRawDiffForFile(repo.RepoPath(), mergeBase, headCommitID, RawDiffNormal, "", &patch); err != nil {
+			return nil, fmt.Errorf("GetRawDiffForFile: %v", err)
+		}
+		comparison.Patch = patch.String()
+	}
+
+	return comparison, nil
+}
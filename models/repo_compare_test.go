@@ -0,0 +1,87 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_CompareCommits(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+
+	// see the branches of user2/repo16 in integrations/gitea-repositories-meta
+	const (
+		firstCommitID  = "5099b81332712fe655e34e8dd63574f503f61811"
+		masterCommitID = "69554a64c1e6030f051e5c3f94bfbd773cd6a324"
+		orphanCommitID = "f27c2b2b03dcab38beaf89b0ab4ff61f6de63441"
+	)
+
+	comparison, err := repo.CompareCommits(gitRepo, firstCommitID, masterCommitID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, firstCommitID, comparison.MergeBaseCommitID)
+	if assert.Len(t, comparison.Commits, 2) {
+		assert.Equal(t, masterCommitID, comparison.Commits[0].ID)
+	}
+	if assert.Len(t, comparison.Files, 1) {
+		assert.Equal(t, &ComparisonFile{
+			Filename:  "readme.md",
+			Status:    "modified",
+			Additions: 1,
+			Deletions: 1,
+		}, comparison.Files[0])
+	}
+	assert.EqualValues(t, 1, comparison.TotalAdditions)
+	assert.EqualValues(t, 1, comparison.TotalDeletions)
+	assert.True(t, strings.HasPrefix(comparison.Patch, "diff --git a/readme.md b/readme.md"))
+
+	// head is an ancestor of base
+	comparison, err = repo.CompareCommits(gitRepo, masterCommitID, firstCommitID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, firstCommitID, comparison.MergeBaseCommitID)
+	assert.Empty(t, comparison.Commits)
+	assert.Empty(t, comparison.Files)
+	assert.Empty(t, comparison.Patch)
+
+	_, err = repo.CompareCommits(gitRepo, masterCommitID, orphanCommitID, false)
+	assert.True(t, IsErrNoMergeBase(err))
+
+	// other errors of git are not taken for unrelated histories
+	_, err = repo.CompareCommits(gitRepo, masterCommitID, "0000000000000000000000000000000000000000", false)
+	assert.Error(t, err)
+	assert.False(t, IsErrNoMergeBase(err))
+}
+
+func TestResolveCommitRef(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+
+	for _, test := range []struct {
+		ref, commitID string
+	}{
+		{"master", "69554a64c1e6030f051e5c3f94bfbd773cd6a324"},
+		{"69554a64c1e6030f051e5c3f94bfbd773cd6a324", "69554a64c1e6030f051e5c3f94bfbd773cd6a324"},
+		{"69554a6", "69554a64c1e6030f051e5c3f94bfbd773cd6a324"},
+		{"master~2", "5099b81332712fe655e34e8dd63574f503f61811"},
+		{"unknown", ""},
+		{"0000000", ""},
+		{"--all", ""},
+	} {
+		commitID, err := ResolveCommitRef(gitRepo, test.ref)
+		assert.NoError(t, err)
+		assert.Equal(t, test.commitID, commitID, test.ref)
+	}
+}
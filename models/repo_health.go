@@ -0,0 +1,130 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/Unknwon/com"
+)
+
+// RepoHealthCheckStatus represents the result of the health check of a repository
+type RepoHealthCheckStatus int
+
+// Enumerate all the repository health check statuses
+const (
+	RepoHealthCheckNone RepoHealthCheckStatus = iota // the repository has never been checked
+	RepoHealthCheckHealthy
+	RepoHealthCheckUnhealthy
+)
+
+// String returns the name of the status used by the API
+func (status RepoHealthCheckStatus) String() string {
+	switch status {
+	case RepoHealthCheckHealthy:
+		return "healthy"
+	case RepoHealthCheckUnhealthy:
+		return "unhealthy"
+	}
+	return "none"
+}
+
+// repoHealthCheckQueue holds the IDs of the repositories waiting for a health check.
+var repoHealthCheckQueue = sync.NewUniqueQueue(setting.Repository.HealthCheckQueueLength)
+
+// repoHealthCheckGCTable holds the IDs of the queued repositories a git gc is requested for.
+var repoHealthCheckGCTable = sync.NewStatusTable()
+
+// AddRepoHealthCheck queues a health check of the repository, followed by a
+// git gc if gc is set. It returns false if a check of the repository is already
+// waiting in the queue, the requests are then merged into this check.
+func AddRepoHealthCheck(repoID int64, gc bool) bool {
+	if gc {
+		repoHealthCheckGCTable.Start(com.ToStr(repoID))
+	}
+	var queued bool
+	repoHealthCheckQueue.AddFunc(repoID, func() {
+		queued = true
+	})
+	return queued
+}
+
+// IsRepoHealthCheckQueued returns true if a health check of the repository
+// is waiting in the queue.
+func IsRepoHealthCheckQueued(repoID int64) bool {
+	return repoHealthCheckQueue.Exist(repoID)
+}
+
+// CheckHealth runs git fsck on the repository and stores the result. When gc is
+// set, git gc is run afterwards if no error has been found.
+func (repo *Repository) CheckHealth(gc bool) error {
+	repoPath := repo.RepoPath()
+	var errs []string
+	if err := git.Fsck(repoPath, setting.Cron.RepoHealthCheck.Timeout, setting.Cron.RepoHealthCheck.Args...); err != nil {
+		errs = append(errs, err.Error())
+	} else if gc {
+		args := append([]string{"gc"}, setting.Git.GCArgs...)
+		_, stderr, err := process.GetManager().ExecDir(
+			time.Duration(setting.Git.Timeout.GC)*time.Second,
+			repoPath, fmt.Sprintf("CheckHealth(git gc): %s", repoPath),
+			"git", args...)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %s", err, stderr))
+		}
+	}
+
+	repo.HealthCheckStatus = RepoHealthCheckHealthy
+	repo.HealthCheckErrors = strings.Join(errs, "\n")
+	repo.HealthCheckUnix = util.TimeStampNow()
+	if len(errs) > 0 {
+		repo.HealthCheckStatus = RepoHealthCheckUnhealthy
+		desc := fmt.Sprintf("Failed to health check repository (%s): %s", repoPath, repo.HealthCheckErrors)
+		log.Warn(desc)
+		if err := CreateRepositoryNotice(desc); err != nil {
+			log.Error(4, "CreateRepositoryNotice: %v", err)
+		}
+	}
+
+	_, err := x.ID(repo.ID).Cols("health_check_status", "health_check_errors", "health_check_unix").Update(repo)
+	return err
+}
+
+// checkQueuedRepoHealth runs the health check of a repository taken from the queue.
+func checkQueuedRepoHealth(repoID string) {
+	repoHealthCheckQueue.Remove(repoID)
+	gc := repoHealthCheckGCTable.IsRunning(repoID)
+	repoHealthCheckGCTable.Stop(repoID)
+
+	repo, err := GetRepositoryByID(com.StrTo(repoID).MustInt64())
+	if err != nil {
+		log.Error(4, "GetRepositoryByID [%s]: %v", repoID, err)
+		return
+	}
+	log.Trace("Running health check on repository %s", repo.RepoPath())
+	if err = repo.CheckHealth(gc); err != nil {
+		log.Error(4, "CheckHealth [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+// CheckQueuedRepoHealth runs the health checks waiting in the queue.
+func CheckQueuedRepoHealth() {
+	for repoID := range repoHealthCheckQueue.Queue() {
+		checkQueuedRepoHealth(repoID)
+	}
+}
+
+// InitRepoHealthCheck starts the worker running the queued health checks.
+func InitRepoHealthCheck() {
+	go CheckQueuedRepoHealth()
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_CheckHealth(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, RepoHealthCheckNone, repo.HealthCheckStatus)
+
+	assert.NoError(t, repo.CheckHealth(true))
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, RepoHealthCheckHealthy, repo.HealthCheckStatus)
+	assert.Empty(t, repo.HealthCheckErrors)
+	assert.NotZero(t, repo.HealthCheckUnix)
+
+	// a branch pointing to a missing commit
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo.RepoPath(), "refs", "heads", "broken"),
+		[]byte("0123456789012345678901234567890123456789\n"), 0644))
+	assert.NoError(t, repo.CheckHealth(false))
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, RepoHealthCheckUnhealthy, repo.HealthCheckStatus)
+	assert.Contains(t, repo.HealthCheckErrors, "refs/heads/broken")
+	AssertExistsAndLoadBean(t, &Notice{Type: NoticeRepository}, Cond("description LIKE ?", "%refs/heads/broken%"))
+}
+
+func TestAddRepoHealthCheck(t *testing.T) {
+	PrepareTestEnv(t)
+
+	assert.True(t, AddRepoHealthCheck(1, false))
+	assert.True(t, AddRepoHealthCheck(16, false))
+	// the requests for a queued repository are merged
+	assert.False(t, AddRepoHealthCheck(1, false))
+	assert.False(t, AddRepoHealthCheck(1, true))
+	assert.Equal(t, 2, repoHealthCheckQueue.Len())
+	assert.True(t, IsRepoHealthCheckQueued(1))
+	assert.True(t, repoHealthCheckGCTable.IsRunning("1"))
+	assert.False(t, repoHealthCheckGCTable.IsRunning("16"))
+
+	checkQueuedRepoHealth(<-repoHealthCheckQueue.Queue())
+	assert.False(t, IsRepoHealthCheckQueued(1))
+	assert.False(t, repoHealthCheckGCTable.IsRunning("1"))
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, RepoHealthCheckHealthy, repo.HealthCheckStatus)
+
+	// a repository can be queued again once its check has started
+	assert.True(t, AddRepoHealthCheck(1, false))
+
+	checkQueuedRepoHealth(<-repoHealthCheckQueue.Queue())
+	checkQueuedRepoHealth(<-repoHealthCheckQueue.Queue())
+	assert.Equal(t, 0, repoHealthCheckQueue.Len())
+	repo = AssertExistsAndLoadBean(t, &Repository{ID: 16}).(*Repository)
+	assert.Equal(t, RepoHealthCheckHealthy, repo.HealthCheckStatus)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/linguist"
+)
+
+func languageStatsCacheKey(repoID int64, commitID string) string {
+	return fmt.Sprintf("repo_language_stats_%d_%s", repoID, commitID)
+}
+
+// GetLanguageStats returns the byte counts of the languages of the files of
+// the repository at the given commit, honoring the linguist attributes of its
+// root .gitattributes file.
+func (repo *Repository) GetLanguageStats(commitID string) ([]*linguist.LanguageStat, error) {
+	data, err := cache.GetString(languageStatsCacheKey(repo.ID, commitID), func() (string, error) {
+		stats, err := repo.getLanguageStats(commitID)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(stats)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*linguist.LanguageStat
+	if err = json.Unmarshal([]byte(data), &stats); err != nil {
+		return nil, fmt.Errorf("decode language stats [repo_id: %d, commit_id: %s]: %v", repo.ID, commitID, err)
+	}
+	return stats, nil
+}
+
+func (repo *Repository) getLanguageStats(commitID string) ([]*linguist.LanguageStat, error) {
+	repoPath := repo.RepoPath()
+	stdout, err := git.NewCommand("ls-tree", "-r", "-l", "-z", commitID).RunInDirBytes(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("ls-tree: %v", err)
+	}
+
+	var files []linguist.File
+	var attrs *linguist.Attributes
+	for _, entry := range bytes.Split(stdout, []byte{0}) {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		tab := bytes.IndexByte(entry, '\t')
+		if tab < 0 {
+			continue
+		}
+		fields := bytes.Fields(entry[:tab])
+		if len(fields) != 4 || string(fields[1]) != "blob" || string(fields[0]) == "120000" {
+			continue
+		}
+		path := string(entry[tab+1:])
+		if path == ".gitattributes" {
+			content, err := git.NewCommand("cat-file", "blob", string(fields[2])).RunInDirBytes(repoPath)
+			if err != nil {
+				return nil, fmt.Errorf("cat-file .gitattributes: %v", err)
+			}
+			attrs = linguist.ParseAttributes(content)
+			continue
+		}
+		size, err := strconv.ParseInt(string(fields[3]), 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, linguist.File{Path: path, Size: size})
+	}
+	return linguist.Stats(files, attrs), nil
+}
@@ -63,13 +63,34 @@ func (m *Mirror) AfterLoad(session *xorm.Session) {
 
 // ScheduleNextUpdate calculates and sets next update time.
 func (m *Mirror) ScheduleNextUpdate() {
+	m.scheduleNextUpdate(util.TimeStampNow())
+}
+
+func (m *Mirror) scheduleNextUpdate(from util.TimeStamp) {
 	if m.Interval != 0 {
-		m.NextUpdateUnix = util.TimeStampNow().AddDuration(m.Interval)
+		m.NextUpdateUnix = from.AddDuration(m.Interval)
 	} else {
 		m.NextUpdateUnix = 0
 	}
 }
 
+// IsDue returns true if the scheduled update of the mirror is due at the
+// given time. Mirrors with automatic sync disabled are never due.
+func (m *Mirror) IsDue(now util.TimeStamp) bool {
+	return m.NextUpdateUnix != 0 && m.NextUpdateUnix <= now
+}
+
+// SetInterval changes the sync interval of the mirror and schedules the next
+// update accordingly, an interval of 0 disables automatic sync.
+func (m *Mirror) SetInterval(interval time.Duration) error {
+	if interval < 0 || (interval != 0 && interval < setting.Mirror.MinInterval) {
+		return ErrMirrorIntervalTooShort{interval, setting.Mirror.MinInterval}
+	}
+	m.Interval = interval
+	m.ScheduleNextUpdate()
+	return nil
+}
+
 func remoteAddress(repoPath string) (string, error) {
 	cfg, err := ini.Load(GitConfigPath(repoPath))
 	if err != nil {
@@ -337,12 +358,16 @@ func SyncMirrors() {
 			continue
 		}
 
+		// A manual sync of a mirror which is not due keeps its schedule.
+		isDue := m.IsDue(util.TimeStampNow())
 		results, ok := m.runSync()
 		if !ok {
 			continue
 		}
 
-		m.ScheduleNextUpdate()
+		if isDue {
+			m.ScheduleNextUpdate()
+		}
 		if err = updateMirror(sess, m); err != nil {
 			log.Error(4, "UpdateMirror [%s]: %v", repoID, err)
 			continue
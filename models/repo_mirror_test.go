@@ -0,0 +1,58 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirror_ScheduleNextUpdate(t *testing.T) {
+	m := &Mirror{Interval: time.Hour}
+	m.scheduleNextUpdate(1000)
+	assert.EqualValues(t, 1000+3600, m.NextUpdateUnix)
+
+	m.Interval = 0
+	m.scheduleNextUpdate(1000)
+	assert.EqualValues(t, 0, m.NextUpdateUnix)
+}
+
+func TestMirror_IsDue(t *testing.T) {
+	m := &Mirror{NextUpdateUnix: 1000}
+	assert.False(t, m.IsDue(999))
+	assert.True(t, m.IsDue(1000))
+	assert.True(t, m.IsDue(2000))
+
+	// automatic sync is disabled
+	m.NextUpdateUnix = 0
+	assert.False(t, m.IsDue(2000))
+}
+
+func TestMirror_SetInterval(t *testing.T) {
+	defer func(minInterval time.Duration) {
+		setting.Mirror.MinInterval = minInterval
+	}(setting.Mirror.MinInterval)
+	setting.Mirror.MinInterval = 10 * time.Minute
+
+	m := &Mirror{Interval: 8 * time.Hour}
+	err := m.SetInterval(5 * time.Minute)
+	assert.True(t, IsErrMirrorIntervalTooShort(err))
+	assert.Equal(t, 8*time.Hour, m.Interval)
+	assert.True(t, IsErrMirrorIntervalTooShort(m.SetInterval(-time.Hour)))
+
+	now := util.TimeStampNow()
+	assert.NoError(t, m.SetInterval(10*time.Minute))
+	assert.Equal(t, 10*time.Minute, m.Interval)
+	assert.True(t, m.NextUpdateUnix >= now.Add(600))
+	assert.False(t, m.IsDue(now))
+
+	assert.NoError(t, m.SetInterval(0))
+	assert.EqualValues(t, 0, m.NextUpdateUnix)
+}
@@ -121,15 +121,30 @@ func getUserRepoPermission(e Engine, repo *Repository, user *User) (perm Permiss
 		return
 	}
 
+	if err = repo.getOwner(e); err != nil {
+		return
+	}
+
+	// Users without two-factor authentication get no more than anonymous
+	// access to the repositories of organizations requiring it.
+	if required, err := isTwoFactorRequired(e, repo.Owner, user); err != nil {
+		return perm, err
+	} else if required {
+		if repo.IsPrivate {
+			perm.AccessMode = AccessModeNone
+			perm.Units = nil
+		} else {
+			perm.AccessMode = AccessModeRead
+		}
+		return perm, nil
+	}
+
 	// plain user
 	perm.AccessMode, err = accessLevel(e, user.ID, repo)
 	if err != nil {
 		return
 	}
 
-	if err = repo.getOwner(e); err != nil {
-		return
-	}
 	if !repo.Owner.IsOrganization() {
 		return
 	}
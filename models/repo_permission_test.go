@@ -244,3 +244,58 @@ func TestRepoPermissionPrivateOrgRepo(t *testing.T) {
 		assert.True(t, perm.CanWrite(unit.Type))
 	}
 }
+
+func TestRepoPermissionTwoFactorRequired(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	org.RequireTwoFactor = true
+	assert.NoError(t, UpdateUserCols(org, "require_two_factor"))
+
+	// member with two-factor authentication
+	member := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	_, err := x.Insert(&TwoFactor{UID: member.ID})
+	assert.NoError(t, err)
+
+	privateRepo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.NoError(t, privateRepo.getUnits(x))
+	perm, err := GetUserRepoPermission(privateRepo, member)
+	assert.NoError(t, err)
+	for _, unit := range privateRepo.Units {
+		assert.True(t, perm.CanRead(unit.Type))
+		assert.True(t, perm.CanWrite(unit.Type))
+	}
+
+	// member without two-factor authentication
+	tester := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	perm, err = GetUserRepoPermission(privateRepo, tester)
+	assert.NoError(t, err)
+	for _, unit := range privateRepo.Units {
+		assert.False(t, perm.CanRead(unit.Type))
+		assert.False(t, perm.CanWrite(unit.Type))
+	}
+
+	publicRepo := AssertExistsAndLoadBean(t, &Repository{ID: 32}).(*Repository)
+	assert.NoError(t, publicRepo.getUnits(x))
+	perm, err = GetUserRepoPermission(publicRepo, tester)
+	assert.NoError(t, err)
+	for _, unit := range publicRepo.Units {
+		assert.True(t, perm.CanRead(unit.Type))
+		assert.False(t, perm.CanWrite(unit.Type))
+	}
+
+	// site admins are not affected
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	perm, err = GetUserRepoPermission(privateRepo, admin)
+	assert.NoError(t, err)
+	for _, unit := range privateRepo.Units {
+		assert.True(t, perm.CanRead(unit.Type))
+		assert.True(t, perm.CanWrite(unit.Type))
+	}
+
+	// new members must have two-factor authentication as well
+	team := AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team)
+	err = team.AddMember(5)
+	assert.True(t, IsErrTwoFactorRequired(err))
+	AssertNotExistsBean(t, &OrgUser{OrgID: org.ID, UID: 5})
+}
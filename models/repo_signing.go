@@ -0,0 +1,76 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// signingKey returns the ID of the GPG key used to sign the commits made by
+// the server in the repository at repoPath, or an empty string if no key is
+// configured.
+func signingKey(repoPath string) string {
+	switch setting.Repository.Signing.SigningKey {
+	case "", "none":
+		return ""
+	case "default":
+		// git config exits with status 1 if the key is not set.
+		value, _ := git.NewCommand("config", "--get", "user.signingkey").RunInDir(repoPath)
+		return strings.TrimSpace(value)
+	}
+	return setting.Repository.Signing.SigningKey
+}
+
+// webEditorSigningKey returns the key to sign commits made through the web
+// editor with, or an empty string if they should not be signed.
+func webEditorSigningKey(repoPath string) string {
+	if setting.Repository.Signing.WebEditor != "always" {
+		return ""
+	}
+	key := signingKey(repoPath)
+	if key == "" {
+		log.Trace("No signing key configured, web editor commit in %s will not be signed", repoPath)
+	}
+	return key
+}
+
+// commitChanges commits the staged changes of the repository at repoPath,
+// like git.CommitChanges, signing the commit with key unless it is empty.
+func commitChanges(repoPath string, opts git.CommitChangesOptions, key string) error {
+	cmd := git.NewCommand()
+	if opts.Committer != nil {
+		cmd.AddArguments("-c", "user.name="+opts.Committer.Name, "-c", "user.email="+opts.Committer.Email)
+	}
+	cmd.AddArguments("commit")
+
+	if opts.Author == nil {
+		opts.Author = opts.Committer
+	}
+	if opts.Author != nil {
+		cmd.AddArguments(fmt.Sprintf("--author='%s <%s>'", opts.Author.Name, opts.Author.Email))
+	}
+	if key != "" {
+		cmd.AddArguments("-S" + key)
+	}
+	cmd.AddArguments("-m", opts.Message)
+
+	_, err := cmd.RunInDir(repoPath)
+	// No stderr but exit status 1 means nothing to commit.
+	if err != nil && err.Error() == "exit status 1" {
+		return nil
+	}
+	return err
+}
+
+// commitEditorChanges commits the changes made through the web editor in the
+// local copy at localPath, signing the commit if configured to.
+func commitEditorChanges(localPath string, opts git.CommitChangesOptions) error {
+	return commitChanges(localPath, opts, webEditorSigningKey(localPath))
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningKey(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "signing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	assert.NoError(t, git.InitRepository(repoPath, false))
+
+	oldSigning := setting.Repository.Signing
+	defer func() {
+		setting.Repository.Signing = oldSigning
+	}()
+
+	setting.Repository.Signing.SigningKey = "none"
+	assert.Empty(t, signingKey(repoPath))
+
+	setting.Repository.Signing.SigningKey = "0123456789ABCDEF"
+	assert.Equal(t, "0123456789ABCDEF", signingKey(repoPath))
+
+	setting.Repository.Signing.SigningKey = "default"
+	_, err = git.NewCommand("config", "user.signingkey", "FEDCBA9876543210").RunInDir(repoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "FEDCBA9876543210", signingKey(repoPath))
+
+	setting.Repository.Signing.WebEditor = "never"
+	assert.Empty(t, webEditorSigningKey(repoPath))
+	setting.Repository.Signing.WebEditor = "always"
+	assert.Equal(t, "FEDCBA9876543210", webEditorSigningKey(repoPath))
+}
+
+func TestCommitChanges_Unsigned(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repoPath, err := ioutil.TempDir("", "signing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	assert.NoError(t, git.InitRepository(repoPath, false))
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# signing"), 0644))
+	assert.NoError(t, git.AddChanges(repoPath, true))
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, commitChanges(repoPath, git.CommitChangesOptions{
+		Committer: doer.NewGitSig(),
+		Message:   "Add README",
+	}, ""))
+
+	gitRepo, err := git.OpenRepository(repoPath)
+	assert.NoError(t, err)
+	commit, err := gitRepo.GetBranchCommit("master")
+	assert.NoError(t, err)
+	assert.Equal(t, "Add README\n", commit.CommitMessage)
+	assert.Nil(t, commit.Signature)
+}
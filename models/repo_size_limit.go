@@ -0,0 +1,101 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/git"
+)
+
+// GetLFSSize returns the total size of the LFS objects of the repository.
+func (repo *Repository) GetLFSSize() (int64, error) {
+	size, err := x.Where("repository_id = ?", repo.ID).SumInt(new(LFSMetaObject), "size")
+	if err != nil {
+		return 0, fmt.Errorf("SumInt: %v", err)
+	}
+	return size, nil
+}
+
+// GetTotalSize returns the size of the git objects of the repository, as
+// currently on disk, plus the size of its LFS objects.
+func (repo *Repository) GetTotalSize() (int64, error) {
+	repoInfoSize, err := git.GetRepoSize(repo.RepoPath())
+	if err != nil {
+		return 0, fmt.Errorf("GetRepoSize: %v", err)
+	}
+	lfsSize, err := repo.GetLFSSize()
+	if err != nil {
+		return 0, err
+	}
+	return repoInfoSize.Size + repoInfoSize.SizePack + lfsSize, nil
+}
+
+// sizeLimit returns the maximum size in bytes of the repository, which is the
+// limit of its owner applying to each of its repositories separately. It is
+// -1 if the size is not limited.
+func (repo *Repository) sizeLimit() (int64, error) {
+	if err := repo.GetOwner(); err != nil {
+		return 0, fmt.Errorf("GetOwner: %v", err)
+	}
+	return repo.Owner.MaxRepoSizeLimit(), nil
+}
+
+// CheckSizeLimit returns an ErrRepoSizeLimitExceeded if adding pushSize bytes
+// to the repository exceeds the limit of its owner. Pushes which add nothing,
+// like branch deletions, are always allowed so that the size can be reduced.
+func (repo *Repository) CheckSizeLimit(pushSize int64) error {
+	if pushSize <= 0 {
+		return nil
+	}
+	limit, err := repo.sizeLimit()
+	if err != nil || limit < 0 {
+		return err
+	}
+
+	size, err := repo.GetTotalSize()
+	if err != nil {
+		return err
+	}
+	if size+pushSize > limit {
+		return ErrRepoSizeLimitExceeded{
+			RepoID:   repo.ID,
+			Size:     size,
+			PushSize: pushSize,
+			Limit:    limit,
+		}
+	}
+	return nil
+}
+
+// CheckLFSSizeLimit returns an ErrRepoSizeLimitExceeded if uploading the LFS
+// object of the given size to the repository exceeds the limit of its owner.
+// The object may already have a meta object in the repository, it is then
+// already counted in the size of the repository.
+func (repo *Repository) CheckLFSSizeLimit(oid string, size int64) error {
+	limit, err := repo.sizeLimit()
+	if err != nil || limit < 0 {
+		return err
+	}
+
+	total, err := repo.GetTotalSize()
+	if err != nil {
+		return err
+	}
+	if _, err = repo.GetLFSMetaObjectByOid(oid); err == nil {
+		total -= size
+	} else if err != ErrLFSObjectNotExist {
+		return err
+	}
+	if total+size > limit {
+		return ErrRepoSizeLimitExceeded{
+			RepoID:   repo.ID,
+			Size:     total,
+			PushSize: size,
+			Limit:    limit,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_MaxRepoSizeLimit(t *testing.T) {
+	defer func(limit int64) { setting.Repository.MaxSizeLimit = limit }(setting.Repository.MaxSizeLimit)
+
+	u := &User{MaxRepoSize: -1}
+	setting.Repository.MaxSizeLimit = -1
+	assert.EqualValues(t, -1, u.MaxRepoSizeLimit())
+	setting.Repository.MaxSizeLimit = 2
+	assert.EqualValues(t, 2<<20, u.MaxRepoSizeLimit())
+	u.MaxRepoSize = 0
+	assert.EqualValues(t, 0, u.MaxRepoSizeLimit())
+	u.MaxRepoSize = 1
+	assert.EqualValues(t, 1<<20, u.MaxRepoSizeLimit())
+}
+
+func TestRepository_CheckSizeLimit(t *testing.T) {
+	PrepareTestEnv(t)
+	defer func(limit int64) { setting.Repository.MaxSizeLimit = limit }(setting.Repository.MaxSizeLimit)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	size, err := repo.GetTotalSize()
+	assert.NoError(t, err)
+	assert.True(t, size > 0 && size < 1<<20)
+
+	// no limit by default
+	assert.NoError(t, repo.CheckSizeLimit(1<<30))
+
+	owner := AssertExistsAndLoadBean(t, &User{ID: repo.OwnerID}).(*User)
+	owner.MaxRepoSize = 1
+	assert.NoError(t, UpdateUserCols(owner, "max_repo_size"))
+	repo.Owner = nil
+	assert.NoError(t, repo.CheckSizeLimit(1<<10))
+	err = repo.CheckSizeLimit(1 << 20)
+	assert.True(t, IsErrRepoSizeLimitExceeded(err))
+	assert.Equal(t, ErrRepoSizeLimitExceeded{
+		RepoID:   repo.ID,
+		Size:     size,
+		PushSize: 1 << 20,
+		Limit:    1 << 20,
+	}, err)
+
+	// the LFS objects count
+	_, err = NewLFSMetaObject(&LFSMetaObject{
+		Oid:          "2eccdb43825d2a49d99d542daa20075cff1d97d9d2349a8977efe9c03661737c",
+		Size:         1 << 20,
+		RepositoryID: repo.ID,
+	})
+	assert.NoError(t, err)
+	lfsSize, err := repo.GetLFSSize()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1<<20, lfsSize)
+	assert.True(t, IsErrRepoSizeLimitExceeded(repo.CheckSizeLimit(1)))
+	// pushes adding nothing are always allowed
+	assert.NoError(t, repo.CheckSizeLimit(0))
+
+	// the global limit applies when the owner has none
+	owner.MaxRepoSize = -1
+	assert.NoError(t, UpdateUserCols(owner, "max_repo_size"))
+	repo.Owner = nil
+	assert.NoError(t, repo.CheckSizeLimit(1))
+	setting.Repository.MaxSizeLimit = 1
+	assert.True(t, IsErrRepoSizeLimitExceeded(repo.CheckSizeLimit(1)))
+}
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
 
+	"code.gitea.io/git"
 	"github.com/Unknwon/com"
 	"github.com/stretchr/testify/assert"
 )
@@ -176,3 +177,102 @@ func TestTransferOwnership(t *testing.T) {
 
 	CheckConsistencyFor(t, &Repository{}, &User{}, &Team{})
 }
+
+func TestCheckTransferOwnership(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	test := func(doerID int64, newOwnerName string, expected ...RepoTransferProblem) {
+		doer := AssertExistsAndLoadBean(t, &User{ID: doerID}).(*User)
+		problems, err := CheckTransferOwnership(doer, newOwnerName, repo)
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, problems)
+	}
+
+	test(2, "user3")
+	test(2, "user4")
+	test(2, "nobody", RepoTransferProblemOwnerNotExist)
+	test(2, "user2", RepoTransferProblemSameOwner)
+	// user4 is a member but not an owner of user3
+	test(4, "user3", RepoTransferProblemNoPermission)
+	// site admins may transfer repositories to any organization
+	test(1, "user3")
+
+	_, err := x.Insert(&Repository{OwnerID: 3, LowerName: "repo1", Name: "repo1"})
+	assert.NoError(t, err)
+	test(2, "user3", RepoTransferProblemNameCollision)
+	test(4, "user3", RepoTransferProblemNoPermission, RepoTransferProblemNameCollision)
+
+	// nothing has been changed
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, OwnerID: 2})
+}
+
+func TestCreateRepository_DefaultBranch(t *testing.T) {
+	PrepareTestEnv(t)
+	defer func(branch string) {
+		setting.Repository.DefaultBranch = branch
+	}(setting.Repository.DefaultBranch)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	testCreate := func(name, expected string) {
+		repo, err := CreateRepository(user, user, CreateRepoOptions{Name: name})
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, repo.DefaultBranch)
+
+		gitRepo, err := git.OpenRepository(repo.RepoPath())
+		assert.NoError(t, err)
+		head, err := gitRepo.GetHEADBranch()
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, head.Name)
+	}
+
+	setting.Repository.DefaultBranch = ""
+	testCreate("default-branch-empty", "master")
+
+	setting.Repository.DefaultBranch = "main"
+	testCreate("default-branch-main", "main")
+}
+
+func TestCreateRepository_OrgDisabledRepoUnits(t *testing.T) {
+	PrepareTestEnv(t)
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	org.DisabledRepoUnits = []UnitType{UnitTypeWiki, UnitTypeCode}
+	assert.NoError(t, UpdateUserCols(org, "disabled_repo_units"))
+	org = AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	assert.True(t, org.IsRepoUnitDisabled(UnitTypeWiki))
+	// units which could not be disabled are kept
+	assert.False(t, org.IsRepoUnitDisabled(UnitTypeCode))
+
+	repo, err := CreateRepository(doer, org, CreateRepoOptions{Name: "no-wiki"})
+	assert.NoError(t, err)
+	repo, err = GetRepositoryByID(repo.ID)
+	assert.NoError(t, err)
+	assert.False(t, repo.UnitEnabled(UnitTypeWiki))
+	assert.True(t, repo.UnitEnabled(UnitTypeCode))
+	assert.True(t, repo.UnitEnabled(UnitTypeIssues))
+
+	// the repository can still enable the unit
+	var units []RepoUnit
+	for _, unit := range repo.Units {
+		units = append(units, RepoUnit{RepoID: repo.ID, Type: unit.Type, Config: unit.Config})
+	}
+	units = append(units, RepoUnit{RepoID: repo.ID, Type: UnitTypeWiki, Config: new(UnitConfig)})
+	assert.NoError(t, UpdateRepositoryUnits(repo, units))
+
+	// and is not changed by later changes of the defaults
+	org.DisabledRepoUnits = []UnitType{UnitTypeIssues}
+	assert.NoError(t, UpdateUserCols(org, "disabled_repo_units"))
+	repo, err = GetRepositoryByID(repo.ID)
+	assert.NoError(t, err)
+	assert.True(t, repo.UnitEnabled(UnitTypeWiki))
+	assert.True(t, repo.UnitEnabled(UnitTypeIssues))
+
+	repo, err = CreateRepository(doer, org, CreateRepoOptions{Name: "no-issues"})
+	assert.NoError(t, err)
+	repo, err = GetRepositoryByID(repo.ID)
+	assert.NoError(t, err)
+	assert.True(t, repo.UnitEnabled(UnitTypeWiki))
+	assert.False(t, repo.UnitEnabled(UnitTypeIssues))
+}
@@ -74,6 +74,20 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	// Glob patterns of the branches, besides the default branch, whose
+	// commits can close and reopen issues
+	CloseIssuesBranches []string
+}
+
+// ValidateCloseIssuesBranches returns an error if one of the patterns of the
+// branches closing issues is not a valid glob.
+func (cfg *IssuesConfig) ValidateCloseIssuesBranches() error {
+	for _, pattern := range cfg.CloseIssuesBranches {
+		if _, err := util.CompileGlob(pattern, '/'); err != nil {
+			return ErrInvalidCloseIssuesBranchPattern{pattern}
+		}
+	}
+	return nil
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -93,6 +107,8 @@ type PullRequestsConfig struct {
 	AllowRebase               bool
 	AllowRebaseMerge          bool
 	AllowSquash               bool
+	SquashUseCommitAuthor     bool
+	MergeMessageTemplate      string
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -31,6 +31,8 @@ const (
 	ReviewTypeComment
 	// ReviewTypeReject gives feedback blocking merge
 	ReviewTypeReject
+	// ReviewTypeRequest is a review requested from a user but not given yet
+	ReviewTypeRequest
 )
 
 // Icon returns the corresponding icon for the review type
@@ -40,6 +42,8 @@ func (rt ReviewType) Icon() string {
 		return "eye"
 	case ReviewTypeReject:
 		return "x"
+	case ReviewTypeRequest:
+		return "primitive-dot"
 	case ReviewTypeComment, ReviewTypeUnknown:
 		return "comment"
 	default:
@@ -234,6 +238,9 @@ func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
 	if _, err := e.Insert(review); err != nil {
 		return nil, err
 	}
+	if err := review.removeRequest(e); err != nil {
+		return nil, err
+	}
 
 	var reviewHookType HookEventType
 
@@ -304,12 +311,40 @@ func GetCurrentReview(reviewer *User, issue *Issue) (*Review, error) {
 	return getCurrentReview(x, reviewer, issue)
 }
 
+// removeRequest deletes the request of the review once it is submitted.
+func (r *Review) removeRequest(e Engine) error {
+	if r.Type == ReviewTypePending || r.Type == ReviewTypeRequest || r.Type == ReviewTypeUnknown {
+		return nil
+	}
+	_, err := e.Delete(&Review{
+		Type:       ReviewTypeRequest,
+		IssueID:    r.IssueID,
+		ReviewerID: r.ReviewerID,
+	})
+	return err
+}
+
 // UpdateReview will update all cols of the given review in db
 func UpdateReview(r *Review) error {
 	if _, err := x.ID(r.ID).AllCols().Update(r); err != nil {
 		return err
 	}
-	return nil
+	return r.removeRequest(x)
+}
+
+func getReviewRequestsByPullID(e Engine, pullID int64) ([]*User, error) {
+	users := make([]*User, 0, 5)
+	return users, e.
+		Join("INNER", "review", "review.reviewer_id = `user`.id").
+		Where("review.issue_id = ? AND review.type = ?", pullID, ReviewTypeRequest).
+		Asc("review.id").
+		Find(&users)
+}
+
+// GetReviewRequestsByPullID returns the users whose review of a pull request
+// is requested and not given yet.
+func GetReviewRequestsByPullID(pullID int64) ([]*User, error) {
+	return getReviewRequestsByPullID(x, pullID)
 }
 
 // PullReviewersWithType represents the type used to display a review overview
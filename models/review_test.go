@@ -48,7 +48,8 @@ func TestReviewType_Icon(t *testing.T) {
 	assert.Equal(t, "x", ReviewTypeReject.Icon())
 	assert.Equal(t, "comment", ReviewTypeComment.Icon())
 	assert.Equal(t, "comment", ReviewTypeUnknown.Icon())
-	assert.Equal(t, "comment", ReviewType(4).Icon())
+	assert.Equal(t, "primitive-dot", ReviewTypeRequest.Icon())
+	assert.Equal(t, "comment", ReviewType(5).Icon())
 }
 
 func TestFindReviews(t *testing.T) {
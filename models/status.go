@@ -6,10 +6,12 @@ package models
 
 import (
 	"container/list"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -141,8 +143,23 @@ func GetCommitStatuses(repo *Repository, sha string, page int) ([]*CommitStatus,
 
 // GetLatestCommitStatus returns all statuses with a unique context for a given commit.
 func GetLatestCommitStatus(repo *Repository, sha string, page int) ([]*CommitStatus, error) {
+	return getLatestCommitStatus(repo, sha, 10, page*10)
+}
+
+// GetAllLatestCommitStatus returns the latest status of every context for a given commit.
+func GetAllLatestCommitStatus(repo *Repository, sha string) ([]*CommitStatus, error) {
+	return getLatestCommitStatus(repo, sha, 0, 0)
+}
+
+func getLatestCommitStatus(repo *Repository, sha string, limit, start int) ([]*CommitStatus, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if limit > 0 {
+		sess.Limit(limit, start)
+	}
+
 	ids := make([]int64, 0, 10)
-	err := x.Limit(10, page*10).
+	err := sess.
 		Table(&CommitStatus{}).
 		Where("repo_id = ?", repo.ID).And("sha = ?", sha).
 		Select("max( id ) as id").
@@ -157,6 +174,60 @@ func GetLatestCommitStatus(repo *Repository, sha string, page int) ([]*CommitSta
 	return statuses, x.In("id", ids).Find(&statuses)
 }
 
+// commitStatusNewest holds the ID of the newest status of a commit.
+type commitStatusNewest struct {
+	SHA   string
+	MaxID int64
+}
+
+func combinedCommitStatusCacheKey(repoID int64, sha string, newestID int64) string {
+	return fmt.Sprintf("commit_status:%d:%s:%d", repoID, sha, newestID)
+}
+
+// GetCombinedCommitStatuses returns the combined status of each of the given
+// commits of a repository, commits without any status are left out.
+// Combined statuses are cached by the ID of the newest status of their commit,
+// posting a new status therefore never returns an outdated cached value, even
+// while it is computed concurrently.
+func GetCombinedCommitStatuses(repo *Repository, shas []string) (map[string]*CommitStatus, error) {
+	combined := make(map[string]*CommitStatus, len(shas))
+	if len(shas) == 0 {
+		return combined, nil
+	}
+
+	newest := make([]*commitStatusNewest, 0, len(shas))
+	if err := x.Table("commit_status").
+		Select("sha, max(id) AS max_id").
+		Where("repo_id = ?", repo.ID).
+		In("sha", shas).
+		GroupBy("sha").
+		Find(&newest); err != nil {
+		return nil, fmt.Errorf("find newest commit statuses: %v", err)
+	}
+
+	for _, n := range newest {
+		sha := n.SHA
+		data, err := cache.GetString(combinedCommitStatusCacheKey(repo.ID, sha, n.MaxID), func() (string, error) {
+			statuses, err := GetLatestCommitStatus(repo, sha, 0)
+			if err != nil {
+				return "", err
+			}
+			data, err := json.Marshal(CalcCommitStatus(statuses))
+			return string(data), err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("combined commit status [repo_id: %d, sha: %s]: %v", repo.ID, sha, err)
+		}
+
+		status := new(CommitStatus)
+		if err = json.Unmarshal([]byte(data), status); err != nil {
+			return nil, fmt.Errorf("decode combined commit status [repo_id: %d, sha: %s]: %v", repo.ID, sha, err)
+		}
+		combined[sha] = status
+	}
+	return combined, nil
+}
+
 // GetCommitStatus populates a given status for a given commit.
 // NOTE: If ID or Index isn't given, and only Context, TargetURL and/or Description
 //       is given, the CommitStatus created _last_ will be returned.
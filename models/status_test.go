@@ -5,8 +5,16 @@
 package models
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-xorm/xorm"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,3 +44,117 @@ func TestGetCommitStatuses(t *testing.T) {
 		assert.Equal(t, statuses[4].State, CommitStatusError)
 	}
 }
+
+// enableTestCache enables the memory cache until the returned function is
+// called, the cache connection can't be closed so a zero TTL disables it.
+func enableTestCache(t testing.TB) func() {
+	setting.CacheService = &setting.Cache{Adapter: "memory", Interval: 60, TTL: time.Hour}
+	assert.NoError(t, cache.NewContext())
+	return func() {
+		setting.CacheService.TTL = 0
+	}
+}
+
+func TestGetCombinedCommitStatuses(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer enableTestCache(t)()
+
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	sha1 := "1234123412341234123412341234123412341234"
+	sha2 := "2345234523452345234523452345234523452345"
+
+	combined, err := GetCombinedCommitStatuses(repo1, []string{sha1, sha2})
+	assert.NoError(t, err)
+	assert.Len(t, combined, 1)
+	if assert.NotNil(t, combined[sha1]) {
+		assert.EqualValues(t, 5, combined[sha1].ID)
+		assert.Equal(t, CommitStatusError, combined[sha1].State)
+		assert.Equal(t, "deploy/awesomeness", combined[sha1].Context)
+	}
+
+	// A new status invalidates the cached combined status.
+	_, err = x.Insert(&CommitStatus{
+		Index:   6,
+		RepoID:  repo1.ID,
+		State:   CommitStatusSuccess,
+		SHA:     sha1,
+		Context: "deploy/awesomeness",
+	})
+	assert.NoError(t, err)
+	combined, err = GetCombinedCommitStatuses(repo1, []string{sha1})
+	assert.NoError(t, err)
+	if assert.NotNil(t, combined[sha1]) {
+		assert.EqualValues(t, 4, combined[sha1].ID)
+		assert.Equal(t, CommitStatusFailure, combined[sha1].State)
+	}
+}
+
+// queryCountLogger counts the SQL queries logged by xorm.
+type queryCountLogger struct {
+	*xorm.SimpleLogger
+	queries int
+}
+
+func (l *queryCountLogger) Infof(format string, v ...interface{}) {
+	if strings.HasPrefix(format, "[SQL]") {
+		l.queries++
+	}
+}
+
+func benchmarkCombinedCommitStatuses(b *testing.B, cached bool) {
+	assert.NoError(b, PrepareTestDatabase())
+	if cached {
+		defer enableTestCache(b)()
+	}
+
+	// Head commits of 50 pull requests with two status contexts each.
+	repo1 := AssertExistsAndLoadBean(b, &Repository{ID: 1}).(*Repository)
+	shas := make([]string, 50)
+	for i := range shas {
+		shas[i] = fmt.Sprintf("%040x", i+1)
+		for index, context := range []string{"ci", "coverage"} {
+			_, err := x.Insert(&CommitStatus{
+				Index:   int64(index + 1),
+				RepoID:  repo1.ID,
+				State:   CommitStatusSuccess,
+				SHA:     shas[i],
+				Context: context,
+			})
+			assert.NoError(b, err)
+		}
+	}
+
+	counter := &queryCountLogger{SimpleLogger: xorm.NewSimpleLogger(ioutil.Discard)}
+	oldLogger, oldShowSQL := x.Logger(), x.Logger().IsShowSQL()
+	x.SetLogger(counter)
+	x.ShowSQL(true)
+	defer func() {
+		x.SetLogger(oldLogger)
+		x.ShowSQL(oldShowSQL)
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if cached {
+			combined, err := GetCombinedCommitStatuses(repo1, shas)
+			assert.NoError(b, err)
+			assert.Len(b, combined, len(shas))
+		} else {
+			for _, sha := range shas {
+				statuses, err := GetLatestCommitStatus(repo1, sha, 0)
+				assert.NoError(b, err)
+				CalcCommitStatus(statuses)
+			}
+		}
+	}
+	b.ReportMetric(float64(counter.queries)/float64(b.N), "queries/op")
+}
+
+func BenchmarkCombinedCommitStatuses(b *testing.B) {
+	b.Run("Uncached", func(b *testing.B) {
+		benchmarkCombinedCommitStatuses(b, false)
+	})
+	b.Run("Cached", func(b *testing.B) {
+		benchmarkCombinedCommitStatuses(b, true)
+	})
+}
@@ -156,6 +156,16 @@ func GetTwoFactorByUID(uid int64) (*TwoFactor, error) {
 	return twofa, nil
 }
 
+func hasTwoFactorByUID(e Engine, uid int64) (bool, error) {
+	return e.Where("uid = ?", uid).Exist(&TwoFactor{})
+}
+
+// HasTwoFactorByUID returns true if the user has enrolled in two-factor
+// authentication.
+func HasTwoFactorByUID(uid int64) (bool, error) {
+	return hasTwoFactorByUID(x, uid)
+}
+
 // DeleteTwoFactorByID deletes two-factor authentication token by given ID.
 func DeleteTwoFactorByID(id, userID int64) error {
 	cnt, err := x.ID(id).Delete(&TwoFactor{
@@ -50,6 +50,28 @@ var (
 	}
 )
 
+// IsMust returns true if the unit type could not be disabled.
+func (u UnitType) IsMust() bool {
+	for _, tp := range MustRepoUnits {
+		if tp == u {
+			return true
+		}
+	}
+	return false
+}
+
+// OptionalDefaultRepoUnits returns the default unit types which could be
+// disabled.
+func OptionalDefaultRepoUnits() []UnitType {
+	units := make([]UnitType, 0, len(defaultRepoUnits))
+	for _, tp := range defaultRepoUnits {
+		if !tp.IsMust() {
+			units = append(units, tp)
+		}
+	}
+	return units
+}
+
 // Unit is a section of one repository
 type Unit struct {
 	Type    UnitType
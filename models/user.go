@@ -8,8 +8,6 @@ import (
 	"bytes"
 	"container/list"
 	"crypto/md5"
-	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -28,7 +26,6 @@ import (
 	"github.com/go-xorm/builder"
 	"github.com/go-xorm/xorm"
 	"github.com/nfnt/resize"
-	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/ssh"
 
 	"code.gitea.io/git"
@@ -85,6 +82,10 @@ type User struct {
 	Email            string `xorm:"NOT NULL"`
 	KeepEmailPrivate bool
 	Passwd           string `xorm:"NOT NULL"`
+	// PasswdHashAlgo describes the algorithm and the parameters Passwd was hashed with.
+	PasswdHashAlgo string `xorm:"NOT NULL DEFAULT 'pbkdf2'"`
+	// EmailNotifications holds the kinds of issue events the user is notified about by email.
+	EmailNotifications EmailNotificationEvents `xorm:"NOT NULL DEFAULT 31"`
 
 	// MustChangePassword is an attribute that determines if a user
 	// is to change his/her password after registration.
@@ -111,6 +112,8 @@ type User struct {
 	LastRepoVisibility bool
 	// Maximum repository creation limit, -1 means use global default
 	MaxRepoCreation int `xorm:"NOT NULL DEFAULT -1"`
+	// Maximum size of each repository in MiB, -1 means use global default
+	MaxRepoSize int64 `xorm:"NOT NULL DEFAULT -1"`
 
 	// Permissions
 	IsActive                bool `xorm:"INDEX"` // Activate primary email
@@ -132,11 +135,14 @@ type User struct {
 	NumRepos     int
 
 	// For organization
-	Description string
-	NumTeams    int
-	NumMembers  int
-	Teams       []*Team `xorm:"-"`
-	Members     []*User `xorm:"-"`
+	Description      string
+	NumTeams         int
+	NumMembers       int
+	Teams            []*Team `xorm:"-"`
+	Members          []*User `xorm:"-"`
+	RequireTwoFactor bool    `xorm:"NOT NULL DEFAULT false"`
+	// Units disabled on the repositories created in the organization
+	DisabledRepoUnits []UnitType `xorm:"JSON TEXT"`
 
 	// Preferences
 	DiffViewStyle string `xorm:"NOT NULL DEFAULT ''"`
@@ -147,6 +153,9 @@ func (u *User) BeforeUpdate() {
 	if u.MaxRepoCreation < -1 {
 		u.MaxRepoCreation = -1
 	}
+	if u.MaxRepoSize < -1 {
+		u.MaxRepoSize = -1
+	}
 
 	// Organization does not need email
 	u.Email = strings.ToLower(u.Email)
@@ -221,6 +230,19 @@ func (u *User) MaxCreationLimit() int {
 	return u.MaxRepoCreation
 }
 
+// MaxRepoSizeLimit returns the maximum size in bytes of each repository of the
+// user, including its LFS objects. It is -1 if the size is not limited.
+func (u *User) MaxRepoSizeLimit() int64 {
+	limit := u.MaxRepoSize
+	if limit <= -1 {
+		limit = setting.Repository.MaxSizeLimit
+	}
+	if limit <= -1 {
+		return -1
+	}
+	return limit * 1024 * 1024
+}
+
 // CanCreateRepo returns if user login can create a repository
 func (u *User) CanCreateRepo() bool {
 	if u.IsAdmin {
@@ -303,9 +325,18 @@ func (u *User) generateRandomAvatar(e Engine) error {
 		seed = u.Name
 	}
 
-	img, err := avatar.RandomImage([]byte(seed))
-	if err != nil {
-		return fmt.Errorf("RandomImage: %v", err)
+	var img image.Image
+	var err error
+	if setting.DefaultAvatarStyle == setting.AvatarStyleInitials {
+		img, err = avatar.InitialsImage(u.DisplayName(), []byte(u.Name))
+		if err != nil {
+			return fmt.Errorf("InitialsImage: %v", err)
+		}
+	} else {
+		img, err = avatar.RandomImage([]byte(seed))
+		if err != nil {
+			return fmt.Errorf("RandomImage: %v", err)
+		}
 	}
 	// NOTICE for random avatar, it still uses id as avatar name, but custom avatar use md5
 	// since random image is not a user's photo, there is no security for enumable
@@ -333,6 +364,16 @@ func (u *User) generateRandomAvatar(e Engine) error {
 	return nil
 }
 
+// RefreshGeneratedAvatar generates the avatar of the user again if it shows
+// the initials of the user's name, which might have changed since.
+func (u *User) RefreshGeneratedAvatar() error {
+	if setting.DefaultAvatarStyle != setting.AvatarStyleInitials || u.UseCustomAvatar ||
+		u.Avatar != fmt.Sprintf("%d", u.ID) || !com.IsFile(u.CustomAvatarPath()) {
+		return nil
+	}
+	return u.GenerateRandomAvatar()
+}
+
 // SizedRelAvatarLink returns a relative link to the user's avatar. When
 // applicable, the link is for an avatar of the indicated size (in pixels).
 func (u *User) SizedRelAvatarLink(size int) string {
@@ -408,22 +449,6 @@ func (u *User) NewGitSig() *git.Signature {
 	}
 }
 
-func hashPassword(passwd, salt string) string {
-	tempPasswd := pbkdf2.Key([]byte(passwd), []byte(salt), 10000, 50, sha256.New)
-	return fmt.Sprintf("%x", tempPasswd)
-}
-
-// HashPassword hashes a password using PBKDF.
-func (u *User) HashPassword(passwd string) {
-	u.Passwd = hashPassword(passwd, u.Salt)
-}
-
-// ValidatePassword checks if given password matches the one belongs to the user.
-func (u *User) ValidatePassword(passwd string) bool {
-	tempHash := hashPassword(passwd, u.Salt)
-	return subtle.ConstantTimeCompare([]byte(u.Passwd), []byte(tempHash)) == 1
-}
-
 // IsPasswordSet checks if the password is set or left empty
 func (u *User) IsPasswordSet() bool {
 	return !u.ValidatePassword("")
@@ -764,6 +789,7 @@ func CreateUser(u *User) (err error) {
 	}
 
 	u.KeepEmailPrivate = setting.Service.DefaultKeepEmailPrivate
+	u.EmailNotifications = EmailNotifyAll
 
 	u.LowerName = strings.ToLower(u.Name)
 	u.AvatarEmail = u.Email
@@ -777,6 +803,7 @@ func CreateUser(u *User) (err error) {
 	u.HashPassword(u.Passwd)
 	u.AllowCreateOrganization = setting.Service.DefaultAllowCreateOrganization
 	u.MaxRepoCreation = -1
+	u.MaxRepoSize = -1
 
 	if _, err = sess.Insert(u); err != nil {
 		return err
@@ -1004,6 +1031,7 @@ func deleteUser(e *xorm.Session, u *User) error {
 
 	if err = deleteBeans(e,
 		&AccessToken{UID: u.ID},
+		&UserSession{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Access{UserID: u.ID},
 		&Watch{UserID: u.ID},
@@ -1291,6 +1319,62 @@ func GetUserByEmail(email string) (*User, error) {
 	return nil, ErrUserNotExist{0, email, 0}
 }
 
+// GetUsersByEmails returns the users the given emails belong to, either as
+// their primary email or as one of their activated emails, by lower case email.
+// The emails which do not belong to any user are left out.
+func GetUsersByEmails(emails []string) (map[string]*User, error) {
+	users := make(map[string]*User, len(emails))
+	if len(emails) == 0 {
+		return users, nil
+	}
+	lowerEmails := make([]string, len(emails))
+	for i, email := range emails {
+		lowerEmails[i] = strings.ToLower(email)
+	}
+
+	// First try to find the users by primary email
+	primary := make([]*User, 0, len(emails))
+	if err := x.In("email", lowerEmails).Find(&primary); err != nil {
+		return nil, err
+	}
+	for _, u := range primary {
+		users[strings.ToLower(u.Email)] = u
+	}
+
+	// Otherwise, check in alternative list for activated email addresses
+	others := make([]string, 0, len(emails))
+	for _, email := range lowerEmails {
+		if _, ok := users[email]; !ok {
+			others = append(others, email)
+		}
+	}
+	if len(others) == 0 {
+		return users, nil
+	}
+	emailAddresses := make([]*EmailAddress, 0, len(others))
+	if err := x.In("email", others).And("is_activated = ?", true).Find(&emailAddresses); err != nil {
+		return nil, err
+	}
+	uids := make([]int64, 0, len(emailAddresses))
+	for _, emailAddress := range emailAddresses {
+		uids = append(uids, emailAddress.UID)
+	}
+	secondary, err := GetUsersByIDs(uids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*User, len(secondary))
+	for _, u := range secondary {
+		byID[u.ID] = u
+	}
+	for _, emailAddress := range emailAddresses {
+		if u, ok := byID[emailAddress.UID]; ok {
+			users[strings.ToLower(emailAddress.Email)] = u
+		}
+	}
+	return users, nil
+}
+
 // GetUser checks if a user already exists
 func GetUser(user *User) (bool, error) {
 	return x.Get(user)
@@ -1303,8 +1387,9 @@ type SearchUserOptions struct {
 	UID           int64
 	OrderBy       SearchOrderBy
 	Page          int
-	PageSize      int // Can be smaller than or equal to setting.UI.ExplorePagingNum
+	PageSize      int // Defaults to setting.UI.ExplorePagingNum, callers are responsible for any upper bound
 	IsActive      util.OptionalBool
+	IsAdmin       util.OptionalBool
 	SearchByEmail bool // Search by email as well as username/full name
 }
 
@@ -1331,6 +1416,10 @@ func (opts *SearchUserOptions) toConds() builder.Cond {
 		cond = cond.And(builder.Eq{"is_active": opts.IsActive.IsTrue()})
 	}
 
+	if !opts.IsAdmin.IsNone() {
+		cond = cond.And(builder.Eq{"is_admin": opts.IsAdmin.IsTrue()})
+	}
+
 	return cond
 }
 
@@ -1343,7 +1432,7 @@ func SearchUsers(opts *SearchUserOptions) (users []*User, _ int64, _ error) {
 		return nil, 0, fmt.Errorf("Count: %v", err)
 	}
 
-	if opts.PageSize <= 0 || opts.PageSize > setting.UI.ExplorePagingNum {
+	if opts.PageSize <= 0 {
 		opts.PageSize = setting.UI.ExplorePagingNum
 	}
 	if opts.Page <= 0 {
@@ -1528,6 +1617,11 @@ func SyncExternalUsers() {
 			continue
 		}
 
+		if s.IsOAuth2() {
+			syncOAuth2ExternalUsers(s)
+			continue
+		}
+
 		if s.IsLDAP() {
 			log.Trace("Doing: SyncExternalUsers[%s]", s.Name)
 
@@ -0,0 +1,160 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Enumerate all the password hash algorithms
+const (
+	PasswordHashPBKDF2   = "pbkdf2"
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashScrypt   = "scrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// defaultPasswordHashParams are the parameters new passwords are hashed with.
+var defaultPasswordHashParams = map[string][]int{
+	// iterations, key length
+	PasswordHashPBKDF2: {10000, 50},
+	// cost
+	PasswordHashBcrypt: {10},
+	// N, r, p, key length
+	PasswordHashScrypt: {65536, 16, 2, 50},
+	// time, memory in KiB, threads, key length
+	PasswordHashArgon2id: {2, 65536, 8, 50},
+}
+
+// passwordHashAlgo is an algorithm and the parameters a password is hashed with.
+// It is stored as the name of the algorithm followed by its parameters,
+// separated by '$', e.g. "argon2id$2$65536$8$50".
+type passwordHashAlgo struct {
+	Name   string
+	Params []int
+}
+
+// parsePasswordHashAlgo parses a stored password hash algorithm. A name
+// without parameters stands for the default parameters of the algorithm,
+// this is how the passwords hashed before the algorithm was recorded are
+// described. An empty one is the PBKDF2 algorithm of these passwords.
+func parsePasswordHashAlgo(s string) (*passwordHashAlgo, error) {
+	if s == "" {
+		s = PasswordHashPBKDF2
+	}
+	fields := strings.Split(s, "$")
+	defaults, ok := defaultPasswordHashParams[fields[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown password hash algorithm: %q", s)
+	}
+
+	algo := &passwordHashAlgo{Name: fields[0], Params: defaults}
+	if len(fields) == 1 {
+		return algo, nil
+	} else if len(fields)-1 != len(defaults) {
+		return nil, fmt.Errorf("invalid password hash parameters: %q", s)
+	}
+	algo.Params = make([]int, len(defaults))
+	for i, field := range fields[1:] {
+		param, err := strconv.Atoi(field)
+		if err != nil || param <= 0 {
+			return nil, fmt.Errorf("invalid password hash parameters: %q", s)
+		}
+		algo.Params[i] = param
+	}
+	return algo, nil
+}
+
+// String returns the stored form of the algorithm.
+func (algo *passwordHashAlgo) String() string {
+	fields := make([]string, 0, len(algo.Params)+1)
+	fields = append(fields, algo.Name)
+	for _, param := range algo.Params {
+		fields = append(fields, strconv.Itoa(param))
+	}
+	return strings.Join(fields, "$")
+}
+
+// hash hashes the password. The salt is part of the hash returned by bcrypt,
+// the given one is ignored.
+func (algo *passwordHashAlgo) hash(passwd, salt string) (string, error) {
+	p := algo.Params
+	switch algo.Name {
+	case PasswordHashPBKDF2:
+		return fmt.Sprintf("%x", pbkdf2.Key([]byte(passwd), []byte(salt), p[0], p[1], sha256.New)), nil
+	case PasswordHashBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(passwd), p[0])
+		return string(hash), err
+	case PasswordHashScrypt:
+		key, err := scrypt.Key([]byte(passwd), []byte(salt), p[0], p[1], p[2], p[3])
+		return fmt.Sprintf("%x", key), err
+	case PasswordHashArgon2id:
+		return fmt.Sprintf("%x", argon2.IDKey([]byte(passwd), []byte(salt), uint32(p[0]), uint32(p[1]), uint8(p[2]), uint32(p[3]))), nil
+	}
+	return "", fmt.Errorf("unknown password hash algorithm: %q", algo.Name)
+}
+
+// verify checks if the password matches the hash.
+func (algo *passwordHashAlgo) verify(passwd, salt, hash string) bool {
+	if algo.Name == PasswordHashBcrypt {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(passwd)) == nil
+	}
+	tempHash, err := algo.hash(passwd, salt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(tempHash)) == 1
+}
+
+// configuredPasswordHashAlgo returns the algorithm new passwords are hashed with.
+func configuredPasswordHashAlgo() *passwordHashAlgo {
+	name := setting.PasswordHashAlgo
+	if _, ok := defaultPasswordHashParams[name]; !ok {
+		name = PasswordHashPBKDF2
+	}
+	return &passwordHashAlgo{Name: name, Params: defaultPasswordHashParams[name]}
+}
+
+// HashPassword hashes a password using the configured algorithm.
+func (u *User) HashPassword(passwd string) {
+	algo := configuredPasswordHashAlgo()
+	hash, err := algo.hash(passwd, u.Salt)
+	if err != nil {
+		log.Error(4, "HashPassword [%s]: %v", algo, err)
+		algo = &passwordHashAlgo{Name: PasswordHashPBKDF2, Params: defaultPasswordHashParams[PasswordHashPBKDF2]}
+		hash, _ = algo.hash(passwd, u.Salt)
+	}
+	u.Passwd = hash
+	u.PasswdHashAlgo = algo.String()
+}
+
+// ValidatePassword checks if given password matches the one belongs to the user.
+func (u *User) ValidatePassword(passwd string) bool {
+	algo, err := parsePasswordHashAlgo(u.PasswdHashAlgo)
+	if err != nil {
+		log.Error(4, "ValidatePassword [user: %d]: %v", u.ID, err)
+		return false
+	}
+	return algo.verify(passwd, u.Salt, u.Passwd)
+}
+
+// PasswordNeedsRehash returns true if the password of the user is not hashed
+// with the configured algorithm and parameters.
+func (u *User) PasswordNeedsRehash() bool {
+	algo, err := parsePasswordHashAlgo(u.PasswdHashAlgo)
+	return err != nil || algo.String() != configuredPasswordHashAlgo().String()
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePasswordHashAlgo(t *testing.T) {
+	for _, s := range []string{"", "pbkdf2", "pbkdf2$10000$50"} {
+		algo, err := parsePasswordHashAlgo(s)
+		assert.NoError(t, err)
+		assert.Equal(t, "pbkdf2$10000$50", algo.String())
+	}
+
+	algo, err := parsePasswordHashAlgo("argon2id$3$32768$4$32")
+	assert.NoError(t, err)
+	assert.Equal(t, PasswordHashArgon2id, algo.Name)
+	assert.Equal(t, []int{3, 32768, 4, 32}, algo.Params)
+
+	for _, s := range []string{"md5", "bcrypt$10$10", "scrypt$a$16$2$50", "argon2id$0$65536$8$50"} {
+		_, err = parsePasswordHashAlgo(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestValidatePassword_CrossAlgorithm(t *testing.T) {
+	defer func(algo string) {
+		setting.PasswordHashAlgo = algo
+	}(setting.PasswordHashAlgo)
+
+	algos := []string{PasswordHashPBKDF2, PasswordHashBcrypt, PasswordHashScrypt, PasswordHashArgon2id}
+	users := make([]*User, len(algos))
+	for i, algo := range algos {
+		setting.PasswordHashAlgo = algo
+		users[i] = &User{Salt: "abcdefghij"}
+		users[i].HashPassword("password")
+		assert.True(t, strings.HasPrefix(users[i].PasswdHashAlgo, algo+"$"))
+		assert.False(t, users[i].PasswordNeedsRehash())
+	}
+
+	// the stored algorithm is used whatever the configured one is
+	setting.PasswordHashAlgo = PasswordHashArgon2id
+	for i, u := range users {
+		assert.True(t, u.ValidatePassword("password"), u.PasswdHashAlgo)
+		assert.False(t, u.ValidatePassword("wrong password"), u.PasswdHashAlgo)
+		assert.Equal(t, algos[i] != PasswordHashArgon2id, u.PasswordNeedsRehash())
+	}
+
+	// passwords hashed with other parameters are verified with them
+	u := &User{Salt: "abcdefghij", PasswdHashAlgo: "argon2id$1$1024$1$16"}
+	u.Passwd, _ = (&passwordHashAlgo{Name: PasswordHashArgon2id, Params: []int{1, 1024, 1, 16}}).hash("password", u.Salt)
+	assert.True(t, u.ValidatePassword("password"))
+	assert.True(t, u.PasswordNeedsRehash())
+
+	u.PasswdHashAlgo = "unknown"
+	assert.False(t, u.ValidatePassword("password"))
+}
+
+func TestUserSignIn_RehashPassword(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(algo string) {
+		setting.PasswordHashAlgo = algo
+	}(setting.PasswordHashAlgo)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.Equal(t, PasswordHashPBKDF2, user.PasswdHashAlgo)
+	oldPasswd := user.Passwd
+
+	setting.PasswordHashAlgo = PasswordHashArgon2id
+
+	// a failed login keeps the old hash
+	_, err := UserSignIn("user2", "wrong password")
+	assert.True(t, IsErrUserNotExist(err))
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.Equal(t, oldPasswd, user.Passwd)
+	assert.Equal(t, PasswordHashPBKDF2, user.PasswdHashAlgo)
+
+	_, err = UserSignIn("user2", "password")
+	assert.NoError(t, err)
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NotEqual(t, oldPasswd, user.Passwd)
+	assert.Equal(t, "argon2id$2$65536$8$50", user.PasswdHashAlgo)
+
+	// the new hash is used for the next logins
+	_, err = UserSignIn("user2", "password")
+	assert.NoError(t, err)
+	setting.PasswordHashAlgo = PasswordHashPBKDF2
+	_, err = UserSignIn("user2", "password")
+	assert.NoError(t, err)
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.Equal(t, "pbkdf2$10000$50", user.PasswdHashAlgo)
+	assert.Equal(t, oldPasswd, user.Passwd)
+}
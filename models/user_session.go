@@ -0,0 +1,183 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// UserSession represents a web session of a signed in user. The session
+// itself lives in the session store, only its hashed ID is stored along with
+// the metadata needed to recognize and revoke it. A revoked session is kept
+// until it is signed out on its next request.
+type UserSession struct {
+	ID           int64  `xorm:"pk autoincr"`
+	UID          int64  `xorm:"INDEX NOT NULL"`
+	SessionHash  string `xorm:"UNIQUE NOT NULL"`
+	UserAgent    string `xorm:"TEXT"`
+	IP           string
+	IsRevoked    bool           `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix  util.TimeStamp `xorm:"INDEX created"`
+	LastSeenUnix util.TimeStamp `xorm:"INDEX"`
+}
+
+// UserSessionSeenInterval is the number of seconds the last seen time of
+// a session is updated at most once per.
+const UserSessionSeenInterval = 60
+
+func hashSessionID(sid string) string {
+	h := sha256.Sum256([]byte(sid))
+	return hex.EncodeToString(h[:])
+}
+
+// activeSessionsSince returns the time before which the sessions have expired
+// in the session store.
+func activeSessionsSince() util.TimeStamp {
+	return util.TimeStampNow() - util.TimeStamp(setting.SessionConfig.Maxlifetime)
+}
+
+// CreateUserSession records the session with given ID of the user, the
+// records of the sessions of the user which have expired are removed.
+func CreateUserSession(s *UserSession, sid string) error {
+	s.SessionHash = hashSessionID(sid)
+	s.LastSeenUnix = util.TimeStampNow()
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if _, err := sess.Where("uid = ? AND last_seen_unix < ?", s.UID, activeSessionsSince()).
+		Delete(new(UserSession)); err != nil {
+		return fmt.Errorf("delete expired sessions: %v", err)
+	}
+	// A session ID reused by a new sign in replaces the old record.
+	if _, err := sess.Delete(&UserSession{SessionHash: s.SessionHash}); err != nil {
+		return fmt.Errorf("delete session: %v", err)
+	}
+	if _, err := sess.Insert(s); err != nil {
+		return fmt.Errorf("insert session: %v", err)
+	}
+	return sess.Commit()
+}
+
+// GetUserSessionBySessionID returns the record of the session with given ID.
+func GetUserSessionBySessionID(sid string) (*UserSession, error) {
+	s := &UserSession{SessionHash: hashSessionID(sid)}
+	has, err := x.Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrUserSessionNotExist{}
+	}
+	return s, nil
+}
+
+// GetUserSessionByID returns the record of the session with given ID of the user.
+func GetUserSessionByID(uid, id int64) (*UserSession, error) {
+	s := &UserSession{ID: id, UID: uid}
+	has, err := x.Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrUserSessionNotExist{id}
+	}
+	return s, nil
+}
+
+// IsSession returns true if s is the record of the session with given ID.
+func (s *UserSession) IsSession(sid string) bool {
+	return s.SessionHash == hashSessionID(sid)
+}
+
+// UpdateLastSeen updates the last seen time of the session if it has not been
+// updated for UserSessionSeenInterval, and sets the IP and user agent it is
+// used from.
+func (s *UserSession) UpdateLastSeen(ip, userAgent string) error {
+	now := util.TimeStampNow()
+	if now-s.LastSeenUnix < UserSessionSeenInterval && s.IP == ip && s.UserAgent == userAgent {
+		return nil
+	}
+	s.LastSeenUnix = now
+	s.IP = ip
+	s.UserAgent = userAgent
+	_, err := x.ID(s.ID).Cols("last_seen_unix", "ip", "user_agent").Update(s)
+	return err
+}
+
+// ListUserSessions returns the active sessions of the user, the most recently
+// used first.
+func ListUserSessions(uid int64) ([]*UserSession, error) {
+	sessions := make([]*UserSession, 0, 5)
+	return sessions, x.
+		Where("uid = ? AND is_revoked = ? AND last_seen_unix >= ?", uid, false, activeSessionsSince()).
+		Desc("last_seen_unix").
+		Find(&sessions)
+}
+
+// updateUserRands changes the rands of the user, which invalidates the
+// remember me cookies of the user.
+func updateUserRands(e Engine, u *User) (err error) {
+	if u.Rands, err = GetUserSalt(); err != nil {
+		return err
+	}
+	if err = updateUserCols(e, u, "rands"); err != nil {
+		return fmt.Errorf("update rands: %v", err)
+	}
+	return nil
+}
+
+// RevokeUserSession revokes the session with given ID of the user, it is
+// signed out on its next request. The remember me cookies of the user are
+// invalidated as well, the one of the revoked session could sign in again
+// otherwise.
+func RevokeUserSession(u *User, id int64) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	cnt, err := sess.ID(id).Where("uid = ? AND is_revoked = ?", u.ID, false).
+		Cols("is_revoked").Update(&UserSession{IsRevoked: true})
+	if err != nil {
+		return err
+	} else if cnt != 1 {
+		return ErrUserSessionNotExist{id}
+	}
+	if err = updateUserRands(sess, u); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteUserSessionBySessionID removes the record of the session with given ID.
+func DeleteUserSessionBySessionID(sid string) error {
+	_, err := x.Delete(&UserSession{SessionHash: hashSessionID(sid)})
+	return err
+}
+
+// RevokeUserSessions revokes all sessions of the user. The remember me
+// cookies of the user are invalidated as well so the revoked sessions
+// cannot sign in again on their own.
+func RevokeUserSessions(u *User) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+	if _, err = sess.Where("uid = ?", u.ID).Cols("is_revoked").
+		Update(&UserSession{IsRevoked: true}); err != nil {
+		return fmt.Errorf("revoke sessions: %v", err)
+	}
+	if err = updateUserRands(sess, u); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
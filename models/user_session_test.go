@@ -0,0 +1,97 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSessions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(maxlifetime int64) {
+		setting.SessionConfig.Maxlifetime = maxlifetime
+	}(setting.SessionConfig.Maxlifetime)
+	setting.SessionConfig.Maxlifetime = 86400
+
+	expired := &UserSession{UID: 2, IP: "127.0.0.1"}
+	assert.NoError(t, CreateUserSession(expired, "expired"))
+	_, err := x.ID(expired.ID).Cols("last_seen_unix").Update(&UserSession{LastSeenUnix: util.TimeStampNow() - 86401})
+	assert.NoError(t, err)
+
+	first := &UserSession{UID: 2, IP: "127.0.0.1", UserAgent: "first"}
+	assert.NoError(t, CreateUserSession(first, "first"))
+	second := &UserSession{UID: 2, IP: "127.0.0.2", UserAgent: "second"}
+	assert.NoError(t, CreateUserSession(second, "second"))
+	assert.NoError(t, CreateUserSession(&UserSession{UID: 4}, "other"))
+
+	// expired sessions are removed when a new one is recorded
+	AssertNotExistsBean(t, &UserSession{ID: expired.ID})
+
+	s, err := GetUserSessionBySessionID("second")
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, s.ID)
+	assert.True(t, s.IsSession("second"))
+	assert.False(t, s.IsSession("first"))
+	assert.NotEqual(t, "second", s.SessionHash)
+
+	_, err = GetUserSessionBySessionID("unknown")
+	assert.True(t, IsErrUserSessionNotExist(err))
+	_, err = GetUserSessionByID(4, first.ID)
+	assert.True(t, IsErrUserSessionNotExist(err))
+
+	sessions, err := ListUserSessions(2)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	rands := user.Rands
+	assert.NoError(t, RevokeUserSession(user, first.ID))
+	assert.True(t, IsErrUserSessionNotExist(RevokeUserSession(user, first.ID)))
+	assert.True(t, IsErrUserSessionNotExist(RevokeUserSession(AssertExistsAndLoadBean(t, &User{ID: 4}).(*User), second.ID)))
+	s, err = GetUserSessionByID(2, first.ID)
+	assert.NoError(t, err)
+	assert.True(t, s.IsRevoked)
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NotEqual(t, rands, user.Rands)
+
+	sessions, err = ListUserSessions(2)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, second.ID, sessions[0].ID)
+	}
+
+	rands = user.Rands
+	assert.NoError(t, RevokeUserSessions(user))
+	sessions, err = ListUserSessions(2)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 0)
+	user = AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NotEqual(t, rands, user.Rands)
+	AssertExistsAndLoadBean(t, &UserSession{UID: 4, IsRevoked: false})
+
+	assert.NoError(t, DeleteUserSessionBySessionID("second"))
+	AssertNotExistsBean(t, &UserSession{ID: second.ID})
+}
+
+func TestUserSession_UpdateLastSeen(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	s := &UserSession{UID: 2, IP: "127.0.0.1", UserAgent: "agent"}
+	assert.NoError(t, CreateUserSession(s, "session"))
+	lastSeen := s.LastSeenUnix - UserSessionSeenInterval
+	_, err := x.ID(s.ID).Cols("last_seen_unix").Update(&UserSession{LastSeenUnix: lastSeen})
+	assert.NoError(t, err)
+	s.LastSeenUnix = lastSeen
+
+	assert.NoError(t, s.UpdateLastSeen("127.0.0.2", "agent"))
+	s = AssertExistsAndLoadBean(t, &UserSession{ID: s.ID}).(*UserSession)
+	assert.Equal(t, "127.0.0.2", s.IP)
+	assert.True(t, s.LastSeenUnix > lastSeen)
+}
@@ -22,6 +22,26 @@ func TestGetUserEmailsByNames(t *testing.T) {
 	assert.Equal(t, []string{"user8@example.com", "user5@example.com"}, GetUserEmailsByNames([]string{"user8", "user5"}))
 }
 
+func TestGetUsersByEmails(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user101@example.com is an activated email of user10, the user of
+	// user9999999@example.com does not exist
+	users, err := GetUsersByEmails([]string{"User2@example.com", "user21@example.com",
+		"user101@example.com", "user9999999@example.com", "unknown@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+	for email, id := range map[string]int64{"user2@example.com": 2, "user21@example.com": 21, "user101@example.com": 10} {
+		if assert.Contains(t, users, email) {
+			assert.EqualValues(t, id, users[email].ID)
+		}
+	}
+
+	users, err = GetUsersByEmails(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
 func TestCanCreateOrganization(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -91,6 +111,12 @@ func TestSearchUsers(t *testing.T) {
 	// order by name asc default
 	testUserSuccess(&SearchUserOptions{Keyword: "user1", Page: 1, IsActive: util.OptionalBoolTrue},
 		[]int64{1, 10, 11, 12, 13, 14, 15, 16, 18})
+
+	testUserSuccess(&SearchUserOptions{OrderBy: "id ASC", Page: 1, IsAdmin: util.OptionalBoolTrue},
+		[]int64{1})
+
+	testUserSuccess(&SearchUserOptions{OrderBy: "id ASC", Page: 2, PageSize: 5, IsAdmin: util.OptionalBoolFalse},
+		[]int64{10, 11, 12, 13, 14})
 }
 
 func TestDeleteUser(t *testing.T) {
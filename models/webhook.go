@@ -6,13 +6,21 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
+	"io"
 	"io/ioutil"
+	"net/url"
 	"strings"
 	"time"
 
+	"code.gitea.io/git"
 	"code.gitea.io/gitea/modules/httplib"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -83,6 +91,66 @@ type HookEvent struct {
 	ChooseEvents   bool `json:"choose_events"`
 
 	HookEvents `json:"events"`
+
+	// BranchFilter and PathFilter are lists of glob patterns separated by
+	// semicolons restricting the branch events to the matching branches and
+	// changed paths, they are ignored if empty.
+	BranchFilter string `json:"branch_filter"`
+	PathFilter   string `json:"path_filter"`
+}
+
+func splitHookFilter(filter string) []string {
+	patterns := make([]string, 0, 2)
+	for _, pattern := range strings.Split(filter, ";") {
+		if pattern = strings.TrimSpace(pattern); len(pattern) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func matchHookFilter(patterns []string, names ...string) bool {
+	for _, name := range names {
+		for _, pattern := range patterns {
+			if util.GlobMatch(pattern, name, '/') {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetBranchFilterPatterns returns the glob patterns of the branch filter.
+func (e *HookEvent) GetBranchFilterPatterns() []string {
+	return splitHookFilter(e.BranchFilter)
+}
+
+// GetPathFilterPatterns returns the glob patterns of the path filter.
+func (e *HookEvent) GetPathFilterPatterns() []string {
+	return splitHookFilter(e.PathFilter)
+}
+
+// ValidateFilters returns an error if one of the branch or path filter
+// patterns is not a valid glob.
+func (e *HookEvent) ValidateFilters() error {
+	for _, pattern := range append(e.GetBranchFilterPatterns(), e.GetPathFilterPatterns()...) {
+		if _, err := util.CompileGlob(pattern, '/'); err != nil {
+			return ErrInvalidWebhookFilterPattern{pattern}
+		}
+	}
+	return nil
+}
+
+// MatchBranch returns if events of the given branch are delivered.
+func (e *HookEvent) MatchBranch(branch string) bool {
+	patterns := e.GetBranchFilterPatterns()
+	return len(patterns) == 0 || matchHookFilter(patterns, branch)
+}
+
+// MatchPaths returns if events changing the given paths are delivered.
+func (e *HookEvent) MatchPaths(paths []string) bool {
+	patterns := e.GetPathFilterPatterns()
+	return len(patterns) == 0 || matchHookFilter(patterns, paths...)
 }
 
 // HookStatus is the status of a web hook
@@ -343,6 +411,8 @@ func deleteWebhook(bean *Webhook) (err error) {
 		return ErrWebhookNotExist{ID: bean.ID}
 	} else if _, err = sess.Delete(&HookTask{HookID: bean.ID}); err != nil {
 		return err
+	} else if _, err = sess.Delete(&HookTaskAttempt{HookID: bean.ID}); err != nil {
+		return err
 	}
 
 	return sess.Commit()
@@ -467,6 +537,13 @@ type HookTask struct {
 	Delivered       int64
 	DeliveredString string `xorm:"-"`
 
+	// Retry info, a failed task is delivered again at NextDeliveryUnix
+	// until it has been attempted setting.Webhook.MaxAttempts times. The
+	// request and response of each attempt are kept as a HookTaskAttempt,
+	// those of the last one are also recorded below.
+	Attempts         int
+	NextDeliveryUnix util.TimeStamp `xorm:"INDEX"`
+
 	// History info.
 	IsSucceed       bool
 	RequestContent  string        `xorm:"TEXT"`
@@ -525,6 +602,71 @@ func HookTasks(hookID int64, page int) ([]*HookTask, error) {
 		Find(&tasks)
 }
 
+// HookTaskAttempt represents the request and response of an attempt to
+// deliver a hook task.
+type HookTaskAttempt struct {
+	ID              int64 `xorm:"pk autoincr"`
+	RepoID          int64 `xorm:"INDEX"`
+	HookID          int64 `xorm:"INDEX"`
+	HookTaskID      int64 `xorm:"INDEX"`
+	Attempt         int
+	Delivered       int64
+	IsSucceed       bool
+	RequestContent  string        `xorm:"TEXT"`
+	RequestInfo     *HookRequest  `xorm:"-"`
+	ResponseContent string        `xorm:"TEXT"`
+	ResponseInfo    *HookResponse `xorm:"-"`
+}
+
+// AfterLoad updates the request and response information of the attempt
+func (a *HookTaskAttempt) AfterLoad() {
+	if len(a.RequestContent) > 0 {
+		a.RequestInfo = &HookRequest{}
+		if err := json.Unmarshal([]byte(a.RequestContent), a.RequestInfo); err != nil {
+			log.Error(3, "Unmarshal RequestContent[%d]: %v", a.ID, err)
+		}
+	}
+	if len(a.ResponseContent) > 0 {
+		a.ResponseInfo = &HookResponse{}
+		if err := json.Unmarshal([]byte(a.ResponseContent), a.ResponseInfo); err != nil {
+			log.Error(3, "Unmarshal ResponseContent[%d]: %v", a.ID, err)
+		}
+	}
+}
+
+// addAttempt records the request and response of the attempt which was just
+// made to deliver the hook task.
+func (t *HookTask) addAttempt() error {
+	_, err := x.Insert(&HookTaskAttempt{
+		RepoID:          t.RepoID,
+		HookID:          t.HookID,
+		HookTaskID:      t.ID,
+		Attempt:         t.Attempts,
+		Delivered:       t.Delivered,
+		IsSucceed:       t.IsSucceed,
+		RequestContent:  t.simpleMarshalJSON(t.RequestInfo),
+		ResponseContent: t.simpleMarshalJSON(t.ResponseInfo),
+	})
+	return err
+}
+
+// GetHookTaskAttempts returns the attempts of the given hook tasks by the ID
+// of their task, in the order they were made.
+func GetHookTaskAttempts(taskIDs []int64) (map[int64][]*HookTaskAttempt, error) {
+	attemptsMap := make(map[int64][]*HookTaskAttempt, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return attemptsMap, nil
+	}
+	attempts := make([]*HookTaskAttempt, 0, len(taskIDs))
+	if err := x.In("hook_task_id", taskIDs).Asc("id").Find(&attempts); err != nil {
+		return nil, err
+	}
+	for _, a := range attempts {
+		attemptsMap[a.HookTaskID] = append(attemptsMap[a.HookTaskID], a)
+	}
+	return attemptsMap, nil
+}
+
 // CreateHookTask creates a new hook task,
 // it handles conversion from Payload to PayloadContent.
 func CreateHookTask(t *HookTask) error {
@@ -542,6 +684,39 @@ func createHookTask(e Engine, t *HookTask) error {
 	return err
 }
 
+// GetHookTaskByHookID returns the hook task with given ID of the webhook.
+func GetHookTaskByHookID(hookID, id int64) (*HookTask, error) {
+	t := &HookTask{ID: id, HookID: hookID}
+	has, err := x.Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHookTaskNotExist{id, hookID}
+	}
+	return t, nil
+}
+
+// RedeliverHookTask creates a new hook task delivering the payload of the
+// given one again. It is added to the task queue like a new event would be.
+func RedeliverHookTask(t *HookTask) (*HookTask, error) {
+	redelivery := &HookTask{
+		RepoID:         t.RepoID,
+		HookID:         t.HookID,
+		UUID:           gouuid.NewV4().String(),
+		Type:           t.Type,
+		URL:            t.URL,
+		PayloadContent: t.PayloadContent,
+		ContentType:    t.ContentType,
+		EventType:      t.EventType,
+		IsSSL:          t.IsSSL,
+	}
+	if _, err := x.Insert(redelivery); err != nil {
+		return nil, err
+	}
+	go HookQueue.Add(redelivery.RepoID)
+	return redelivery, nil
+}
+
 // UpdateHookTask updates information of hook task.
 func UpdateHookTask(t *HookTask) error {
 	_, err := x.ID(t.ID).AllCols().Update(t)
@@ -562,6 +737,12 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 		}
 	}
 
+	if match, err := w.matchFilters(repo, event, p); err != nil {
+		return err
+	} else if !match {
+		return nil
+	}
+
 	var payloader api.Payloader
 	var err error
 	// Use separate objects so modifications won't be made on payload on non-Gogs/Gitea type hooks.
@@ -601,6 +782,47 @@ func prepareWebhook(e Engine, w *Webhook, repo *Repository, event HookEventType,
 	return nil
 }
 
+// emptyTreeSHA is the ID of the tree without any entry, which git knows even
+// if it is not stored in the repository.
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// matchFilters returns if the payload of a branch event passes the branch and
+// path filters of the webhook. Events without a branch always pass.
+func (w *Webhook) matchFilters(repo *Repository, event HookEventType, p api.Payloader) (bool, error) {
+	switch event {
+	case HookEventCreate:
+		if payload, ok := p.(*api.CreatePayload); ok && payload.RefType == "branch" {
+			return w.MatchBranch(payload.Ref), nil
+		}
+	case HookEventDelete:
+		if payload, ok := p.(*api.DeletePayload); ok && payload.RefType == "branch" {
+			return w.MatchBranch(payload.Ref), nil
+		}
+	case HookEventPush:
+		payload, ok := p.(*api.PushPayload)
+		if !ok || !strings.HasPrefix(payload.Ref, git.BranchPrefix) {
+			return true, nil
+		}
+		if !w.MatchBranch(strings.TrimPrefix(payload.Ref, git.BranchPrefix)) {
+			return false, nil
+		}
+		if len(w.GetPathFilterPatterns()) == 0 {
+			return true, nil
+		}
+
+		// a deleted branch changes no path
+		if payload.After == git.EmptySHA {
+			return false, nil
+		}
+		files, err := GetChangedFilesOfPush(repo.RepoPath(), payload.Before, payload.After)
+		if err != nil {
+			return false, fmt.Errorf("GetChangedFilesOfPush: %v", err)
+		}
+		return w.MatchPaths(files), nil
+	}
+	return true, nil
+}
+
 // PrepareWebhooks adds new webhooks to task queue for given payload.
 func PrepareWebhooks(repo *Repository, event HookEventType, p api.Payloader) error {
 	return prepareWebhooks(x, repo, event, p)
@@ -634,8 +856,37 @@ func prepareWebhooks(e Engine, repo *Repository, event HookEventType, p api.Payl
 	return nil
 }
 
+// maxHookRetryDelay is the longest delay between two attempts of a hook task.
+const maxHookRetryDelay = 24 * time.Hour
+
+// hookRetryDelay returns the delay before the next attempt of a hook task,
+// which has failed the given number of times. The delay starts at
+// setting.Webhook.RetryBackoff and doubles after every failure.
+func hookRetryDelay(failures int) time.Duration {
+	delay := time.Duration(setting.Webhook.RetryBackoff) * time.Second
+	for i := 1; i < failures && delay < maxHookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxHookRetryDelay {
+		return maxHookRetryDelay
+	}
+	return delay
+}
+
+// hookResponseBodyLimit is the maximum length of the response body which is
+// recorded for a hook task.
+const hookResponseBodyLimit = 4096
+
+// signPayload returns the hex encoded HMAC of the payload keyed with the secret.
+func signPayload(h func() hash.Hash, secret string, payload []byte) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (t *HookTask) deliver() {
 	t.IsDelivered = true
+	t.Attempts++
 
 	timeout := time.Duration(setting.Webhook.DeliverTimeout) * time.Second
 	req := httplib.Post(t.URL).SetTimeout(timeout, timeout).
@@ -647,11 +898,23 @@ func (t *HookTask) deliver() {
 		HeaderWithSensitiveCase("X-GitHub-Event", string(t.EventType)).
 		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify})
 
+	var body string
 	switch t.ContentType {
 	case ContentTypeJSON:
-		req = req.Header("Content-Type", "application/json").Body(t.PayloadContent)
+		body = t.PayloadContent
+		req = req.Header("Content-Type", "application/json")
 	case ContentTypeForm:
-		req.Param("payload", t.PayloadContent)
+		body = "payload=" + url.QueryEscape(t.PayloadContent)
+		req = req.Header("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req = req.Body(body)
+
+	// Sign the request body like GitHub does, so existing receivers can verify it.
+	if w, err := GetWebhookByID(t.HookID); err != nil {
+		log.Error(5, "GetWebhookByID: %v", err)
+	} else if len(w.Secret) > 0 {
+		req = req.Header("X-Hub-Signature", "sha1="+signPayload(sha1.New, w.Secret, []byte(body))).
+			Header("X-Hub-Signature-256", "sha256="+signPayload(sha256.New, w.Secret, []byte(body)))
 	}
 
 	// Record delivery information.
@@ -670,12 +933,22 @@ func (t *HookTask) deliver() {
 		t.Delivered = time.Now().UnixNano()
 		if t.IsSucceed {
 			log.Trace("Hook delivered: %s", t.UUID)
+		} else if t.Attempts < setting.Webhook.MaxAttempts {
+			delay := hookRetryDelay(t.Attempts)
+			log.Trace("Hook delivery failed, attempt %d, retrying in %v: %s", t.Attempts, delay, t.UUID)
+			t.IsDelivered = false
+			t.NextDeliveryUnix = util.TimeStamp(time.Now().Add(delay).Unix())
 		} else {
-			log.Trace("Hook delivery failed: %s", t.UUID)
+			log.Trace("Hook delivery failed, giving up after %d attempts: %s", t.Attempts, t.UUID)
 		}
 
 		if err := UpdateHookTask(t); err != nil {
 			log.Error(4, "UpdateHookTask [%d]: %v", t.ID, err)
+		} else if !t.IsDelivered {
+			scheduleHookTask(t.RepoID, hookRetryDelay(t.Attempts))
+		}
+		if err := t.addAttempt(); err != nil {
+			log.Error(4, "addAttempt [%d]: %v", t.ID, err)
 		}
 
 		// Update webhook last delivery status.
@@ -709,7 +982,7 @@ func (t *HookTask) deliver() {
 		t.ResponseInfo.Headers[k] = strings.Join(vals, ",")
 	}
 
-	p, err := ioutil.ReadAll(resp.Body)
+	p, err := ioutil.ReadAll(io.LimitReader(resp.Body, hookResponseBodyLimit))
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("read body: %s", err)
 		return
@@ -717,6 +990,15 @@ func (t *HookTask) deliver() {
 	t.ResponseInfo.Body = string(p)
 }
 
+// scheduleHookTask adds the repository to the task queue once delay has
+// passed, so the undelivered hook tasks which are due by then are delivered
+// without holding up the queue in the meantime.
+func scheduleHookTask(repoID int64, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		HookQueue.Add(repoID)
+	})
+}
+
 // DeliverHooks checks and delivers undelivered hooks.
 // TODO: shoot more hooks at same time.
 func DeliverHooks() {
@@ -727,8 +1009,13 @@ func DeliverHooks() {
 		return
 	}
 
-	// Update hook task status.
+	// Update hook task status, retries which are not due yet are scheduled again.
+	now := util.TimeStampNow()
 	for _, t := range tasks {
+		if t.NextDeliveryUnix > now {
+			scheduleHookTask(t.RepoID, time.Duration(t.NextDeliveryUnix-now)*time.Second)
+			continue
+		}
 		t.deliver()
 	}
 
@@ -744,7 +1031,8 @@ func DeliverHooks() {
 		}
 
 		tasks = make([]*HookTask, 0, 5)
-		if err := x.Where("repo_id=? AND is_delivered=?", repoID, false).Find(&tasks); err != nil {
+		if err := x.Where("repo_id=? AND is_delivered=? AND next_delivery_unix<=?",
+			repoID, false, util.TimeStampNow()).Find(&tasks); err != nil {
 			log.Error(4, "Get repository [%s] hook tasks: %v", repoID, err)
 			continue
 		}
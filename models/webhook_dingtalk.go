@@ -299,8 +299,8 @@ func getDingtalkPullRequestApprovalPayload(p *api.PullRequestPayload, event Hook
 func getDingtalkRepositoryPayload(p *api.RepositoryPayload) (*DingtalkPayload, error) {
 	var title, url string
 	switch p.Action {
-	case api.HookRepoCreated:
-		title = fmt.Sprintf("[%s] Repository created", p.Repository.FullName)
+	case api.HookRepoCreated, HookRepoArchived, HookRepoUnarchived:
+		title = fmt.Sprintf("[%s] Repository %s", p.Repository.FullName, p.Action)
 		url = p.Repository.HTMLURL
 		return &DingtalkPayload{
 			MsgType: "actionCard",
@@ -445,6 +445,14 @@ func getDiscordRepositoryPayload(p *api.RepositoryPayload, meta *DiscordMeta) (*
 	case api.HookRepoDeleted:
 		title = fmt.Sprintf("[%s] Repository deleted", p.Repository.FullName)
 		color = warnColor
+	case HookRepoArchived:
+		title = fmt.Sprintf("[%s] Repository archived", p.Repository.FullName)
+		url = p.Repository.HTMLURL
+		color = warnColor
+	case HookRepoUnarchived:
+		title = fmt.Sprintf("[%s] Repository unarchived", p.Repository.FullName)
+		url = p.Repository.HTMLURL
+		color = successColor
 	}
 
 	return &DiscordPayload{
@@ -364,6 +364,12 @@ func getSlackRepositoryPayload(p *api.RepositoryPayload, slack *SlackMeta) (*Sla
 		title = p.Repository.HTMLURL
 	case api.HookRepoDeleted:
 		text = fmt.Sprintf("[%s] Repository deleted by %s", p.Repository.FullName, senderLink)
+	case HookRepoArchived:
+		text = fmt.Sprintf("[%s] Repository archived by %s", p.Repository.FullName, senderLink)
+		title = p.Repository.HTMLURL
+	case HookRepoUnarchived:
+		text = fmt.Sprintf("[%s] Repository unarchived by %s", p.Repository.FullName, senderLink)
+		title = p.Repository.HTMLURL
 	}
 
 	return &SlackPayload{
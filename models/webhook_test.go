@@ -5,9 +5,20 @@
 package models
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/stretchr/testify/assert"
@@ -267,6 +278,207 @@ func TestPrepareWebhooks(t *testing.T) {
 	}
 }
 
-// TODO TestHookTask_deliver
+func TestPrepareWebhooks_BranchFilter(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.BranchFilter = "develop;release/*"
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, UpdateWebhook(w))
+
+	hookTask := &HookTask{RepoID: repo.ID, HookID: 1, EventType: HookEventPush}
+	assert.NoError(t, PrepareWebhooks(repo, HookEventPush, &api.PushPayload{
+		Ref:    "refs/heads/master",
+		Before: git.EmptySHA,
+		After:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	}))
+	AssertNotExistsBean(t, hookTask)
+
+	// the filters do not apply to events without a branch
+	assert.NoError(t, PrepareWebhooks(repo, HookEventPush, &api.PushPayload{
+		Ref:    "refs/tags/v1.0",
+		Before: git.EmptySHA,
+		After:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	}))
+	AssertExistsAndLoadBean(t, hookTask)
+}
+
+func TestPrepareWebhooks_PathFilter(t *testing.T) {
+	PrepareTestEnv(t)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	w := AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	w.BranchFilter = "master"
+	w.PathFilter = "docs/**;*.md"
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, UpdateWebhook(w))
+
+	// the initial commit adds README.md
+	hookTask := &HookTask{RepoID: repo.ID, HookID: 1, EventType: HookEventPush}
+	assert.NoError(t, PrepareWebhooks(repo, HookEventPush, &api.PushPayload{
+		Ref:    "refs/heads/master",
+		Before: git.EmptySHA,
+		After:  "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+	}))
+	AssertExistsAndLoadBean(t, hookTask)
+
+	w.PathFilter = "docs/**"
+	assert.True(t, w.MatchPaths([]string{"docs/install/index.md"}))
+	assert.False(t, w.MatchPaths([]string{"README.md"}))
+}
+
+func TestHookEvent_ValidateFilters(t *testing.T) {
+	e := &HookEvent{BranchFilter: "master; release/*", PathFilter: "docs/**"}
+	assert.NoError(t, e.ValidateFilters())
+	assert.Equal(t, []string{"master", "release/*"}, e.GetBranchFilterPatterns())
+
+	e.PathFilter = "docs/[z-a]"
+	err := e.ValidateFilters()
+	assert.True(t, IsErrInvalidWebhookFilterPattern(err))
+	assert.Equal(t, ErrInvalidWebhookFilterPattern{"docs/[z-a]"}, err)
+}
+
+func TestSignPayload(t *testing.T) {
+	payload := []byte("The quick brown fox jumps over the lazy dog")
+	assert.Equal(t, "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9", signPayload(sha1.New, "key", payload))
+	assert.Equal(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", signPayload(sha256.New, "key", payload))
+}
+
+func TestHookTask_deliver(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(deliverTimeout int) {
+		setting.Webhook.DeliverTimeout = deliverTimeout
+	}(setting.Webhook.DeliverTimeout)
+	setting.Webhook.DeliverTimeout = 5
+
+	var header http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		assert.NoError(t, r.ParseForm())
+		body = r.PostForm.Get("payload")
+	}))
+	defer server.Close()
+
+	hookTask := &HookTask{
+		RepoID:      1,
+		HookID:      1,
+		Type:        GITEA,
+		URL:         server.URL,
+		ContentType: ContentTypeForm,
+		EventType:   HookEventPush,
+		Payloader:   &api.PushPayload{Ref: "refs/heads/master"},
+	}
+	assert.NoError(t, CreateHookTask(hookTask))
+
+	// Without a secret the payload isn't signed.
+	hookTask.deliver()
+	assert.True(t, hookTask.IsSucceed)
+	assert.Equal(t, hookTask.PayloadContent, body)
+	assert.Empty(t, header.Get("X-Hub-Signature"))
+	assert.Empty(t, header.Get("X-Hub-Signature-256"))
+
+	_, err := x.ID(1).Cols("secret").Update(&Webhook{Secret: "secret"})
+	assert.NoError(t, err)
+	hookTask.deliver()
+	signedBody := []byte("payload=" + url.QueryEscape(hookTask.PayloadContent))
+	assert.Equal(t, "sha1="+signPayload(sha1.New, "secret", signedBody), header.Get("X-Hub-Signature"))
+	assert.Equal(t, "sha256="+signPayload(sha256.New, "secret", signedBody), header.Get("X-Hub-Signature-256"))
+	assert.Equal(t, "push", header.Get("X-Gitea-Event"))
+}
+
+func TestHookRetryDelay(t *testing.T) {
+	defer func(backoff int) {
+		setting.Webhook.RetryBackoff = backoff
+	}(setting.Webhook.RetryBackoff)
+	setting.Webhook.RetryBackoff = 60
+
+	assert.Equal(t, time.Minute, hookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, hookRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, hookRetryDelay(3))
+	assert.Equal(t, 8*time.Minute, hookRetryDelay(4))
+	assert.Equal(t, maxHookRetryDelay, hookRetryDelay(20))
+	assert.Equal(t, maxHookRetryDelay, hookRetryDelay(1000))
+}
+
+func TestHookTask_deliverRetries(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(maxAttempts, deliverTimeout int) {
+		setting.Webhook.MaxAttempts = maxAttempts
+		setting.Webhook.DeliverTimeout = deliverTimeout
+	}(setting.Webhook.MaxAttempts, setting.Webhook.DeliverTimeout)
+	setting.Webhook.MaxAttempts = 3
+	setting.Webhook.DeliverTimeout = 5
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Reason", fmt.Sprintf("maintenance %d", requests))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(strings.Repeat("x", hookResponseBodyLimit+1)))
+	}))
+	defer server.Close()
+
+	hookTask := &HookTask{
+		RepoID:      1,
+		HookID:      1,
+		Type:        GITEA,
+		URL:         server.URL,
+		ContentType: ContentTypeJSON,
+		EventType:   HookEventPush,
+		Payloader:   &api.PushPayload{Ref: "refs/heads/master"},
+	}
+	assert.NoError(t, CreateHookTask(hookTask))
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		hookTask.deliver()
+
+		hookTask = AssertExistsAndLoadBean(t, &HookTask{ID: hookTask.ID}).(*HookTask)
+		assert.EqualValues(t, attempt, hookTask.Attempts)
+		assert.False(t, hookTask.IsSucceed)
+		assert.Equal(t, http.StatusServiceUnavailable, hookTask.ResponseInfo.Status)
+		assert.Equal(t, fmt.Sprintf("maintenance %d", attempt), hookTask.ResponseInfo.Headers["X-Reason"])
+		assert.Len(t, hookTask.ResponseInfo.Body, hookResponseBodyLimit)
+		if attempt < 3 {
+			assert.False(t, hookTask.IsDelivered)
+			assert.True(t, hookTask.NextDeliveryUnix > util.TimeStampNow())
+		} else {
+			// gives up after the last attempt
+			assert.True(t, hookTask.IsDelivered)
+		}
+	}
+
+	// every attempt keeps its own response
+	attempts, err := GetHookTaskAttempts([]int64{hookTask.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, attempts[hookTask.ID], 3) {
+		for i, attempt := range attempts[hookTask.ID] {
+			assert.Equal(t, i+1, attempt.Attempt)
+			assert.False(t, attempt.IsSucceed)
+			assert.Equal(t, http.StatusServiceUnavailable, attempt.ResponseInfo.Status)
+			assert.Equal(t, fmt.Sprintf("maintenance %d", i+1), attempt.ResponseInfo.Headers["X-Reason"])
+			assert.Equal(t, "push", attempt.RequestInfo.Headers["X-Gitea-Event"])
+		}
+	}
+}
+
+func TestRedeliverHookTask(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	hookTask, err := GetHookTaskByHookID(1, 1)
+	assert.NoError(t, err)
+	redelivery, err := RedeliverHookTask(hookTask)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hookTask.ID, redelivery.ID)
+	assert.NotEqual(t, hookTask.UUID, redelivery.UUID)
+	redelivery = AssertExistsAndLoadBean(t, &HookTask{ID: redelivery.ID, HookID: 1, RepoID: 1}).(*HookTask)
+	assert.Equal(t, hookTask.PayloadContent, redelivery.PayloadContent)
+	assert.False(t, redelivery.IsDelivered)
+	assert.Zero(t, redelivery.Attempts)
+
+	_, err = GetHookTaskByHookID(2, 1)
+	assert.True(t, IsErrHookTaskNotExist(err))
+}
 
 // TODO TestDeliverHooks
@@ -36,6 +36,7 @@ type AdminEditUserForm struct {
 	Website                 string `binding:"ValidUrl;MaxSize(255)"`
 	Location                string `binding:"MaxSize(50)"`
 	MaxRepoCreation         int
+	MaxRepoSize             int64
 	Active                  bool
 	Admin                   bool
 	AllowGitHook            bool
@@ -72,11 +72,59 @@ func SignedInID(ctx *macaron.Context, sess session.Store) int64 {
 	if uid == nil {
 		return 0
 	} else if id, ok := uid.(int64); ok {
+		if !checkUserSession(ctx, sess, id) {
+			return 0
+		}
 		return id
 	}
 	return 0
 }
 
+// checkUserSession records the session of the signed in user, or updates its
+// last seen time if it is recorded already. It returns false if the session
+// has been revoked, which signs it out.
+func checkUserSession(ctx *macaron.Context, sess session.Store, uid int64) bool {
+	ip, userAgent := ctx.RemoteAddr(), ctx.Req.UserAgent()
+	s, err := models.GetUserSessionBySessionID(sess.ID())
+	if err != nil {
+		if !models.IsErrUserSessionNotExist(err) {
+			log.Error(4, "GetUserSessionBySessionID: %v", err)
+		} else if err = models.CreateUserSession(&models.UserSession{
+			UID:       uid,
+			IP:        ip,
+			UserAgent: userAgent,
+		}, sess.ID()); err != nil {
+			log.Error(4, "CreateUserSession: %v", err)
+		}
+		return true
+	}
+
+	if s.IsRevoked || s.UID != uid {
+		log.Trace("Session of user %d has been revoked", uid)
+		SignOutSession(ctx, sess)
+		if err = models.DeleteUserSessionBySessionID(sess.ID()); err != nil {
+			log.Error(4, "DeleteUserSessionBySessionID: %v", err)
+		}
+		return false
+	}
+	if err = s.UpdateLastSeen(ip, userAgent); err != nil {
+		log.Error(4, "UpdateLastSeen: %v", err)
+	}
+	return true
+}
+
+// SignOutSession signs the user of the session out and clears the auto-login
+// cookies, so the session does not sign in again on its own.
+func SignOutSession(ctx *macaron.Context, sess session.Store) {
+	sess.Delete("uid")
+	sess.Delete("uname")
+	sess.Delete("socialId")
+	sess.Delete("socialName")
+	sess.Delete("socialEmail")
+	ctx.SetCookie(setting.CookieUserName, "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
+	ctx.SetCookie(setting.CookieRememberName, "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
+}
+
 // SignedInUser returns the user object of signed user.
 // It returns a bool value to indicate whether user uses basic auth or not.
 func SignedInUser(ctx *macaron.Context, sess session.Store) (*models.User, bool) {
@@ -49,6 +49,8 @@ type AuthenticationForm struct {
 	Oauth2AuthURL                 string
 	Oauth2ProfileURL              string
 	Oauth2EmailURL                string
+	Oauth2GroupClaimName          string
+	Oauth2GroupTeamMap            string
 }
 
 // Validate validates fields
@@ -0,0 +1,227 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/openidConnect"
+)
+
+// keysRefetchInterval is the minimum time between two fetches of the keys of
+// a provider, a token signed with an unknown key causing a new fetch in case
+// the provider rotated its keys.
+var keysRefetchInterval = time.Minute
+
+// openIDConnectProvider is an OpenID Connect provider verifying the signature
+// of the ID token before goth trusts its claims.
+type openIDConnectProvider struct {
+	*openidConnect.Provider
+	keys *jsonWebKeySet
+}
+
+// FetchUser verifies the ID token of the session and returns the user of its
+// claims.
+func (p *openIDConnectProvider) FetchUser(session goth.Session) (goth.User, error) {
+	if sess, ok := session.(*openidConnect.Session); ok && len(sess.IDToken) > 0 {
+		claims, err := p.keys.verify(sess.IDToken)
+		if err != nil {
+			return goth.User{}, fmt.Errorf("verify ID token of %s: %v", p.Name(), err)
+		}
+		if err = validateIDTokenClaims(claims, p.ClientKey); err != nil {
+			return goth.User{}, fmt.Errorf("validate ID token of %s: %v", p.Name(), err)
+		}
+	}
+	return p.Provider.FetchUser(session)
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set,
+// see https://tools.ietf.org/html/rfc7517 and https://tools.ietf.org/html/rfc7518#section-6
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// publicKey returns the RSA or ECDSA public key, or nil for other types of keys.
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+// jsonWebKeySet holds the signing keys of an OpenID Connect provider, fetched
+// from the jwks_uri of its discovery document when needed.
+type jsonWebKeySet struct {
+	discoveryURL string
+	client       *http.Client
+
+	lock      sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func newJSONWebKeySet(discoveryURL string, client *http.Client) *jsonWebKeySet {
+	return &jsonWebKeySet{
+		discoveryURL: discoveryURL,
+		client:       client,
+	}
+}
+
+func (s *jsonWebKeySet) getJSON(url string, v interface{}) error {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetch replaces the keys by the signing keys currently published by the
+// provider, the keys of the provider being rotated regularly.
+func (s *jsonWebKeySet) fetch() error {
+	s.fetchedAt = time.Now()
+
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := s.getJSON(s.discoveryURL, &discovery); err != nil {
+		return err
+	}
+	if len(discovery.JWKSURI) == 0 {
+		return fmt.Errorf("discovery document has no jwks_uri")
+	}
+
+	var set struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+	if err := s.getJSON(discovery.JWKSURI, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return fmt.Errorf("invalid key %s: %v", k.KeyID, err)
+		}
+		if key != nil {
+			keys[k.KeyID] = key
+		}
+	}
+	s.keys = keys
+	return nil
+}
+
+// key returns the key with the given ID. The only key of the provider is
+// returned for a token without key ID.
+func (s *jsonWebKeySet) key(keyID string) (interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	find := func() interface{} {
+		if len(keyID) == 0 && len(s.keys) == 1 {
+			for _, key := range s.keys {
+				return key
+			}
+		}
+		return s.keys[keyID]
+	}
+
+	if key := find(); key != nil {
+		return key, nil
+	}
+	if s.keys == nil || time.Since(s.fetchedAt) >= keysRefetchInterval {
+		if err := s.fetch(); err != nil {
+			return nil, fmt.Errorf("fetch keys: %v", err)
+		}
+		if key := find(); key != nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", keyID)
+}
+
+// verify checks the signature of the given ID token and returns its claims,
+// which are left to validate. Tokens signed with the client secret (HMAC) or
+// not at all are refused.
+func (s *jsonWebKeySet) verify(idToken string) (map[string]interface{}, error) {
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unsupported signing algorithm %s", token.Method.Alg())
+		}
+		keyID, _ := token.Header["kid"].(string)
+		return s.key(keyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(jwt.MapClaims), nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// testOpenIDConnectServer is an OpenID Connect provider publishing the public
+// keys of its signing keys, and issuing the ID token of its refresh tokens.
+type testOpenIDConnectServer struct {
+	*httptest.Server
+	keys      map[string]interface{}
+	published []string
+	fetches   int
+	// tokenKeyID is the ID of the key signing the ID tokens of the token endpoint
+	tokenKeyID string
+}
+
+func newTestOpenIDConnectServer(t *testing.T) *testOpenIDConnectServer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	s := &testOpenIDConnectServer{
+		keys:       map[string]interface{}{"rsa": rsaKey, "ec": ecKey},
+		published:  []string{"rsa", "ec"},
+		tokenKeyID: "rsa",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.fetches++
+		encode := func(i *big.Int) string {
+			return base64.RawURLEncoding.EncodeToString(i.Bytes())
+		}
+		keys := make([]map[string]string, 0, len(s.published))
+		for _, kid := range s.published {
+			switch key := s.keys[kid].(type) {
+			case *rsa.PrivateKey:
+				keys = append(keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig",
+					"n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))})
+			case *ecdsa.PrivateKey:
+				keys = append(keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256",
+					"x": encode(key.X), "y": encode(key.Y)})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "new-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     s.sign(t, jwt.SigningMethodRS256, s.tokenKeyID, s.claims()),
+		})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *testOpenIDConnectServer) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    s.URL,
+		"aud":    "client",
+		"sub":    "user",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+		"groups": []string{"developers"},
+	}
+}
+
+func (s *testOpenIDConnectServer) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(s.keys[kid])
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestJSONWebKeySet_Verify(t *testing.T) {
+	s := newTestOpenIDConnectServer(t)
+	defer s.Close()
+	keys := newJSONWebKeySet(s.URL+"/.well-known/openid-configuration", http.DefaultClient)
+
+	claims, err := keys.verify(s.sign(t, jwt.SigningMethodRS256, "rsa", s.claims()))
+	assert.NoError(t, err)
+	assert.Equal(t, "user", claims["sub"])
+	_, err = keys.verify(s.sign(t, jwt.SigningMethodES256, "ec", s.claims()))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.fetches)
+
+	// a tampered token
+	token := s.sign(t, jwt.SigningMethodRS256, "rsa", s.claims())
+	other := s.sign(t, jwt.SigningMethodRS256, "rsa", jwt.MapClaims{"sub": "admin"})
+	_, err = keys.verify(token[:len(token)-10] + other[len(other)-10:])
+	assert.Error(t, err)
+
+	// tokens signed with the client secret or not signed at all
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, s.claims()).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	_, err = keys.verify(hmacToken)
+	assert.Error(t, err)
+	noneToken, err := jwt.NewWithClaims(jwt.SigningMethodNone, s.claims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	_, err = keys.verify(noneToken)
+	assert.Error(t, err)
+
+	// a key unknown to the provider doesn't fetch the keys again right away
+	s.keys["other"] = s.keys["rsa"]
+	_, err = keys.verify(s.sign(t, jwt.SigningMethodRS256, "other", s.claims()))
+	assert.Error(t, err)
+	assert.Equal(t, 1, s.fetches)
+
+	// a key the provider rotated in is fetched
+	defer func(d time.Duration) { keysRefetchInterval = d }(keysRefetchInterval)
+	keysRefetchInterval = 0
+	s.published = []string{"other"}
+	_, err = keys.verify(s.sign(t, jwt.SigningMethodRS256, "other", s.claims()))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.fetches)
+
+	// the only key is used for a token without key ID
+	noKeyID := jwt.NewWithClaims(jwt.SigningMethodRS256, s.claims())
+	token, err = noKeyID.SignedString(s.keys["other"])
+	assert.NoError(t, err)
+	_, err = keys.verify(token)
+	assert.NoError(t, err)
+}
+
+func TestRefreshUser(t *testing.T) {
+	s := newTestOpenIDConnectServer(t)
+	defer s.Close()
+	assert.NoError(t, RegisterProvider("oidc", "openidConnect", "client", "secret", s.URL+"/.well-known/openid-configuration", nil))
+	defer RemoveProvider("oidc")
+
+	user, err := RefreshUser("oidc", "refresh-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "oidc", user.Provider)
+	assert.Equal(t, "user", user.UserID)
+	assert.Equal(t, "new-access-token", user.AccessToken)
+	// the provider didn't issue a new refresh token
+	assert.Equal(t, "refresh-token", user.RefreshToken)
+	assert.Equal(t, []interface{}{"developers"}, user.RawData["groups"])
+
+	// the ID token is refused when signed with a key the provider doesn't publish
+	s.keys["unpublished"] = s.keys["rsa"]
+	s.tokenKeyID = "unpublished"
+	_, err = RefreshUser("oidc", "refresh-token")
+	assert.Error(t, err)
+}
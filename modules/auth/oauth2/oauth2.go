@@ -5,8 +5,10 @@
 package oauth2
 
 import (
+	"fmt"
 	"math"
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -109,6 +111,79 @@ func RegisterProvider(providerName, providerType, clientID, clientSecret, openID
 	return err
 }
 
+// HasProvider returns true if the given OAuth2 provider is registered in the goth lib
+func HasProvider(providerName string) bool {
+	_, err := goth.GetProvider(providerName)
+	return err == nil
+}
+
+// RefreshUser uses the refresh token to get new tokens from the given OAuth2
+// provider. The user also holds the claims of the new ID token of an OpenID
+// Connect provider, verified like on sign in, when the provider issued one.
+func RefreshUser(providerName, refreshToken string) (goth.User, error) {
+	provider, err := goth.GetProvider(providerName)
+	if err != nil {
+		return goth.User{}, err
+	}
+	if !provider.RefreshTokenAvailable() {
+		return goth.User{}, fmt.Errorf("provider %s does not support refreshing tokens", providerName)
+	}
+	token, err := provider.RefreshToken(refreshToken)
+	if err != nil {
+		return goth.User{}, err
+	}
+
+	// providers may keep the refresh token valid without issuing a new one
+	if len(token.RefreshToken) == 0 {
+		token.RefreshToken = refreshToken
+	}
+	if idToken, _ := token.Extra("id_token").(string); len(idToken) > 0 {
+		if _, ok := provider.(*openIDConnectProvider); ok {
+			return provider.FetchUser(&openidConnect.Session{
+				AccessToken:  token.AccessToken,
+				RefreshToken: token.RefreshToken,
+				ExpiresAt:    token.Expiry,
+				IDToken:      idToken,
+			})
+		}
+	}
+	return goth.User{
+		Provider:     providerName,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.Expiry,
+	}, nil
+}
+
+// clockSkew is the difference tolerated between the clocks of Gitea and of an
+// OpenID Connect provider.
+const clockSkew = 10 * time.Second
+
+// validateIDTokenClaims completes the validation of the claims of the ID token
+// of an OpenID Connect provider, goth validating its audience, issuer and
+// expiry.
+// See https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+func validateIDTokenClaims(claims map[string]interface{}, clientID string) error {
+	if subject, _ := claims["sub"].(string); len(subject) == 0 {
+		return fmt.Errorf("ID token has no subject")
+	}
+
+	// the authorized party is the client the token is issued for, which is
+	// required when the token has several audiences
+	authorizedParty, _ := claims["azp"].(string)
+	if audiences, ok := claims["aud"].([]interface{}); ok && len(audiences) > 1 && len(authorizedParty) == 0 {
+		return fmt.Errorf("ID token has several audiences but no authorized party")
+	}
+	if len(authorizedParty) > 0 && authorizedParty != clientID {
+		return fmt.Errorf("ID token is issued for another client: %s", authorizedParty)
+	}
+
+	if issuedAt, ok := claims["iat"].(float64); ok && time.Unix(int64(issuedAt), 0).After(time.Now().Add(clockSkew)) {
+		return fmt.Errorf("ID token is issued in the future")
+	}
+	return nil
+}
+
 // RemoveProvider removes the given OAuth2 provider from the goth lib
 func RemoveProvider(providerName string) {
 	delete(goth.GetProviders(), providerName)
@@ -167,8 +242,16 @@ func createProvider(providerName, providerType, clientID, clientSecret, openIDCo
 	case "gplus":
 		provider = gplus.New(clientID, clientSecret, callbackURL, "email")
 	case "openidConnect":
-		if provider, err = openidConnect.New(clientID, clientSecret, callbackURL, openIDConnectAutoDiscoveryURL); err != nil {
+		var oidcProvider *openidConnect.Provider
+		if oidcProvider, err = openidConnect.New(clientID, clientSecret, callbackURL, openIDConnectAutoDiscoveryURL, "openid", "profile", "email"); err != nil {
 			log.Warn("Failed to create OpenID Connect Provider with name '%s' with url '%s': %v", providerName, openIDConnectAutoDiscoveryURL, err)
+		} else {
+			// the username suggested by the provider is a better match for a Gitea login name than the nickname
+			oidcProvider.NickNameClaims = []string{openidConnect.PreferredUsernameClaim, openidConnect.NicknameClaim}
+			provider = &openIDConnectProvider{
+				Provider: oidcProvider,
+				keys:     newJSONWebKeySet(openIDConnectAutoDiscoveryURL, oidcProvider.Client()),
+			}
 		}
 	case "twitter":
 		provider = twitter.NewAuthenticate(clientID, clientSecret, callbackURL)
@@ -0,0 +1,34 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIDTokenClaims(t *testing.T) {
+	now := float64(time.Now().Unix())
+	for _, claims := range []map[string]interface{}{
+		{"sub": "user", "aud": "client", "iat": now},
+		{"sub": "user", "aud": []interface{}{"client"}},
+		{"sub": "user", "aud": []interface{}{"client", "other"}, "azp": "client"},
+	} {
+		assert.NoError(t, validateIDTokenClaims(claims, "client"), "%v", claims)
+	}
+
+	for _, claims := range []map[string]interface{}{
+		{"aud": "client"},
+		{"sub": "", "aud": "client"},
+		{"sub": "user", "aud": []interface{}{"client", "other"}},
+		{"sub": "user", "aud": []interface{}{"client", "other"}, "azp": "other"},
+		{"sub": "user", "aud": "client", "azp": "other"},
+		{"sub": "user", "aud": "client", "iat": now + 3600},
+	} {
+		assert.Error(t, validateIDTokenClaims(claims, "client"), "%v", claims)
+	}
+}
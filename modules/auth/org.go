@@ -30,12 +30,26 @@ func (f *CreateOrgForm) Validate(ctx *macaron.Context, errs binding.Errors) bind
 
 // UpdateOrgSettingForm form for updating organization settings
 type UpdateOrgSettingForm struct {
-	Name            string `binding:"Required;AlphaDashDot;MaxSize(35)" locale:"org.org_name_holder"`
-	FullName        string `binding:"MaxSize(100)"`
-	Description     string `binding:"MaxSize(255)"`
-	Website         string `binding:"ValidUrl;MaxSize(255)"`
-	Location        string `binding:"MaxSize(50)"`
-	MaxRepoCreation int
+	Name             string `binding:"Required;AlphaDashDot;MaxSize(35)" locale:"org.org_name_holder"`
+	FullName         string `binding:"MaxSize(100)"`
+	Description      string `binding:"MaxSize(255)"`
+	Website          string `binding:"ValidUrl;MaxSize(255)"`
+	Location         string `binding:"MaxSize(50)"`
+	MaxRepoCreation  int
+	MaxRepoSize      int64
+	RequireTwoFactor bool
+	RepoUnits        []models.UnitType
+}
+
+// IsRepoUnitEnabled returns true if the unit is enabled by default on the new
+// repositories of the organization.
+func (f *UpdateOrgSettingForm) IsRepoUnitEnabled(tp models.UnitType) bool {
+	for _, enabled := range f.RepoUnits {
+		if enabled == tp {
+			return true
+		}
+	}
+	return false
 }
 
 // Validate validates the fields
@@ -54,6 +54,10 @@ type MigrateRepoForm struct {
 	Mirror      bool   `json:"mirror"`
 	Private     bool   `json:"private"`
 	Description string `json:"description" binding:"MaxSize(255)"`
+	// import the issues of a github.com repository
+	Issues bool `json:"issues"`
+	// token of the GitHub API used to import the issues
+	AuthToken string `json:"auth_token"`
 }
 
 // Validate validates the fields
@@ -114,9 +118,12 @@ type RepoSettingForm struct {
 	PullsAllowRebase                 bool
 	PullsAllowRebaseMerge            bool
 	PullsAllowSquash                 bool
+	PullsSquashUseCommitAuthor       bool
+	PullsMergeMessageTemplate        string
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableIssueDependencies          bool
+	CloseIssuesBranches              string
 
 	// Admin settings
 	EnableHealthCheck bool
@@ -146,6 +153,15 @@ type ProtectBranchForm struct {
 	RequiredApprovals       int64
 	ApprovalsWhitelistUsers string
 	ApprovalsWhitelistTeams string
+	ApprovalsTeamID         int64
+	ApprovalsTeamWeight     int64
+	RequireTeamApproval     bool
+	EnableStatusCheck       bool
+	StatusCheckContexts     string
+
+	ProtectedFilePatterns        string
+	ProtectedFilesWhitelistUsers string
+	ProtectedFilesWhitelistTeams string
 }
 
 // Validate validates the fields
@@ -153,6 +169,18 @@ func (f *ProtectBranchForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ProtectTagForm form for changing protected tag settings
+type ProtectTagForm struct {
+	NamePattern    string `binding:"Required"`
+	WhitelistUsers string
+	WhitelistTeams string
+}
+
+// Validate validates the fields
+func (f *ProtectTagForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //  __      __      ___.   .__    .__            __
 // /  \    /  \ ____\_ |__ |  |__ |  |__   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \|  |  \ /  _ \|  |/ /
@@ -173,6 +201,8 @@ type WebhookForm struct {
 	PullRequest  bool
 	Repository   bool
 	Active       bool
+	BranchFilter string
+	PathFilter   string
 }
 
 // PushOnly if the hook will be triggered when push
@@ -277,6 +307,7 @@ type CreateIssueForm struct {
 	AssigneeID  int64
 	Content     string
 	Files       []string
+	Draft       bool `form:"draft"`
 }
 
 // Validate validates the fields
@@ -284,6 +315,16 @@ func (f *CreateIssueForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// MoveIssueForm form for moving an issue to another repository
+type MoveIssueForm struct {
+	TargetRepo string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *MoveIssueForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // CreateCommentForm form for creating comment
 type CreateCommentForm struct {
 	Content string
@@ -318,6 +359,7 @@ type CreateMilestoneForm struct {
 	Title    string `binding:"Required;MaxSize(50)"`
 	Content  string
 	Deadline string
+	Weighted bool
 }
 
 // Validate validates the fields
@@ -345,6 +387,22 @@ func (f *CreateLabelForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// BulkIssueLabelsForm form for adding and removing labels of several issues
+type BulkIssueLabelsForm struct {
+	// list of issue indexes
+	// required: true
+	Issues []int64 `json:"issues" binding:"Required"`
+	// list of label IDs to add
+	Add []int64 `json:"add"`
+	// list of label IDs to remove
+	Remove []int64 `json:"remove"`
+}
+
+// Validate validates the fields
+func (f *BulkIssueLabelsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // InitializeLabelsForm form for initializing labels
 type InitializeLabelsForm struct {
 	TemplateName string `binding:"Required"`
@@ -189,6 +189,20 @@ func (f *AddEmailForm) Validate(ctx *macaron.Context, errs binding.Errors) bindi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// EmailNotificationsForm form for changing the email notification preferences
+type EmailNotificationsForm struct {
+	Watching        bool
+	OwnThread       bool
+	Mention         bool
+	Assigned        bool
+	ReviewRequested bool
+}
+
+// Validate validates the fields
+func (f *EmailNotificationsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ChangePasswordForm form for changing password
 type ChangePasswordForm struct {
 	OldPassword string `form:"old_password" binding:"MaxSize(255)"`
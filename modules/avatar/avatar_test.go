@@ -5,6 +5,7 @@
 package avatar
 
 import (
+	"image/color"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,3 +18,43 @@ func Test_RandomImage(t *testing.T) {
 	_, err = RandomImageSize(0, []byte("gogs@local"))
 	assert.Error(t, err)
 }
+
+func Test_Initials(t *testing.T) {
+	assert.Equal(t, "JT", Initials("John Ronald Reuel Tolkien"))
+	assert.Equal(t, "U", Initials("user2"))
+	assert.Equal(t, "EZ", Initials("  Élodie   zoë "))
+	assert.Equal(t, "JD", Initials("(John) Doe-Smith"))
+	// there are no glyphs for these initials
+	assert.Equal(t, "S", Initials("Иван Smith"))
+	assert.Equal(t, "", Initials("李小龙"))
+	assert.Equal(t, "", Initials(""))
+}
+
+func Test_InitialsImage(t *testing.T) {
+	img, err := InitialsImage("John Doe", []byte("user2"))
+	assert.NoError(t, err)
+	assert.Equal(t, AvatarSize, img.Bounds().Dx())
+
+	// the background color only depends on the seed
+	other, err := InitialsImage("Jane Roe", []byte("user2"))
+	assert.NoError(t, err)
+	assert.Equal(t, img.At(0, 0), other.At(0, 0))
+
+	// the initials are drawn in white
+	white := 0
+	for y := 0; y < AvatarSize; y++ {
+		for x := 0; x < AvatarSize; x++ {
+			if img.At(x, y) == (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+				white++
+			}
+		}
+	}
+	assert.NotZero(t, white)
+
+	// names without initials fall back to random images
+	_, err = InitialsImage("", []byte("user2"))
+	assert.NoError(t, err)
+
+	_, err = InitialsImageSize(10, "John Doe", []byte("user2"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package avatar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is a 5x7 pixel font of the letters and digits initials are drawn with.
+var glyphs = map[rune][glyphHeight]string{
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+}
+
+// backgroundColors are the background colors of initials avatars.
+var backgroundColors = []color.RGBA{
+	{0xdb, 0x28, 0x28, 0xff},
+	{0xf2, 0x71, 0x1c, 0xff},
+	{0xb5, 0x8d, 0x05, 0xff},
+	{0x6c, 0x9a, 0x13, 0xff},
+	{0x21, 0xba, 0x45, 0xff},
+	{0x00, 0xa1, 0x8f, 0xff},
+	{0x21, 0x85, 0xd0, 0xff},
+	{0x4c, 0x50, 0xb8, 0xff},
+	{0x64, 0x35, 0xc9, 0xff},
+	{0xa3, 0x33, 0xc8, 0xff},
+	{0xe0, 0x39, 0x97, 0xff},
+	{0xa5, 0x67, 0x3f, 0xff},
+	{0x5b, 0x6b, 0x7a, 0xff},
+}
+
+// initial returns the first letter or digit of word which can be drawn,
+// accented letters are drawn without their accents.
+func initial(word string) (rune, bool) {
+	for _, r := range norm.NFKD.String(word) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		r = unicode.ToUpper(r)
+		_, ok := glyphs[r]
+		return r, ok
+	}
+	return 0, false
+}
+
+// Initials returns the initials of the first and the last word of name which
+// can be drawn, that is up to two uppercase letters or digits.
+func Initials(name string) string {
+	var initials []rune
+	words := strings.Fields(name)
+	first := len(words)
+	for i := range words {
+		if r, ok := initial(words[i]); ok {
+			initials = append(initials, r)
+			first = i
+			break
+		}
+	}
+	for i := len(words) - 1; i > first; i-- {
+		if r, ok := initial(words[i]); ok {
+			initials = append(initials, r)
+			break
+		}
+	}
+	return string(initials)
+}
+
+// InitialsImageSize generates and returns an avatar image showing the initials
+// of name on a background color unique to seed in custom size (height and width).
+// If name has no initials that can be drawn, a random avatar image unique to seed
+// is returned instead.
+func InitialsImageSize(size int, name string, seed []byte) (image.Image, error) {
+	initials := []rune(Initials(name))
+	if len(initials) == 0 {
+		return RandomImageSize(size, seed)
+	}
+
+	// every pixel of a glyph is a square of scale pixels, and the initials
+	// take half of the width of the image
+	columns := len(initials)*(glyphWidth+1) - 1
+	scale := size / 2 / (2*(glyphWidth+1) - 1)
+	if scale < 1 {
+		return nil, fmt.Errorf("avatar size %d is too small", size)
+	}
+
+	hash := fnv.New32a()
+	hash.Write(seed)
+	background := backgroundColors[hash.Sum32()%uint32(len(backgroundColors))]
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.ZP, draw.Src)
+
+	left := (size - columns*scale) / 2
+	top := (size - glyphHeight*scale) / 2
+	for i, r := range initials {
+		glyph := glyphs[r]
+		for y, row := range glyph {
+			for x, pixel := range row {
+				if pixel != '#' {
+					continue
+				}
+				x0 := left + (i*(glyphWidth+1)+x)*scale
+				y0 := top + y*scale
+				draw.Draw(img, image.Rect(x0, y0, x0+scale, y0+scale), image.White, image.ZP, draw.Src)
+			}
+		}
+	}
+	return img, nil
+}
+
+// InitialsImage generates and returns an avatar image showing the initials
+// of name in default size (height and width).
+func InitialsImage(name string, seed []byte) (image.Image, error) {
+	return InitialsImageSize(AvatarSize, name, seed)
+}
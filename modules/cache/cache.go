@@ -30,6 +30,29 @@ func NewContext() error {
 	return err
 }
 
+// GetString returns key value from cache with callback when no key exists in cache
+func GetString(key string, getFunc func() (string, error)) (string, error) {
+	if conn == nil || setting.CacheService.TTL == 0 {
+		return getFunc()
+	}
+	if !conn.IsExist(key) {
+		var (
+			value string
+			err   error
+		)
+		if value, err = getFunc(); err != nil {
+			return value, err
+		}
+		conn.Put(key, value, int64(setting.CacheService.TTL.Seconds()))
+	}
+	switch value := conn.Get(key).(type) {
+	case string:
+		return value, nil
+	default:
+		return "", fmt.Errorf("Unsupported cached value type: %v", value)
+	}
+}
+
 // GetInt returns key value from cache with callback when no key exists in cache
 func GetInt(key string, getFunc func() (int, error)) (int, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
@@ -214,6 +214,15 @@ func repoAssignment(ctx *Context, repo *models.Repository) {
 			EarlyResponseForGoGetMeta(ctx)
 			return
 		}
+		required, err := models.IsTwoFactorRequired(repo.Owner, ctx.User)
+		if err != nil {
+			ctx.ServerError("IsTwoFactorRequired", err)
+			return
+		} else if required {
+			ctx.Flash.Error(ctx.Tr("org.two_factor_required", repo.Owner.Name))
+			ctx.Redirect(setting.AppSubURL + "/user/settings/security")
+			return
+		}
 		ctx.NotFound("no access right", nil)
 		return
 	}
@@ -88,6 +88,17 @@ func NewContext() {
 			go models.RemoveOldDeletedBranches()
 		}
 	}
+	if setting.Cron.ReleaseExpiredLFSLocks.Enabled {
+		entry, err = c.AddFunc("Release expired LFS locks", setting.Cron.ReleaseExpiredLFSLocks.Schedule, models.ReleaseExpiredLFSLocks)
+		if err != nil {
+			log.Fatal(4, "Cron[Release expired LFS locks]: %v", err)
+		}
+		if setting.Cron.ReleaseExpiredLFSLocks.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go models.ReleaseExpiredLFSLocks()
+		}
+	}
 	c.Start()
 }
 
@@ -0,0 +1,126 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Field is a field an issue template asks the issue author to fill in.
+type Field struct {
+	Name        string `yaml:"name"`
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
+// Title returns the heading of the field in the issue body.
+func (f *Field) Title() string {
+	if len(f.Label) > 0 {
+		return f.Label
+	}
+	return f.Name
+}
+
+// FormName returns the name of the form value holding the field.
+func (f *Field) FormName() string {
+	return "field_" + f.Name
+}
+
+// Template is an issue template defined in YAML.
+type Template struct {
+	Name   string   `yaml:"name"`
+	About  string   `yaml:"about"`
+	Body   string   `yaml:"body"`
+	Fields []*Field `yaml:"fields"`
+}
+
+// Parse parses the YAML content of an issue template.
+func Parse(content []byte) (*Template, error) {
+	t := new(Template)
+	if err := yaml.Unmarshal(content, t); err != nil {
+		return nil, err
+	}
+	for i, field := range t.Fields {
+		if len(strings.TrimSpace(field.Name)) == 0 {
+			return nil, fmt.Errorf("field %d has no name", i+1)
+		}
+	}
+	return t, nil
+}
+
+// Content returns the issue body the template starts with: its body followed
+// by an empty section for every field.
+func (t *Template) Content() string {
+	body := strings.TrimSpace(t.Body)
+	if len(body) == 0 {
+		return t.RenderFields(nil)
+	}
+	return body + "\n\n" + t.RenderFields(nil)
+}
+
+// RenderFields returns a section for every field, filled in with values
+// indexed by field name.
+func (t *Template) RenderFields(values map[string]string) string {
+	var buf bytes.Buffer
+	for _, field := range t.Fields {
+		buf.WriteString("### ")
+		buf.WriteString(field.Title())
+		buf.WriteString("\n\n")
+		if value := strings.TrimSpace(values[field.Name]); len(value) > 0 {
+			buf.WriteString(value)
+			buf.WriteString("\n\n")
+		} else if len(field.Description) > 0 {
+			buf.WriteString("<!-- ")
+			buf.WriteString(field.Description)
+			buf.WriteString(" -->\n\n")
+		}
+	}
+	return buf.String()
+}
+
+var (
+	sectionPattern = regexp.MustCompile(`(?m)^###[ \t]+(.+?)[ \t]*$`)
+	commentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// parseSections returns the content of the "###" sections of body by title.
+func parseSections(body string) map[string]string {
+	sections := make(map[string]string)
+	matches := sectionPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, match := range matches {
+		end := len(body)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		title := body[match[2]:match[3]]
+		if _, ok := sections[title]; !ok {
+			sections[title] = body[match[1]:end]
+		}
+	}
+	return sections
+}
+
+// MissingFields returns the required fields which are left empty in body.
+// Placeholder comments do not count as content.
+func (t *Template) MissingFields(body string) []*Field {
+	sections := parseSections(body)
+	missing := make([]*Field, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		if !field.Required {
+			continue
+		}
+		content := commentPattern.ReplaceAllString(sections[field.Title()], "")
+		if len(strings.TrimSpace(content)) == 0 {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
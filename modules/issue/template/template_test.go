@@ -0,0 +1,78 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTemplate = `
+name: Bug report
+about: Something does not work
+body: |
+  Thanks for taking the time to report a bug!
+fields:
+  - name: version
+    label: Gitea version
+    description: The output of gitea --version
+    required: true
+  - name: logs
+    label: Logs
+`
+
+func TestParse(t *testing.T) {
+	tmpl, err := Parse([]byte(testTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bug report", tmpl.Name)
+	assert.Len(t, tmpl.Fields, 2)
+	assert.Equal(t, "Gitea version", tmpl.Fields[0].Title())
+	assert.Equal(t, "field_version", tmpl.Fields[0].FormName())
+	assert.True(t, tmpl.Fields[0].Required)
+	assert.Equal(t, "logs", tmpl.Fields[1].Name)
+	assert.False(t, tmpl.Fields[1].Required)
+
+	_, err = Parse([]byte("fields:\n  - label: No name\n"))
+	assert.Error(t, err)
+}
+
+func TestTemplate_Content(t *testing.T) {
+	tmpl, err := Parse([]byte(testTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, `Thanks for taking the time to report a bug!
+
+### Gitea version
+
+<!-- The output of gitea --version -->
+
+### Logs
+
+`, tmpl.Content())
+}
+
+func TestTemplate_MissingFields(t *testing.T) {
+	tmpl, err := Parse([]byte(testTemplate))
+	assert.NoError(t, err)
+
+	// Missing required field
+	missing := tmpl.MissingFields(tmpl.Content())
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "version", missing[0].Name)
+	missing = tmpl.MissingFields("I did not use the template")
+	assert.Len(t, missing, 1)
+	missing = tmpl.MissingFields(tmpl.RenderFields(map[string]string{"logs": "panic"}))
+	assert.Len(t, missing, 1)
+
+	// Filled required field
+	assert.Empty(t, tmpl.MissingFields(tmpl.RenderFields(map[string]string{"version": "1.7.0"})))
+	assert.Empty(t, tmpl.MissingFields("### Gitea version\n\n<!-- The output of gitea --version -->\n1.7.0\n### Logs\n"))
+
+	// No required fields
+	tmpl, err = Parse([]byte("name: Question\nfields:\n  - name: question\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, tmpl.MissingFields(""))
+	assert.Empty(t, tmpl.MissingFields(tmpl.Content()))
+}
@@ -24,7 +24,7 @@ type ContentStore struct {
 // Get takes a Meta object and retrieves the content from the store, returning
 // it as an io.Reader. If fromByte > 0, the reader starts from that byte
 func (s *ContentStore) Get(meta *models.LFSMetaObject, fromByte int64) (io.ReadCloser, error) {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
+	path := filepath.Join(s.BasePath, meta.RelativePath())
 
 	f, err := os.Open(path)
 	if err != nil {
@@ -38,7 +38,7 @@ func (s *ContentStore) Get(meta *models.LFSMetaObject, fromByte int64) (io.ReadC
 
 // Put takes a Meta object and an io.Reader and writes the content to the store.
 func (s *ContentStore) Put(meta *models.LFSMetaObject, r io.Reader) error {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
+	path := filepath.Join(s.BasePath, meta.RelativePath())
 	tmpPath := path + ".tmp"
 
 	dir := filepath.Dir(path)
@@ -76,7 +76,7 @@ func (s *ContentStore) Put(meta *models.LFSMetaObject, r io.Reader) error {
 
 // Exists returns true if the object exists in the content store.
 func (s *ContentStore) Exists(meta *models.LFSMetaObject) bool {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
+	path := filepath.Join(s.BasePath, meta.RelativePath())
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return false
 	}
@@ -85,7 +85,7 @@ func (s *ContentStore) Exists(meta *models.LFSMetaObject) bool {
 
 // Verify returns true if the object exists in the content store and size is correct.
 func (s *ContentStore) Verify(meta *models.LFSMetaObject) (bool, error) {
-	path := filepath.Join(s.BasePath, transformKey(meta.Oid))
+	path := filepath.Join(s.BasePath, meta.RelativePath())
 
 	fi, err := os.Stat(path)
 	if os.IsNotExist(err) || err == nil && fi.Size() != meta.Size {
@@ -96,11 +96,3 @@ func (s *ContentStore) Verify(meta *models.LFSMetaObject) (bool, error) {
 
 	return true, nil
 }
-
-func transformKey(key string) string {
-	if len(key) < 5 {
-		return key
-	}
-
-	return filepath.Join(key[0:2], key[2:4], key[4:])
-}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	api "code.gitea.io/sdk/gitea"
 )
 
@@ -128,6 +129,13 @@ func GetListLockHandler(ctx *context.Context) {
 	})
 }
 
+// lockRequest is an api.LFSLockRequest which may ask for the lock to expire.
+type lockRequest struct {
+	api.LFSLockRequest
+	// TTL is the number of seconds after which the lock expires, zero for never.
+	TTL int64 `json:"ttl,omitempty"`
+}
+
 // PostLockHandler create lock
 func PostLockHandler(ctx *context.Context) {
 	if !checkIsValidRequest(ctx, false) {
@@ -135,18 +143,24 @@ func PostLockHandler(ctx *context.Context) {
 	}
 	ctx.Resp.Header().Set("Content-Type", metaMediaType)
 
-	var req api.LFSLockRequest
+	var req lockRequest
 	dec := json.NewDecoder(ctx.Req.Body().ReadCloser())
 	err := dec.Decode(&req)
-	if err != nil {
+	if err != nil || req.TTL < 0 {
 		writeStatus(ctx, 400)
 		return
 	}
 
+	var expires util.TimeStamp
+	if req.TTL > 0 {
+		expires = util.TimeStampNow().Add(req.TTL)
+	}
+
 	lock, err := models.CreateLFSLock(&models.LFSLock{
-		Repo:  ctx.Repo.Repository,
-		Path:  req.Path,
-		Owner: ctx.User,
+		Repo:        ctx.Repo.Repository,
+		Path:        req.Path,
+		Owner:       ctx.User,
+		ExpiresUnix: expires,
 	})
 	if err != nil {
 		if models.IsErrLFSLockAlreadyExist(err) {
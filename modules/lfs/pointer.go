@@ -0,0 +1,45 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+)
+
+// PointerSizeCutoff is the maximum size of an LFS pointer file.
+const PointerSizeCutoff = 1024
+
+var oidPattern = regexp.MustCompile("^[a-f0-9]{64}$")
+
+// IsPointerFile returns the LFS meta object referenced by buf if buf is the
+// content of an LFS pointer file, or nil otherwise.
+func IsPointerFile(buf []byte) *models.LFSMetaObject {
+	content := string(buf)
+	if len(content) > PointerSizeCutoff || !strings.HasPrefix(content, models.LFSMetaFileIdentifier+"\n") {
+		return nil
+	}
+
+	meta := &models.LFSMetaObject{Size: -1}
+	for _, line := range strings.Split(content, "\n")[1:] {
+		switch {
+		case strings.HasPrefix(line, models.LFSMetaFileOidPrefix):
+			meta.Oid = strings.TrimPrefix(line, models.LFSMetaFileOidPrefix)
+		case strings.HasPrefix(line, "size "):
+			size, err := strconv.ParseInt(strings.TrimPrefix(line, "size "), 10, 64)
+			if err != nil {
+				return nil
+			}
+			meta.Size = size
+		}
+	}
+	if !oidPattern.MatchString(meta.Oid) || meta.Size < 0 {
+		return nil
+	}
+	return meta
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -235,6 +236,17 @@ func PostHandler(ctx *context.Context) {
 		return
 	}
 
+	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	if err := checkSizeLimit(repository, contentStore, rv.Oid, rv.Size); err != nil {
+		if models.IsErrRepoSizeLimitExceeded(err) {
+			writeSizeLimitExceeded(ctx, err.(models.ErrRepoSizeLimitExceeded))
+		} else {
+			log.Error(4, "checkSizeLimit: %v", err)
+			writeStatus(ctx, 500)
+		}
+		return
+	}
+
 	meta, err := models.NewLFSMetaObject(&models.LFSMetaObject{Oid: rv.Oid, Size: rv.Size, RepositoryID: repository.ID})
 	if err != nil {
 		writeStatus(ctx, 404)
@@ -244,7 +256,6 @@ func PostHandler(ctx *context.Context) {
 	ctx.Resp.Header().Set("Content-Type", metaMediaType)
 
 	sentStatus := 202
-	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
 	if meta.Existing && contentStore.Exists(meta) {
 		sentStatus = 200
 	}
@@ -304,6 +315,20 @@ func BatchHandler(ctx *context.Context) {
 		}
 
 		// Object is not found
+		if bv.Operation == "upload" {
+			if err = checkSizeLimit(repository, contentStore, object.Oid, object.Size); models.IsErrRepoSizeLimitExceeded(err) {
+				responseObjects = append(responseObjects, &Representation{
+					Oid:   object.Oid,
+					Size:  object.Size,
+					Error: &ObjectError{Code: http.StatusRequestEntityTooLarge, Message: sizeLimitExceededMessage(err.(models.ErrRepoSizeLimitExceeded))},
+				})
+				continue
+			} else if err != nil {
+				log.Error(4, "checkSizeLimit: %v", err)
+				writeStatus(ctx, 500)
+				return
+			}
+		}
 		meta, err = models.NewLFSMetaObject(&models.LFSMetaObject{Oid: object.Oid, Size: object.Size, RepositoryID: repository.ID})
 		if err == nil {
 			responseObjects = append(responseObjects, Represent(object, meta, meta.Existing, !contentStore.Exists(meta)))
@@ -329,6 +354,15 @@ func PutHandler(ctx *context.Context) {
 	}
 
 	contentStore := &ContentStore{BasePath: setting.LFS.ContentPath}
+	if err := checkSizeLimit(repository, contentStore, meta.Oid, meta.Size); err != nil {
+		if models.IsErrRepoSizeLimitExceeded(err) {
+			writeSizeLimitExceeded(ctx, err.(models.ErrRepoSizeLimitExceeded))
+		} else {
+			log.Error(4, "checkSizeLimit: %v", err)
+			writeStatus(ctx, 500)
+		}
+		return
+	}
 	if err := contentStore.Put(meta, ctx.Req.Body().ReadCloser()); err != nil {
 		ctx.Resp.WriteHeader(500)
 		fmt.Fprintf(ctx.Resp, `{"message":"%s"}`, err)
@@ -471,6 +505,28 @@ func unpackbatch(ctx *context.Context) *BatchVars {
 	return &bv
 }
 
+// checkSizeLimit returns an ErrRepoSizeLimitExceeded if uploading the object
+// makes the repository exceed its size limit. The objects the repository
+// already stores are always allowed, as they are not uploaded again.
+func checkSizeLimit(repository *models.Repository, contentStore *ContentStore, oid string, size int64) error {
+	if meta, err := repository.GetLFSMetaObjectByOid(oid); err == nil && contentStore.Exists(meta) {
+		return nil
+	}
+	return repository.CheckLFSSizeLimit(oid, size)
+}
+
+func sizeLimitExceededMessage(err models.ErrRepoSizeLimitExceeded) string {
+	return fmt.Sprintf("upload of %s exceeds the repository size limit of %s, the repository already uses %s",
+		base.FileSize(err.PushSize), base.FileSize(err.Limit), base.FileSize(err.Size))
+}
+
+func writeSizeLimitExceeded(ctx *context.Context, err models.ErrRepoSizeLimitExceeded) {
+	ctx.Resp.Header().Set("Content-Type", metaMediaType)
+	ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(ctx.Resp).Encode(map[string]string{"message": sizeLimitExceededMessage(err)})
+	logRequest(ctx.Req, http.StatusRequestEntityTooLarge)
+}
+
 func writeStatus(ctx *context.Context, status int) {
 	message := http.StatusText(status)
 
@@ -0,0 +1,174 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package linguist
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// linguist attributes of .gitattributes files
+const (
+	attrVendored      = "linguist-vendored"
+	attrGenerated     = "linguist-generated"
+	attrDocumentation = "linguist-documentation"
+	attrLanguage      = "linguist-language"
+)
+
+// attrRule is a line of a .gitattributes file, the value of an attribute is
+// nil if the line unsets it.
+type attrRule struct {
+	pattern *regexp.Regexp
+	attrs   map[string]*string
+}
+
+// Attributes are the linguist attributes of a .gitattributes file.
+type Attributes struct {
+	rules []attrRule
+}
+
+// FileAttributes are the linguist attributes of a file.
+type FileAttributes struct {
+	Vendored      bool
+	Generated     bool
+	Documentation bool
+	// Language overrides the language detected from the file name if not empty.
+	Language string
+}
+
+// ParseAttributes parses the linguist attributes of the content of a
+// .gitattributes file at the root of a repository. Lines which do not set any
+// linguist attribute, as well as macros, are ignored.
+func ParseAttributes(data []byte) *Attributes {
+	attrs := &Attributes{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+
+		rule := attrRule{attrs: make(map[string]*string)}
+		for _, field := range fields[1:] {
+			var name string
+			var value *string
+			switch {
+			case strings.HasPrefix(field, "-"):
+				name = field[1:]
+				value = newString("false")
+			case strings.HasPrefix(field, "!"):
+				name = field[1:]
+			case strings.Contains(field, "="):
+				idx := strings.IndexByte(field, '=')
+				name = field[:idx]
+				value = newString(field[idx+1:])
+			default:
+				name = field
+				value = newString("true")
+			}
+			switch name {
+			case attrVendored, attrGenerated, attrDocumentation, attrLanguage:
+				rule.attrs[name] = value
+			}
+		}
+		if len(rule.attrs) == 0 {
+			continue
+		}
+
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			continue
+		}
+		rule.pattern = pattern
+		attrs.rules = append(attrs.rules, rule)
+	}
+	return attrs
+}
+
+// Match returns the linguist attributes of the file at given path, relative
+// to the root of the repository. As in git, the last matching line of the
+// .gitattributes file takes precedence for every attribute.
+func (a *Attributes) Match(path string) FileAttributes {
+	var fa FileAttributes
+	if a == nil {
+		return fa
+	}
+
+	values := make(map[string]*string)
+	for _, rule := range a.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		for name, value := range rule.attrs {
+			values[name] = value
+		}
+	}
+
+	isSet := func(name string) bool {
+		value := values[name]
+		return value != nil && *value != "false"
+	}
+	fa.Vendored = isSet(attrVendored)
+	fa.Generated = isSet(attrGenerated)
+	fa.Documentation = isSet(attrDocumentation)
+	if value := values[attrLanguage]; value != nil && *value != "true" && *value != "false" {
+		fa.Language = normalizeLanguage(*value)
+	}
+	return fa
+}
+
+// compilePattern converts a .gitattributes pattern to a regular expression
+// matching the paths relative to the root of the repository. A pattern
+// without a slash matches the file name at any depth, otherwise it is
+// relative to the root, and "**" matches any number of directories.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	buf.WriteString("^")
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		buf.WriteString("(?:.*/)?")
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			buf.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**") && i+2 == len(pattern):
+			buf.WriteString(".*")
+			i++
+		case c == '*':
+			buf.WriteString("[^/]*")
+		case c == '?':
+			buf.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				buf.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			buf.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
+
+func newString(s string) *string {
+	return &s
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package linguist
+
+import (
+	"path"
+	"strings"
+)
+
+var (
+	// languageFileNames are the languages of files recognized by their name.
+	languageFileNames = map[string]string{
+		"dockerfile":     "Dockerfile",
+		"makefile":       "Makefile",
+		"gnumakefile":    "Makefile",
+		"rakefile":       "Ruby",
+		"gemfile":        "Ruby",
+		"cmakelists.txt": "CMake",
+	}
+
+	// languageExtensions are the languages of files recognized by their
+	// extension. Only programming and markup languages are listed, data and
+	// prose files like JSON or Markdown do not count in the statistics.
+	languageExtensions = map[string]string{
+		".as":     "ActionScript",
+		".asm":    "Assembly",
+		".bat":    "Batchfile",
+		".c":      "C",
+		".cc":     "C++",
+		".clj":    "Clojure",
+		".cmake":  "CMake",
+		".coffee": "CoffeeScript",
+		".cpp":    "C++",
+		".cs":     "C#",
+		".css":    "CSS",
+		".cxx":    "C++",
+		".d":      "D",
+		".dart":   "Dart",
+		".el":     "Emacs Lisp",
+		".erl":    "Erlang",
+		".ex":     "Elixir",
+		".exs":    "Elixir",
+		".f90":    "Fortran",
+		".fs":     "F#",
+		".go":     "Go",
+		".groovy": "Groovy",
+		".h":      "C",
+		".hh":     "C++",
+		".hpp":    "C++",
+		".hs":     "Haskell",
+		".htm":    "HTML",
+		".html":   "HTML",
+		".java":   "Java",
+		".jl":     "Julia",
+		".js":     "JavaScript",
+		".jsx":    "JavaScript",
+		".kt":     "Kotlin",
+		".less":   "Less",
+		".lisp":   "Common Lisp",
+		".lua":    "Lua",
+		".m":      "Objective-C",
+		".ml":     "OCaml",
+		".php":    "PHP",
+		".pl":     "Perl",
+		".pm":     "Perl",
+		".ps1":    "PowerShell",
+		".py":     "Python",
+		".r":      "R",
+		".rb":     "Ruby",
+		".rs":     "Rust",
+		".sass":   "Sass",
+		".scala":  "Scala",
+		".scss":   "SCSS",
+		".sh":     "Shell",
+		".sql":    "SQL",
+		".swift":  "Swift",
+		".tcl":    "Tcl",
+		".tex":    "TeX",
+		".ts":     "TypeScript",
+		".tsx":    "TypeScript",
+		".vb":     "Visual Basic",
+		".vue":    "Vue",
+		".zsh":    "Shell",
+	}
+
+	// languageNames are the known languages by their lower case name.
+	languageNames = make(map[string]string)
+)
+
+func init() {
+	for _, languages := range []map[string]string{languageFileNames, languageExtensions} {
+		for _, language := range languages {
+			languageNames[strings.ToLower(language)] = language
+		}
+	}
+}
+
+// normalizeLanguage returns the known spelling of a language given by the
+// linguist-language attribute, dashes may be used for spaces.
+func normalizeLanguage(language string) string {
+	if known, ok := languageNames[strings.ToLower(strings.Replace(language, "-", " ", -1))]; ok {
+		return known
+	}
+	return language
+}
+
+// LanguageByFileName returns the language of the file at given path detected
+// from its name, or an empty string for files without a known language.
+func LanguageByFileName(filePath string) string {
+	name := strings.ToLower(path.Base(filePath))
+	if language, ok := languageFileNames[name]; ok {
+		return language
+	}
+	return languageExtensions[path.Ext(name)]
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package linguist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributes_Match(t *testing.T) {
+	attrs := ParseAttributes([]byte(`# comment
+*.pb.go linguist-generated
+vendor/** linguist-vendored
+vendor/ours/** -linguist-vendored
+/docs/** linguist-documentation
+**/testdata/** linguist-vendored=true
+*.inc linguist-language=PHP
+*.h linguist-language=c++
+lib/*.js linguist-vendored
+lib/main.js !linguist-vendored
+*.txt text eol=lf
+`))
+
+	assert.Equal(t, FileAttributes{Generated: true}, attrs.Match("api/service.pb.go"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("api/service.go"))
+	assert.Equal(t, FileAttributes{Vendored: true}, attrs.Match("vendor/github.com/pkg/errors/errors.go"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("vendor/ours/lib.go"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("src/vendor/lib.go"))
+	assert.Equal(t, FileAttributes{Documentation: true}, attrs.Match("docs/conf.py"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("src/docs/conf.py"))
+	assert.Equal(t, FileAttributes{Vendored: true}, attrs.Match("testdata/a.go"))
+	assert.Equal(t, FileAttributes{Vendored: true}, attrs.Match("pkg/parser/testdata/a.go"))
+	assert.Equal(t, FileAttributes{Language: "PHP"}, attrs.Match("includes/header.inc"))
+	assert.Equal(t, FileAttributes{Language: "C++"}, attrs.Match("include/lib.h"))
+	assert.Equal(t, FileAttributes{Vendored: true}, attrs.Match("lib/jquery.js"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("lib/sub/jquery.js"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("lib/main.js"))
+	assert.Equal(t, FileAttributes{}, attrs.Match("README.txt"))
+
+	var noAttrs *Attributes
+	assert.Equal(t, FileAttributes{}, noAttrs.Match("vendor/lib.go"))
+}
+
+func TestLanguageByFileName(t *testing.T) {
+	assert.Equal(t, "Go", LanguageByFileName("main.go"))
+	assert.Equal(t, "JavaScript", LanguageByFileName("public/js/index.JS"))
+	assert.Equal(t, "Makefile", LanguageByFileName("Makefile"))
+	assert.Equal(t, "Dockerfile", LanguageByFileName("docker/Dockerfile"))
+	assert.Equal(t, "", LanguageByFileName("README.md"))
+	assert.Equal(t, "", LanguageByFileName("LICENSE"))
+}
+
+func TestStats(t *testing.T) {
+	files := []File{
+		{Path: "main.go", Size: 300},
+		{Path: "models/user.go", Size: 500},
+		{Path: "vendor/github.com/pkg/errors/errors.go", Size: 10000},
+		{Path: "vendor/github.com/pkg/errors/Makefile", Size: 100},
+		{Path: "public/js/index.js", Size: 200},
+		{Path: "templates/header.inc", Size: 1000},
+		{Path: "README.md", Size: 4000},
+		{Path: "empty.py", Size: 0},
+	}
+
+	stats := Stats(files, nil)
+	assert.Equal(t, []*LanguageStat{
+		{Language: "Go", Size: 10800, Percentage: 10800 * 100 / 11100.0},
+		{Language: "JavaScript", Size: 200, Percentage: 200 * 100 / 11100.0},
+		{Language: "Makefile", Size: 100, Percentage: 100 * 100 / 11100.0},
+	}, stats)
+
+	// a .gitattributes excluding a directory
+	stats = Stats(files, ParseAttributes([]byte("vendor/** linguist-vendored\n")))
+	assert.Equal(t, []*LanguageStat{
+		{Language: "Go", Size: 800, Percentage: 80},
+		{Language: "JavaScript", Size: 200, Percentage: 20},
+	}, stats)
+
+	// and one overriding the language of some files
+	stats = Stats(files, ParseAttributes([]byte(`vendor/** linguist-vendored
+*.inc linguist-language=php
+*.js linguist-language=TypeScript
+`)))
+	assert.Equal(t, []*LanguageStat{
+		{Language: "PHP", Size: 1000, Percentage: 50},
+		{Language: "Go", Size: 800, Percentage: 40},
+		{Language: "TypeScript", Size: 200, Percentage: 10},
+	}, stats)
+
+	assert.Empty(t, Stats([]File{{Path: "README.md", Size: 10}}, nil))
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package linguist
+
+import "sort"
+
+// File is a file of a repository to compute language statistics of.
+type File struct {
+	Path string
+	Size int64
+}
+
+// LanguageStat is the share of a language in the files of a repository.
+type LanguageStat struct {
+	Language   string
+	Size       int64
+	Percentage float64
+}
+
+// Stats returns the byte counts of the languages of files, the biggest
+// first. Vendored, generated and documentation files, as marked by attrs,
+// are excluded, and the language of a file may be overridden by attrs.
+func Stats(files []File, attrs *Attributes) []*LanguageStat {
+	sizes := make(map[string]int64)
+	var total int64
+	for _, f := range files {
+		fa := attrs.Match(f.Path)
+		if fa.Vendored || fa.Generated || fa.Documentation {
+			continue
+		}
+		language := fa.Language
+		if len(language) == 0 {
+			language = LanguageByFileName(f.Path)
+		}
+		if len(language) == 0 || f.Size == 0 {
+			continue
+		}
+		sizes[language] += f.Size
+		total += f.Size
+	}
+
+	stats := make([]*LanguageStat, 0, len(sizes))
+	for language, size := range sizes {
+		stats = append(stats, &LanguageStat{
+			Language:   language,
+			Size:       size,
+			Percentage: float64(size) * 100 / float64(total),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Size != stats[j].Size {
+			return stats[i].Size > stats[j].Size
+		}
+		return stats[i].Language < stats[j].Language
+	})
+	return stats
+}
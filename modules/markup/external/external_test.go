@@ -0,0 +1,78 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package external_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/markup"
+	. "code.gitea.io/gitea/modules/markup/external"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func registerTestParsers(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is required to run the external renderer tests")
+	}
+	setting.ExternalMarkupParsers = []setting.MarkupParser{
+		{
+			Enabled:        true,
+			MarkupName:     "asciidoc",
+			FileExtensions: []string{".adoc", ".asciidoc"},
+			Command:        "cat",
+		},
+		{
+			Enabled:        true,
+			MarkupName:     "nocommand",
+			FileExtensions: []string{".nocmd"},
+		},
+		{
+			Enabled:        false,
+			MarkupName:     "disabled",
+			FileExtensions: []string{".disabled"},
+			Command:        "cat",
+		},
+	}
+	RegisterParsers()
+}
+
+func TestRegisterParsers(t *testing.T) {
+	registerTestParsers(t)
+
+	assert.Equal(t, "asciidoc", markup.Type("README.adoc"))
+	assert.Equal(t, "asciidoc", markup.Type("docs/Guide.ASCIIDOC"))
+	assert.True(t, markup.IsReadmeFile("README.adoc"))
+	assert.Empty(t, markup.Type("README.adoc.txt"))
+	assert.Empty(t, markup.Type("file.nocmd"))
+	assert.Empty(t, markup.Type("file.disabled"))
+	assert.Nil(t, markup.GetParserByType("nocommand"))
+	assert.Nil(t, markup.GetParserByType("disabled"))
+}
+
+func TestRender_Sanitized(t *testing.T) {
+	registerTestParsers(t)
+	setting.AppURL = "http://localhost:3000/"
+
+	input := `<div class="paragraph"><p>Some <strong>AsciiDoc</strong></p></div>` +
+		`<script>alert("xss")</script>` +
+		`<a href="javascript:alert('xss')" onclick="alert('xss')">link</a>`
+	for _, isWiki := range []bool{false, true} {
+		var output string
+		if isWiki {
+			output = markup.RenderWiki("Home.adoc", []byte(input), "/user2/repo1/wiki", nil)
+		} else {
+			output = string(markup.Render("README.adoc", []byte(input), "/user2/repo1/src/branch/master", nil))
+		}
+		assert.Contains(t, output, "<p>Some <strong>AsciiDoc</strong></p>")
+		assert.Contains(t, output, "link")
+		assert.False(t, strings.Contains(output, "<script"), output)
+		assert.False(t, strings.Contains(output, "javascript:"), output)
+		assert.False(t, strings.Contains(output, "onclick"), output)
+	}
+}
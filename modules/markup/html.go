@@ -279,11 +279,14 @@ func (ctx *postProcessCtx) postProcess(rawHTML []byte) ([]byte, error) {
 }
 
 func (ctx *postProcessCtx) visitNode(node *html.Node) {
-	// We ignore code, pre and already generated links.
+	// We ignore code, pre, diagram sources and already generated links.
 	switch node.Type {
 	case html.TextNode:
 		ctx.textNode(node)
 	case html.ElementNode:
+		if isMermaidNode(node) {
+			return
+		}
 		if node.Data == "a" || node.Data == "code" || node.Data == "pre" {
 			if node.Data == "a" && ctx.visitLinksForShortLinks {
 				ctx.visitNodeForShortLinks(node)
@@ -297,6 +300,20 @@ func (ctx *postProcessCtx) visitNode(node *html.Node) {
 	// ignore everything else
 }
 
+// isMermaidNode reports whether node is a diagram placeholder emitted by the
+// markdown renderer, whose text must be left untouched.
+func isMermaidNode(node *html.Node) bool {
+	if node.Data != "div" {
+		return false
+	}
+	for _, attr := range node.Attr {
+		if attr.Key == "class" && attr.Val == "mermaid" {
+			return true
+		}
+	}
+	return false
+}
+
 func (ctx *postProcessCtx) visitNodeForShortLinks(node *html.Node) {
 	switch node.Type {
 	case html.TextNode:
@@ -7,6 +7,7 @@ package markdown
 
 import (
 	"bytes"
+	"html"
 	"strings"
 
 	"code.gitea.io/gitea/modules/markup"
@@ -100,6 +101,31 @@ func (r *Renderer) ListItem(out *bytes.Buffer, text []byte, flags int) {
 	r.Renderer.ListItem(out, text, flags)
 }
 
+// BlockCode renders fenced code blocks. Blocks tagged as mermaid are wrapped in
+// a div the frontend can turn into a diagram, others are left to blackfriday.
+func (r *Renderer) BlockCode(out *bytes.Buffer, text []byte, lang string) {
+	if !setting.Markdown.EnableMermaid || !isMermaidBlock(lang) {
+		r.Renderer.BlockCode(out, text, lang)
+		return
+	}
+
+	if out.Len() > 0 {
+		out.WriteByte('\n')
+	}
+	out.WriteString(`<div class="mermaid">`)
+	out.WriteString(html.EscapeString(string(text)))
+	out.WriteString("</div>\n")
+}
+
+// isMermaidBlock reports whether the info string of a fenced code block
+// declares a mermaid diagram.
+func isMermaidBlock(info string) bool {
+	if i := strings.IndexAny(info, "\t "); i >= 0 {
+		info = info[:i]
+	}
+	return strings.ToLower(info) == "mermaid"
+}
+
 // Image defines how images should be processed to produce corresponding HTML elements.
 func (r *Renderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
 	prefix := r.URLPrefix
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/modules/markup"
 	. "code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -217,3 +218,35 @@ func TestTotal_RenderString(t *testing.T) {
 		assert.Equal(t, testCases[i+1], line)
 	}
 }
+
+func TestRender_Mermaid(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+	defer func(enabled bool) { setting.Markdown.EnableMermaid = enabled }(setting.Markdown.EnableMermaid)
+
+	test := func(input, expected string) {
+		buffer := RenderString(input, setting.AppSubURL, nil)
+		assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(buffer))
+		buffer = string(markup.Render("README.md", []byte(input), setting.AppSubURL, nil))
+		assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(buffer))
+	}
+
+	setting.Markdown.EnableMermaid = true
+	test("```mermaid\ngraph TD;\n  A-->B;\n  @user-->#1;\n```",
+		`<div class="mermaid">graph TD;
+  A--&gt;B;
+  @user--&gt;#1;
+</div>`)
+	test("```mermaid\n</div><script>alert(1)</script>\n```",
+		`<div class="mermaid">&lt;/div&gt;&lt;script&gt;alert(1)&lt;/script&gt;
+</div>`)
+	test("```go\nfmt.Println(\"A-->B\")\n```",
+		`<pre><code class="language-go">fmt.Println(&#34;A--&gt;B&#34;)
+</code></pre>`)
+
+	setting.Markdown.EnableMermaid = false
+	test("```mermaid\ngraph TD;\n  A-->B;\n```",
+		`<pre><code class="language-mermaid">graph TD;
+  A--&gt;B;
+</code></pre>`)
+}
@@ -22,6 +22,7 @@ type Collector struct {
 	Follows       *prometheus.Desc
 	HookTasks     *prometheus.Desc
 	Issues        *prometheus.Desc
+	IssuesOpen    *prometheus.Desc
 	Labels        *prometheus.Desc
 	LoginSources  *prometheus.Desc
 	Milestones    *prometheus.Desc
@@ -29,6 +30,8 @@ type Collector struct {
 	Oauths        *prometheus.Desc
 	Organizations *prometheus.Desc
 	PublicKeys    *prometheus.Desc
+	PullsOpen     *prometheus.Desc
+	QueueLengths  *prometheus.Desc
 	Releases      *prometheus.Desc
 	Repositories  *prometheus.Desc
 	Stars         *prometheus.Desc
@@ -77,6 +80,11 @@ func NewCollector() Collector {
 			"Number of Issues",
 			nil, nil,
 		),
+		IssuesOpen: prometheus.NewDesc(
+			namespace+"issues_open",
+			"Number of open Issues",
+			nil, nil,
+		),
 		Labels: prometheus.NewDesc(
 			namespace+"labels",
 			"Number of Labels",
@@ -112,6 +120,16 @@ func NewCollector() Collector {
 			"Number of PublicKeys",
 			nil, nil,
 		),
+		PullsOpen: prometheus.NewDesc(
+			namespace+"pulls_open",
+			"Number of open PullRequests",
+			nil, nil,
+		),
+		QueueLengths: prometheus.NewDesc(
+			namespace+"queue_length",
+			"Number of items waiting in an internal queue",
+			[]string{"queue"}, nil,
+		),
 		Releases: prometheus.NewDesc(
 			namespace+"releases",
 			"Number of Releases",
@@ -156,6 +174,17 @@ func NewCollector() Collector {
 
 }
 
+// Register registers a Collector to the default prometheus registry. A
+// Collector already registered, as when the routes are set up again, is kept.
+func Register() error {
+	if err := prometheus.Register(NewCollector()); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
 // Describe returns all possible prometheus.Desc
 func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Accesses
@@ -165,6 +194,7 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Follows
 	ch <- c.HookTasks
 	ch <- c.Issues
+	ch <- c.IssuesOpen
 	ch <- c.Labels
 	ch <- c.LoginSources
 	ch <- c.Milestones
@@ -172,6 +202,8 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Oauths
 	ch <- c.Organizations
 	ch <- c.PublicKeys
+	ch <- c.PullsOpen
+	ch <- c.QueueLengths
 	ch <- c.Releases
 	ch <- c.Repositories
 	ch <- c.Stars
@@ -221,6 +253,11 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Issue),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		c.IssuesOpen,
+		prometheus.GaugeValue,
+		float64(stats.Counter.OpenIssue),
+	)
 	ch <- prometheus.MustNewConstMetric(
 		c.Labels,
 		prometheus.GaugeValue,
@@ -256,6 +293,25 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.PublicKey),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		c.PullsOpen,
+		prometheus.GaugeValue,
+		float64(stats.Counter.OpenPull),
+	)
+	// The hook queue only holds the IDs of the repositories with tasks to
+	// deliver, the tasks waiting for delivery are counted instead.
+	for queue, length := range map[string]int64{
+		"mirror_sync":        int64(models.MirrorQueue.Len()),
+		"webhook_delivery":   stats.Counter.UndeliveredHookTask,
+		"pull_request_check": int64(models.PullRequestQueueLen()),
+	} {
+		ch <- prometheus.MustNewConstMetric(
+			c.QueueLengths,
+			prometheus.GaugeValue,
+			float64(length),
+			queue,
+		)
+	}
 	ch <- prometheus.MustNewConstMetric(
 		c.Releases,
 		prometheus.GaugeValue,
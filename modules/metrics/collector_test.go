@@ -0,0 +1,75 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/sdk/gitea"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	assert.NoError(t, models.CreateHookTask(&models.HookTask{
+		RepoID:    1,
+		HookID:    1,
+		Type:      models.GITEA,
+		URL:       "http://www.example.com/unit_test",
+		Payloader: &api.PushPayload{},
+	}))
+
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(NewCollector()))
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	values := make(map[string]float64, len(families))
+	queues := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if family.GetName() == "gitea_queue_length" {
+				for _, label := range metric.GetLabel() {
+					queues[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+				continue
+			}
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	for _, name := range []string{
+		"gitea_repositories",
+		"gitea_users",
+		"gitea_organizations",
+		"gitea_issues",
+		"gitea_issues_open",
+		"gitea_pulls_open",
+	} {
+		assert.Contains(t, values, name)
+	}
+	stats := models.GetStatistic()
+	assert.EqualValues(t, stats.Counter.Repo, values["gitea_repositories"])
+	assert.EqualValues(t, stats.Counter.User, values["gitea_users"])
+	assert.EqualValues(t, stats.Counter.Org, values["gitea_organizations"])
+	assert.EqualValues(t, models.GetCount(t, &models.Issue{}, models.Cond("is_closed = ? AND is_pull = ?", false, false)), values["gitea_issues_open"])
+	assert.EqualValues(t, models.GetCount(t, &models.Issue{}, models.Cond("is_closed = ? AND is_pull = ?", false, true)), values["gitea_pulls_open"])
+
+	assert.Equal(t, map[string]float64{
+		"mirror_sync":        float64(models.MirrorQueue.Len()),
+		"webhook_delivery":   float64(models.GetCount(t, &models.HookTask{}, models.Cond("is_delivered = ?", false))),
+		"pull_request_check": float64(models.PullRequestQueueLen()),
+	}, queues)
+	assert.EqualValues(t, 1, queues["webhook_delivery"])
+}
+
+func TestRegister(t *testing.T) {
+	// registering again, as on reload, does not fail
+	assert.NoError(t, Register())
+	assert.NoError(t, Register())
+}
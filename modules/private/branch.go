@@ -64,3 +64,28 @@ func CanUserPush(protectedBranchID, userID int64) (bool, error) {
 
 	return canPush["can_push"].(bool), nil
 }
+
+// CanUserPushProtectedFiles returns if user can push changes to the protected files of a protected branch
+func CanUserPushProtectedFiles(protectedBranchID, userID int64) (bool, error) {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/protectedbranch/%d/%d/protected-files", protectedBranchID, userID)
+	log.GitLogger.Trace("CanUserPushProtectedFiles: %s", reqURL)
+
+	resp, err := newInternalRequest(reqURL, "GET").Response()
+	if err != nil {
+		return false, err
+	}
+
+	var canPush = make(map[string]interface{})
+	if err := json.NewDecoder(resp.Body).Decode(&canPush); err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	// All 2XX status codes are accepted and others will return an error
+	if resp.StatusCode/100 != 2 {
+		return false, fmt.Errorf("Failed to retrieve push user: %s", decodeJSONError(resp).Err)
+	}
+
+	return canPush["can_push"].(bool), nil
+}
@@ -66,3 +66,32 @@ func ActivePullRequest(baseRepoID int64, headRepoID int64, baseBranch, headBranc
 
 	return pr, nil
 }
+
+// CheckRepoSizeLimit returns a models.ErrRepoSizeLimitExceeded if pushing
+// pushSize bytes to the repository exceeds its size limit
+func CheckRepoSizeLimit(repoID, pushSize int64) error {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/repositories/%d/size-limit?pushSize=%d", repoID, pushSize)
+	log.GitLogger.Trace("CheckRepoSizeLimit: %s", reqURL)
+
+	resp, err := newInternalRequest(reqURL, "GET").Response()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// All 2XX status codes are accepted and others will return an error
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to check repository size limit: %s", decodeJSONError(resp).Err)
+	}
+
+	var result struct {
+		Exceeded *models.ErrRepoSizeLimitExceeded `json:"exceeded"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Exceeded != nil {
+		return *result.Exceeded
+	}
+	return nil
+}
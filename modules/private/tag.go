@@ -0,0 +1,40 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// CanUserPushTag returns if user can create, update or delete a tag
+func CanUserPushTag(repoID int64, tagName string, userID int64) (bool, error) {
+	// the tag name is escaped, as it may contain characters such as %, # or ?
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/protectedtag/%d/%d/%s", repoID, userID, url.PathEscape(tagName))
+	log.GitLogger.Trace("CanUserPushTag: %s", reqURL)
+
+	resp, err := newInternalRequest(reqURL, "GET").Response()
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// All 2XX status codes are accepted and others will return an error
+	if resp.StatusCode/100 != 2 {
+		return false, fmt.Errorf("Failed to retrieve push user: %s", decodeJSONError(resp).Err)
+	}
+
+	var canPush struct {
+		CanPush bool `json:"can_push"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&canPush); err != nil {
+		return false, err
+	}
+	return canPush.CanPush, nil
+}
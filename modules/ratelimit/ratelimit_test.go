@@ -0,0 +1,64 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(requestsPerMinute, burst int) (*Limiter, *time.Time) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(requestsPerMinute, burst)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l, now := newTestLimiter(60, 3)
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("1.2.3.4")
+		assert.True(t, ok)
+	}
+	ok, retryAfter := l.Allow("1.2.3.4")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// other keys have their own bucket
+	ok, _ = l.Allow("5.6.7.8")
+	assert.True(t, ok)
+
+	*now = now.Add(time.Second)
+	ok, _ = l.Allow("1.2.3.4")
+	assert.True(t, ok)
+	ok, _ = l.Allow("1.2.3.4")
+	assert.False(t, ok)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l, now := newTestLimiter(60, 3)
+
+	l.Allow("1.2.3.4")
+	l.Allow("5.6.7.8")
+	assert.Equal(t, 2, l.Len())
+
+	*now = now.Add(2 * time.Second)
+	l.Allow("5.6.7.8")
+	assert.Equal(t, 2, l.Len())
+
+	// 1.2.3.4 has been idle long enough for its bucket to be full again
+	*now = now.Add(2 * time.Second)
+	l.Allow("9.9.9.9")
+	assert.Equal(t, 2, l.Len())
+}
+
+func TestLimiter_ZeroRate(t *testing.T) {
+	l, _ := newTestLimiter(0, 3)
+	ok, _ := l.Allow("1.2.3.4")
+	assert.False(t, ok)
+}
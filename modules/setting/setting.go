@@ -82,6 +82,12 @@ const (
 	ReCaptcha    = "recaptcha"
 )
 
+// enumerates all the styles of generated avatars
+const (
+	AvatarStyleIdenticon = "identicon"
+	AvatarStyleInitials  = "initials"
+)
+
 // settings
 var (
 	// AppVer settings
@@ -165,6 +171,7 @@ var (
 	ReverseProxyAuthUser  string
 	ReverseProxyAuthEmail string
 	MinPasswordLength     int
+	PasswordHashAlgo      string
 	ImportLocalPaths      bool
 	DisableGitHooks       bool
 
@@ -189,12 +196,16 @@ var (
 	Webhook = struct {
 		QueueLength    int
 		DeliverTimeout int
+		MaxAttempts    int
+		RetryBackoff   int
 		SkipTLSVerify  bool
 		Types          []string
 		PagingNum      int
 	}{
 		QueueLength:    1000,
 		DeliverTimeout: 5,
+		MaxAttempts:    3,
+		RetryBackoff:   60,
 		SkipTLSVerify:  false,
 		PagingNum:      10,
 	}
@@ -205,11 +216,14 @@ var (
 		ForcePrivate           bool
 		DefaultPrivate         string
 		MaxCreationLimit       int
+		MaxSizeLimit           int64
 		MirrorQueueLength      int
 		PullRequestQueueLength int
+		HealthCheckQueueLength int
 		PreferredLicenses      []string
 		DisableHTTPGit         bool
 		UseCompatSSHURI        bool
+		DefaultBranch          string
 
 		// Repository editor settings
 		Editor struct {
@@ -236,16 +250,31 @@ var (
 		PullRequest struct {
 			WorkInProgressPrefixes []string
 		} `ini:"repository.pull-request"`
+
+		// Repository archive settings
+		Archive struct {
+			MaxAge          time.Duration
+			CleanupInterval time.Duration
+		} `ini:"-"`
+
+		// Repository signing settings
+		Signing struct {
+			SigningKey string
+			WebEditor  string
+		} `ini:"-"`
 	}{
 		AnsiCharset:            "",
 		ForcePrivate:           false,
 		DefaultPrivate:         RepoCreatingLastUserVisibility,
 		MaxCreationLimit:       -1,
+		MaxSizeLimit:           -1,
 		MirrorQueueLength:      1000,
 		PullRequestQueueLength: 1000,
+		HealthCheckQueueLength: 1000,
 		PreferredLicenses:      []string{"Apache License 2.0,MIT License"},
 		DisableHTTPGit:         false,
 		UseCompatSSHURI:        false,
+		DefaultBranch:          "master",
 
 		// Repository editor settings
 		Editor: struct {
@@ -286,6 +315,24 @@ var (
 		}{
 			WorkInProgressPrefixes: defaultPullRequestWorkInProgressPrefixes,
 		},
+
+		// Repository archive settings
+		Archive: struct {
+			MaxAge          time.Duration
+			CleanupInterval time.Duration
+		}{
+			MaxAge:          24 * time.Hour,
+			CleanupInterval: time.Hour,
+		},
+
+		// Repository signing settings
+		Signing: struct {
+			SigningKey string
+			WebEditor  string
+		}{
+			SigningKey: "default",
+			WebEditor:  "never",
+		},
 	}
 	RepoRootPath string
 	ScriptType   = "bash"
@@ -359,10 +406,12 @@ var (
 	// Markdown settings
 	Markdown = struct {
 		EnableHardLineBreak bool
+		EnableMermaid       bool
 		CustomURLSchemes    []string `ini:"CUSTOM_URL_SCHEMES"`
 		FileExtensions      []string
 	}{
 		EnableHardLineBreak: false,
+		EnableMermaid:       true,
 		FileExtensions:      strings.Split(".md,.markdown,.mdown,.mkd", ","),
 	}
 
@@ -380,6 +429,7 @@ var (
 	DisableGravatar       bool
 	EnableFederatedAvatar bool
 	LibravatarService     *libravatar.Libravatar
+	DefaultAvatarStyle    string
 
 	// Log settings
 	LogLevel    string
@@ -438,6 +488,11 @@ var (
 			Schedule   string
 			OlderThan  time.Duration
 		} `ini:"cron.deleted_branches_cleanup"`
+		ReleaseExpiredLFSLocks struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.release_expired_lfs_locks"`
 	}{
 		UpdateMirror: struct {
 			Enabled    bool
@@ -503,11 +558,21 @@ var (
 			Schedule:   "@every 24h",
 			OlderThan:  24 * time.Hour,
 		},
+		ReleaseExpiredLFSLocks: struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		}{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@every 1h",
+		},
 	}
 
 	// Git settings
 	Git = struct {
 		Version                  string `ini:"-"`
+		SupportPartialClone      bool   `ini:"-"`
 		DisableDiffHighlight     bool
 		MaxGitDiffLines          int
 		MaxGitDiffLineCharacters int
@@ -520,6 +585,14 @@ var (
 			Pull    int
 			GC      int `ini:"GC"`
 		} `ini:"git.timeout"`
+		HTTPRateLimit struct {
+			Enabled                        bool
+			AnonymousRequestsPerMinute     int
+			AnonymousBurst                 int
+			AuthenticatedRequestsPerMinute int
+			AuthenticatedBurst             int
+			TrustProxyHeaders              bool
+		} `ini:"git.http_rate_limit"`
 	}{
 		DisableDiffHighlight:     false,
 		MaxGitDiffLines:          1000,
@@ -539,6 +612,21 @@ var (
 			Pull:    300,
 			GC:      60,
 		},
+		HTTPRateLimit: struct {
+			Enabled                        bool
+			AnonymousRequestsPerMinute     int
+			AnonymousBurst                 int
+			AuthenticatedRequestsPerMinute int
+			AuthenticatedBurst             int
+			TrustProxyHeaders              bool
+		}{
+			Enabled:                        false,
+			AnonymousRequestsPerMinute:     60,
+			AnonymousBurst:                 20,
+			AuthenticatedRequestsPerMinute: 0,
+			AuthenticatedBurst:             0,
+			TrustProxyHeaders:              false,
+		},
 	}
 
 	// Mirror settings
@@ -953,6 +1041,7 @@ func NewContext() {
 	ReverseProxyAuthUser = sec.Key("REVERSE_PROXY_AUTHENTICATION_USER").MustString("X-WEBAUTH-USER")
 	ReverseProxyAuthEmail = sec.Key("REVERSE_PROXY_AUTHENTICATION_EMAIL").MustString("X-WEBAUTH-EMAIL")
 	MinPasswordLength = sec.Key("MIN_PASSWORD_LENGTH").MustInt(6)
+	PasswordHashAlgo = sec.Key("PASSWORD_HASH_ALGO").In("pbkdf2", []string{"pbkdf2", "bcrypt", "scrypt", "argon2id"})
 	ImportLocalPaths = sec.Key("IMPORT_LOCAL_PATHS").MustBool(false)
 	DisableGitHooks = sec.Key("DISABLE_GIT_HOOKS").MustBool(false)
 	InternalToken = sec.Key("INTERNAL_TOKEN").String()
@@ -1037,6 +1126,7 @@ func NewContext() {
 	Repository.DisableHTTPGit = sec.Key("DISABLE_HTTP_GIT").MustBool()
 	Repository.UseCompatSSHURI = sec.Key("USE_COMPAT_SSH_URI").MustBool()
 	Repository.MaxCreationLimit = sec.Key("MAX_CREATION_LIMIT").MustInt(-1)
+	Repository.MaxSizeLimit = sec.Key("MAX_SIZE_LIMIT").MustInt64(-1)
 	RepoRootPath = sec.Key("ROOT").MustString(path.Join(homeDir, "gitea-repositories"))
 	forcePathSeparator(RepoRootPath)
 	if !filepath.IsAbs(RepoRootPath) {
@@ -1055,6 +1145,10 @@ func NewContext() {
 		log.Fatal(4, "Failed to map Repository.Local settings: %v", err)
 	} else if err = Cfg.Section("repository.pull-request").MapTo(&Repository.PullRequest); err != nil {
 		log.Fatal(4, "Failed to map Repository.PullRequest settings: %v", err)
+	} else if err = Cfg.Section("repository.archive").MapTo(&Repository.Archive); err != nil {
+		log.Fatal(4, "Failed to map Repository.Archive settings: %v", err)
+	} else if err = Cfg.Section("repository.signing").MapTo(&Repository.Signing); err != nil {
+		log.Fatal(4, "Failed to map Repository.Signing settings: %v", err)
 	}
 
 	if !filepath.IsAbs(Repository.Upload.TempPath) {
@@ -1080,6 +1174,7 @@ func NewContext() {
 		GravatarSource = source
 	}
 	DisableGravatar = sec.Key("DISABLE_GRAVATAR").MustBool()
+	DefaultAvatarStyle = sec.Key("DEFAULT_AVATAR_STYLE").In(AvatarStyleIdenticon, []string{AvatarStyleIdenticon, AvatarStyleInitials})
 	EnableFederatedAvatar = sec.Key("ENABLE_FEDERATED_AVATAR").MustBool(!InstallLock)
 	if OfflineMode {
 		DisableGravatar = true
@@ -1203,6 +1298,10 @@ func NewContext() {
 		// Explicitly disable credential helper, otherwise Git credentials might leak
 		git.GlobalCommandArgs = append(git.GlobalCommandArgs, "-c", "credential.helper=")
 	}
+
+	// Partial clone needs upload-pack to support blob and tree filters
+	// and to serve the missing objects by ID later on
+	Git.SupportPartialClone = version.Compare(binVersion, "2.22", ">=")
 }
 
 // Service settings
@@ -1609,6 +1708,8 @@ func newWebhookService() {
 	sec := Cfg.Section("webhook")
 	Webhook.QueueLength = sec.Key("QUEUE_LENGTH").MustInt(1000)
 	Webhook.DeliverTimeout = sec.Key("DELIVER_TIMEOUT").MustInt(5)
+	Webhook.MaxAttempts = sec.Key("MAX_ATTEMPTS").MustInt(3)
+	Webhook.RetryBackoff = sec.Key("RETRY_BACKOFF").MustInt(60)
 	Webhook.SkipTLSVerify = sec.Key("SKIP_TLS_VERIFY").MustBool()
 	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk"}
 	Webhook.PagingNum = sec.Key("PAGING_NUM").MustInt(10)
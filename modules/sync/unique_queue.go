@@ -36,6 +36,11 @@ func (q *UniqueQueue) Queue() <-chan string {
 	return q.queue
 }
 
+// Len returns the number of instances waiting in the queue.
+func (q *UniqueQueue) Len() int {
+	return len(q.queue)
+}
+
 // Exist returns true if there is an instance with given identity
 // exists in the queue.
 func (q *UniqueQueue) Exist(id interface{}) bool {
@@ -0,0 +1,82 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// CompileGlob compiles a glob pattern into a regular expression matching whole
+// strings. A '*' matches any sequence of characters except separator, '**'
+// matches any sequence including separator, '?' matches a single character
+// other than separator and '[...]' matches a character class ('[!...]' negates
+// it). A zero separator makes '*' behave like '**'.
+func CompileGlob(pattern string, separator rune) (*regexp.Regexp, error) {
+	notSep := "."
+	if separator != 0 {
+		notSep = "[^" + regexp.QuoteMeta(string(separator)) + "]"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				// "**/" also matches no directory at all
+				if separator != 0 && i+1 < len(runes) && runes[i+1] == separator {
+					i++
+					buf.WriteString("(?:.*" + regexp.QuoteMeta(string(separator)) + ")?")
+				} else {
+					buf.WriteString(".*")
+				}
+			} else {
+				buf.WriteString(notSep + "*")
+			}
+		case '?':
+			buf.WriteString(notSep)
+		case '[':
+			end := i + 1
+			if end < len(runes) && runes[end] == '!' {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				// No closing bracket, so match it literally.
+				buf.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := string(runes[i+1 : end])
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i = end
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
+
+// GlobMatch reports whether name matches the glob pattern, see CompileGlob.
+// Invalid patterns never match.
+func GlobMatch(pattern, name string, separator rune) bool {
+	re, err := CompileGlob(pattern, separator)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+	type test struct {
+		Pattern string
+		Name    string
+		Matches bool
+	}
+	for _, test := range []test{
+		{"go.mod", "go.mod", true},
+		{"go.mod", "go_mod", false},
+		{"go.mod", "sub/go.mod", false},
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/sub/main.go", true},
+		{"migrations/**", "migrations/v1.sql", true},
+		{"migrations/**", "migrations/old/v1.sql", true},
+		{"migrations/**", "models/migrations/v1.sql", false},
+		{"v?.sql", "v1.sql", true},
+		{"v?.sql", "v10.sql", false},
+		{"v[0-9].sql", "v1.sql", true},
+		{"v[!0-9].sql", "v1.sql", false},
+		{"v[!0-9].sql", "va.sql", true},
+		{"v[0-9.sql", "v[0-9.sql", true},
+		{"build (*)", "build (go1.11)", true},
+		{"build (*)", "build go1.11", false},
+	} {
+		assert.Equal(t, test.Matches, GlobMatch(test.Pattern, test.Name, '/'), "%s ~ %s", test.Pattern, test.Name)
+	}
+
+	assert.False(t, GlobMatch("ci/*", "ci/build/linux", '/'))
+	assert.True(t, GlobMatch("ci/*", "ci/build/linux", 0))
+
+	_, err := CompileGlob("[z-a]", '/')
+	assert.Error(t, err)
+}
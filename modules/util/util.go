@@ -7,6 +7,7 @@ package util
 import (
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/gitea/modules/log"
@@ -48,6 +49,16 @@ func OptionalBoolOf(b bool) OptionalBool {
 	return OptionalBoolFalse
 }
 
+// OptionalBoolParse get the corresponding OptionalBool of a string using strconv.ParseBool,
+// it returns OptionalBoolNone if the string is empty or invalid
+func OptionalBoolParse(s string) OptionalBool {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return OptionalBoolNone
+	}
+	return OptionalBoolOf(b)
+}
+
 // Max max of two ints
 func Max(a, b int) int {
 	if a < b {
@@ -77,3 +77,11 @@ func TestIsExternalURL(t *testing.T) {
 		assert.Equal(t, test.Expected, IsExternalURL(test.RawURL))
 	}
 }
+
+func TestOptionalBoolParse(t *testing.T) {
+	assert.Equal(t, OptionalBool(OptionalBoolNone), OptionalBoolParse(""))
+	assert.Equal(t, OptionalBool(OptionalBoolNone), OptionalBoolParse("maybe"))
+	assert.Equal(t, OptionalBool(OptionalBoolTrue), OptionalBoolParse("true"))
+	assert.Equal(t, OptionalBool(OptionalBoolTrue), OptionalBoolParse("1"))
+	assert.Equal(t, OptionalBool(OptionalBoolFalse), OptionalBoolParse("false"))
+}
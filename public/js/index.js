@@ -1361,7 +1361,7 @@ function initAdmin() {
     }
 
     function onOAuth2Change() {
-        $('.open_id_connect_auto_discovery_url, .oauth2_use_custom_url').hide();
+        $('.open_id_connect_auto_discovery_url, .open_id_connect_group_mapping, .oauth2_use_custom_url').hide();
         $('.open_id_connect_auto_discovery_url input[required]').removeAttr('required');
 
         var provider = $('#oauth2_provider').val();
@@ -1372,7 +1372,7 @@ function initAdmin() {
                 break;
             case 'openidConnect':
                 $('.open_id_connect_auto_discovery_url input').attr('required', 'required');
-                $('.open_id_connect_auto_discovery_url').show();
+                $('.open_id_connect_auto_discovery_url, .open_id_connect_group_mapping').show();
                 break;
         }
         onOAuth2UseCustomURLChange();
@@ -1807,6 +1807,11 @@ $(document).ready(function () {
         hljs.initHighlightingOnLoad();
     }
 
+    // Mermaid diagrams, available when a custom template loads the library
+    if (typeof mermaid != 'undefined') {
+        mermaid.init(undefined, '.mermaid');
+    }
+
     // Dropzone
     var $dropzone = $('#dropzone');
     if ($dropzone.length > 0) {
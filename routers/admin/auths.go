@@ -149,6 +149,8 @@ func parseOAuth2Config(form auth.AuthenticationForm) *models.OAuth2Config {
 		ClientSecret:                  form.Oauth2Secret,
 		OpenIDConnectAutoDiscoveryURL: form.OpenIDConnectAutoDiscoveryURL,
 		CustomURLMapping:              customURLMapping,
+		GroupClaimName:                form.Oauth2GroupClaimName,
+		GroupTeamMap:                  form.Oauth2GroupTeamMap,
 	}
 }
 
@@ -192,6 +194,14 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 		return
 	}
 
+	if models.LoginType(form.Type) == models.LoginOAuth2 {
+		if _, err := models.ParseOAuth2GroupTeamMap(form.Oauth2GroupTeamMap); err != nil {
+			ctx.Data["Err_Oauth2GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_oauth2_group_team_map", err.Error()), tplAuthNew, form)
+			return
+		}
+	}
+
 	if err := models.CreateLoginSource(&models.LoginSource{
 		Type:          models.LoginType(form.Type),
 		Name:          form.Name,
@@ -273,6 +283,11 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ServiceName: form.PAMServiceName,
 		}
 	case models.LoginOAuth2:
+		if _, err := models.ParseOAuth2GroupTeamMap(form.Oauth2GroupTeamMap); err != nil {
+			ctx.Data["Err_Oauth2GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_oauth2_group_team_map", err.Error()), tplAuthEdit, form)
+			return
+		}
 		config = parseOAuth2Config(form)
 	default:
 		ctx.Error(400)
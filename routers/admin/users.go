@@ -209,6 +209,7 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 	u.Website = form.Website
 	u.Location = form.Location
 	u.MaxRepoCreation = form.MaxRepoCreation
+	u.MaxRepoSize = form.MaxRepoSize
 	u.IsActive = form.Active
 	u.IsAdmin = form.Admin
 	u.AllowGitHook = form.AllowGitHook
@@ -5,9 +5,13 @@
 package admin
 
 import (
+	"time"
+
 	api "code.gitea.io/sdk/gitea"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/user"
 )
@@ -45,3 +49,94 @@ func CreateRepo(ctx *context.APIContext, form api.CreateRepoOption) {
 
 	repo.CreateUserRepo(ctx, owner, form)
 }
+
+// RepoHealthCheck represents the health check of a repository
+type RepoHealthCheck struct {
+	// status of the last check, either "none", "healthy" or "unhealthy"
+	Status string `json:"status"`
+	// errors found by the last check
+	Errors string `json:"errors"`
+	// swagger:strfmt date-time
+	Checked *time.Time `json:"checked_at"`
+	// whether a check is waiting to be run
+	Queued bool `json:"queued"`
+}
+
+func toRepoHealthCheck(repo *models.Repository) *RepoHealthCheck {
+	check := &RepoHealthCheck{
+		Status: repo.HealthCheckStatus.String(),
+		Errors: repo.HealthCheckErrors,
+		Queued: models.IsRepoHealthCheckQueued(repo.ID),
+	}
+	if repo.HealthCheckStatus != models.RepoHealthCheckNone {
+		checked := repo.HealthCheckUnix.AsTime()
+		check.Checked = &checked
+	}
+	return check
+}
+
+// GetRepoHealthCheck api for getting the result of the last health check of a repository
+func GetRepoHealthCheck(ctx *context.APIContext) {
+	// swagger:operation GET /admin/repos/{owner}/{repo}/fsck admin adminGetRepoHealthCheck
+	// ---
+	// summary: Get the result of the last health check of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoHealthCheck"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	ctx.JSON(200, toRepoHealthCheck(ctx.Repo.Repository))
+}
+
+// CheckRepoHealth api for queueing a health check of a repository
+func CheckRepoHealth(ctx *context.APIContext) {
+	// swagger:operation POST /admin/repos/{owner}/{repo}/fsck admin adminCheckRepoHealth
+	// ---
+	// summary: Queue a health check of a repository
+	// description: Runs git fsck on the repository in the background. A check
+	//   requested while another one is waiting to be run is merged into it.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: gc
+	//   in: query
+	//   description: run git gc after git fsck if no error is found
+	//   type: boolean
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoHealthCheck"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	repo := ctx.Repo.Repository
+	if models.AddRepoHealthCheck(repo.ID, ctx.QueryBool("gc")) {
+		log.Trace("Health check of repository %s queued by %s", repo.FullName(), ctx.User.Name)
+	}
+	ctx.JSON(202, toRepoHealthCheck(repo))
+}
@@ -5,10 +5,14 @@
 package admin
 
 import (
+	"fmt"
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/v1/user"
 	api "code.gitea.io/sdk/gitea"
 )
@@ -33,6 +37,69 @@ func parseLoginSource(ctx *context.APIContext, u *models.User, sourceID int64, l
 	u.LoginName = loginName
 }
 
+// GetAllUsers API for getting information of all the users
+func GetAllUsers(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users admin adminGetAllUsers
+	// ---
+	// summary: List all users
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword to search in username, full name and email
+	//   type: string
+	// - name: active
+	//   in: query
+	//   description: if provided, only list active or inactive users
+	//   type: boolean
+	// - name: admin
+	//   in: query
+	//   description: if provided, only list site administrators or regular users
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	users, count, err := models.SearchUsers(&models.SearchUserOptions{
+		Keyword:       strings.Trim(ctx.Query("q"), " "),
+		Type:          models.UserTypeIndividual,
+		OrderBy:       models.SearchOrderByID,
+		Page:          ctx.QueryInt("page"),
+		PageSize:      limit,
+		IsActive:      util.OptionalBoolParse(ctx.Query("active")),
+		IsAdmin:       util.OptionalBoolParse(ctx.Query("admin")),
+		SearchByEmail: true,
+	})
+	if err != nil {
+		ctx.Error(500, "SearchUsers", err)
+		return
+	}
+
+	results := make([]*api.User, len(users))
+	for i := range users {
+		results[i] = users[i].APIFormat()
+	}
+
+	ctx.SetLinkHeader(int(count), limit)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.JSON(200, &results)
+}
+
 // CreateUser create a user
 func CreateUser(ctx *context.APIContext, form api.CreateUserOption) {
 	// swagger:operation POST /admin/users admin adminCreateUser
@@ -291,3 +358,91 @@ func DeleteUserPublicKey(ctx *context.APIContext) {
 
 	ctx.Status(204)
 }
+
+// ListUserSessions list the active web sessions of a user
+func ListUserSessions(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/sessions admin adminListUserSessions
+	// ---
+	// summary: List a user's active web sessions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SessionList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	user.ListUserSessions(ctx, u)
+}
+
+// DeleteUserSession revokes a web session of a user
+func DeleteUserSession(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/users/{username}/sessions/{id} admin adminDeleteUserSession
+	// ---
+	// summary: Revoke a web session of a user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the session to revoke
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	user.DeleteUserSession(ctx, u, ctx.ParamsInt64(":id"))
+}
+
+// DeleteUserSessions revokes all web sessions of a user
+func DeleteUserSessions(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/users/{username}/sessions admin adminDeleteUserSessions
+	// ---
+	// summary: Revoke all web sessions of a user
+	// description: Remember me cookies are invalidated as well, access tokens are kept.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	user.DeleteUserSessions(ctx, u)
+}
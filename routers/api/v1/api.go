@@ -463,6 +463,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/times", repo.ListMyTrackedTimes)
 
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
+
+			m.Group("/sessions", func() {
+				m.Combo("").Get(user.ListMySessions).
+					Delete(user.DeleteMySessions)
+				m.Delete("/:id", user.DeleteMySession)
+			})
 		}, reqToken())
 
 		// Repositories
@@ -480,6 +486,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Group("/:username/:reponame", func() {
 				m.Combo("").Get(reqAnyRepoReader(), repo.Get).
 					Delete(reqToken(), reqOwner(), repo.Delete)
+				m.Post("/issue_migration/retry", reqToken(), reqAdmin(), repo.RetryIssueMigration)
 				m.Group("/hooks", func() {
 					m.Combo("").Get(repo.ListHooks).
 						Post(bind(api.CreateHookOption{}), repo.CreateHook)
@@ -488,6 +495,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 							Patch(bind(api.EditHookOption{}), repo.EditHook).
 							Delete(repo.DeleteHook)
 						m.Post("/tests", context.RepoRef(), repo.TestHook)
+						m.Get("/deliveries", repo.ListHookDeliveries)
+						m.Post("/deliveries/:delivery/redeliver", repo.RedeliverHook)
 					})
 				}, reqToken(), reqAdmin())
 				m.Group("/collaborators", func() {
@@ -497,11 +506,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(repo.DeleteCollaborator)
 				}, reqToken(), reqAdmin())
 				m.Get("/raw/*", context.RepoRefByType(context.RepoRefAny), reqRepoReader(models.UnitTypeCode), repo.GetRawFile)
+				m.Get("/blame/*", context.RepoRefByType(context.RepoRefAny), reqRepoReader(models.UnitTypeCode), repo.GetBlame)
 				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
+					m.Get("/merged", repo.ListMergedBranches)
 					m.Get("/*", context.RepoRefByType(context.RepoRefBranch), repo.GetBranch)
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/keys", func() {
@@ -523,6 +534,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 							Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
 							Delete(repo.DeleteIssueComment)
 					})
+					m.Post("/labels/bulk", reqToken(), bind(auth.BulkIssueLabelsForm{}), repo.BulkUpdateIssueLabels)
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetIssue).
 							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
@@ -591,18 +603,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("/editorconfig/:filename", context.RepoRef(), reqRepoReader(models.UnitTypeCode), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(bind(api.ListPullRequestsOptions{}), repo.ListPullRequests).
-						Post(reqToken(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+						Post(reqToken(), bind(repo.CreateDraftablePullRequestOption{}), repo.CreatePullRequest)
 					m.Group("/:index", func() {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(), reqRepoWriter(models.UnitTypePullRequests), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), reqRepoWriter(models.UnitTypePullRequests), bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Post("/ready", reqToken(), repo.MarkPullRequestReady)
+						m.Get("/divergence", repo.GetPullRequestDivergence)
 					})
 				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo())
 				m.Group("/statuses", func() {
 					m.Combo("/:sha").Get(repo.GetCommitStatuses).
 						Post(reqToken(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/commits", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(), repo.ListCommits)
 				m.Group("/commits/:ref", func() {
 					m.Get("/status", repo.GetCombinedCommitStatusByRef)
 					m.Get("/statuses", repo.GetCommitStatusesByRef)
@@ -612,6 +627,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("/refs/*", repo.GetGitRefs)
 					m.Combo("/trees/:sha", context.RepoRef()).Get(repo.GetTree)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(), repo.CompareCommits)
+				m.Get("/stats/contributors", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(), repo.GetContributorStats)
 			}, repoAssignment())
 		})
 
@@ -669,7 +686,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 		m.Group("/admin", func() {
 			m.Group("/users", func() {
-				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Combo("").Get(admin.GetAllUsers).
+					Post(bind(api.CreateUserOption{}), admin.CreateUser)
 				m.Group("/:username", func() {
 					m.Combo("").Patch(bind(api.EditUserOption{}), admin.EditUser).
 						Delete(admin.DeleteUser)
@@ -679,8 +697,17 @@ func RegisterRoutes(m *macaron.Macaron) {
 					})
 					m.Post("/orgs", bind(api.CreateOrgOption{}), admin.CreateOrg)
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
+					m.Group("/sessions", func() {
+						m.Combo("").Get(admin.ListUserSessions).
+							Delete(admin.DeleteUserSessions)
+						m.Delete("/:id", admin.DeleteUserSession)
+					})
 				})
 			})
+			m.Group("/repos/:username/:reponame", func() {
+				m.Combo("/fsck").Get(admin.GetRepoHealthCheck).
+					Post(admin.CheckRepoHealth)
+			}, repoAssignment())
 		}, reqToken(), reqSiteAdmin())
 
 		m.Group("/topics", func() {
@@ -243,12 +243,18 @@ func AddTeamMember(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	u := user.GetUserByParams(ctx)
 	if ctx.Written() {
 		return
 	}
 	if err := ctx.Org.Team.AddMember(u.ID); err != nil {
-		ctx.Error(500, "AddMember", err)
+		if models.IsErrTwoFactorRequired(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "AddMember", err)
+		}
 		return
 	}
 	ctx.Status(204)
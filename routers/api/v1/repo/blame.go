@@ -0,0 +1,145 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+
+	"code.gitea.io/git"
+)
+
+// BlameSignature represents the author or the committer of a commit of a blame
+type BlameSignature struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// swagger:strfmt date-time
+	Date time.Time `json:"date"`
+}
+
+// BlameRange represents consecutive lines of a file last changed by the same commit
+type BlameRange struct {
+	// first line of the range, starting at 1
+	StartLine int `json:"start_line"`
+	// last line of the range
+	EndLine   int             `json:"end_line"`
+	SHA       string          `json:"sha"`
+	Summary   string          `json:"summary"`
+	Author    *BlameSignature `json:"author"`
+	Committer *BlameSignature `json:"committer"`
+}
+
+// FileBlame represents the blame of a file
+type FileBlame struct {
+	Path     string        `json:"path"`
+	CommitID string        `json:"commit_id"`
+	Ranges   []*BlameRange `json:"ranges"`
+}
+
+// GetBlame returns the commits the lines of a file were last changed by
+func GetBlame(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/blame/{ref}/{filepath} repository repoGetBlame
+	// ---
+	// summary: Get the blame of a file of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: branch, tag or commit to get the blame at
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: filepath of the file to get the blame of
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileBlame"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetBlobByPath", err)
+		}
+		return
+	}
+
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		ctx.Error(422, "", fmt.Errorf("file is larger than %d bytes", setting.UI.MaxDisplayFileSize))
+		return
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		ctx.Error(500, "DataAsync", err)
+		return
+	}
+	buf := make([]byte, 1024)
+	n, _ := dataRc.Read(buf)
+	dataRc.Close()
+	if !base.IsTextFile(buf[:n]) {
+		ctx.Error(422, "", fmt.Errorf("cannot blame binary file"))
+		return
+	}
+
+	commitID := ctx.Repo.Commit.ID.String()
+	ranges, err := models.GetBlame(ctx.Repo.Repository.RepoPath(), commitID, ctx.Repo.TreePath)
+	if err != nil {
+		ctx.Error(500, "GetBlame", err)
+		return
+	}
+
+	apiRanges := make([]*BlameRange, len(ranges))
+	for i, r := range ranges {
+		apiRanges[i] = &BlameRange{
+			StartLine: r.StartLine,
+			EndLine:   r.StartLine + r.LineCount - 1,
+			SHA:       r.Commit.SHA,
+			Summary:   r.Commit.Summary,
+			Author: &BlameSignature{
+				Name:  r.Commit.AuthorName,
+				Email: r.Commit.AuthorEmail,
+				Date:  r.Commit.AuthorTime,
+			},
+			Committer: &BlameSignature{
+				Name:  r.Commit.CommitterName,
+				Email: r.Commit.CommitterEmail,
+				Date:  r.Commit.CommitterTime,
+			},
+		}
+	}
+	ctx.JSON(200, &FileBlame{
+		Path:     ctx.Repo.TreePath,
+		CommitID: commitID,
+		Ranges:   apiRanges,
+	})
+}
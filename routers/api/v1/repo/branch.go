@@ -17,6 +17,9 @@ func GetBranch(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/branches/{branch} repository repoGetBranch
 	// ---
 	// summary: Retrieve a specific branch from a repository
+	// description: A branch named "merged" cannot be retrieved here, its path
+	//   is that of the list of merged branches. Its reference can be retrieved
+	//   at /repos/{owner}/{repo}/git/refs/heads/merged instead.
 	// produces:
 	// - application/json
 	// parameters:
@@ -103,3 +106,79 @@ func ListBranches(ctx *context.APIContext) {
 
 	ctx.JSON(200, &apiBranches)
 }
+
+// MergedBranch represents a branch merged into another one
+type MergedBranch struct {
+	Name string `json:"name"`
+	// whether the branch is the default branch of the repository
+	IsDefault bool `json:"is_default"`
+	// whether the branch is protected
+	IsProtected bool `json:"is_protected"`
+}
+
+// ListMergedBranches list the branches merged into a branch of a repository
+func ListMergedBranches(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branches/merged repository repoListMergedBranches
+	// ---
+	// summary: List the branches whose tip is reachable from a branch
+	// description: This path takes precedence over the one of a branch named
+	//   "merged", whose reference can be retrieved at
+	//   /repos/{owner}/{repo}/git/refs/heads/merged instead.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: into
+	//   in: query
+	//   description: branch the branches are merged into, defaults to the default branch
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergedBranchList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	into := ctx.Query("into")
+	if len(into) == 0 {
+		into = ctx.Repo.Repository.DefaultBranch
+	}
+
+	branches, err := ctx.Repo.Repository.GetMergedBranches(into)
+	if err != nil {
+		if models.IsErrBranchNotExist(err) {
+			ctx.Error(404, "", err)
+		} else {
+			ctx.Error(500, "GetMergedBranches", err)
+		}
+		return
+	}
+
+	protectedBranches, err := ctx.Repo.Repository.GetProtectedBranches()
+	if err != nil {
+		ctx.Error(500, "GetProtectedBranches", err)
+		return
+	}
+	protected := make(map[string]bool, len(protectedBranches))
+	for _, protectedBranch := range protectedBranches {
+		protected[protectedBranch.BranchName] = true
+	}
+
+	mergedBranches := make([]*MergedBranch, len(branches))
+	for i, branch := range branches {
+		mergedBranches[i] = &MergedBranch{
+			Name:        branch.Name,
+			IsDefault:   branch.Name == ctx.Repo.Repository.DefaultBranch,
+			IsProtected: protected[branch.Name],
+		}
+	}
+
+	ctx.JSON(200, &mergedBranches)
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ListCommits lists the commits of a repository
+func ListCommits(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/commits repository repoListCommits
+	// ---
+	// summary: List the commits of a repository, the newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: query
+	//   description: branch, tag or commit SHA to list the history of, defaults to the default branch
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	ref := ctx.Query("sha")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commitID, err := resolveCompareRef(ctx, ref)
+	if err != nil {
+		ctx.Error(500, "resolveCompareRef", err)
+		return
+	} else if len(commitID) == 0 {
+		ctx.Error(404, "", fmt.Sprintf("ref %q does not exist", ref))
+		return
+	}
+
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	commits, count, err := ctx.Repo.Repository.GetCommitsPage(ctx.Repo.GitRepo, commitID, ctx.QueryInt("page"), limit)
+	if err != nil {
+		ctx.Error(500, "GetCommitsPage", err)
+		return
+	}
+
+	ctx.SetLinkHeader(count, limit)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.JSON(200, models.ListToPushCommits(commits).ToAPIPayloadCommits(ctx.Repo.Repository.HTMLURL()))
+}
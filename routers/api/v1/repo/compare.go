@@ -0,0 +1,101 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// resolveCompareRef returns the commit ID of a branch, tag, commit SHA or
+// other revision, or an empty string if there is no such reference. Branches
+// take precedence over tags of the same name.
+func resolveCompareRef(ctx *context.APIContext, ref string) (string, error) {
+	gitRepo := ctx.Repo.GitRepo
+	switch {
+	case gitRepo.IsBranchExist(ref):
+		return gitRepo.GetBranchCommitID(ref)
+	case gitRepo.IsTagExist(ref):
+		commit, err := gitRepo.GetTagCommit(ref)
+		if err != nil {
+			return "", err
+		}
+		return commit.ID.String(), nil
+	}
+	return models.ResolveCommitRef(gitRepo, ref)
+}
+
+// CompareCommits compares two commits of a repository
+func CompareCommits(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareCommits
+	// ---
+	// summary: Compare two commits of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: base and head branch, tag, commit SHA or other revision separated by "...", e.g. "master...feature"
+	//   type: string
+	//   required: true
+	// - name: patch
+	//   in: query
+	//   description: include the patch text of the changes
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comparison"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	refs := strings.Split(ctx.Params("*"), "...")
+	if len(refs) != 2 || len(refs[0]) == 0 || len(refs[1]) == 0 {
+		ctx.Error(422, "", "base and head must be given as <base>...<head>")
+		return
+	}
+
+	commitIDs := make([]string, len(refs))
+	for i, ref := range refs {
+		commitID, err := resolveCompareRef(ctx, ref)
+		if err != nil {
+			ctx.Error(500, "resolveCompareRef", err)
+			return
+		} else if len(commitID) == 0 {
+			ctx.Error(404, "", fmt.Sprintf("ref %q does not exist", ref))
+			return
+		}
+		commitIDs[i] = commitID
+	}
+
+	comparison, err := ctx.Repo.Repository.CompareCommits(ctx.Repo.GitRepo, commitIDs[0], commitIDs[1], ctx.QueryBool("patch"))
+	if err != nil {
+		if models.IsErrNoMergeBase(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "CompareCommits", err)
+		}
+		return
+	}
+	ctx.JSON(200, comparison)
+}
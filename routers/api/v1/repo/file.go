@@ -36,6 +36,10 @@ func GetRawFile(ctx *context.APIContext) {
 	//   description: filepath of the file to get
 	//   type: string
 	//   required: true
+	// - name: pointer
+	//   in: query
+	//   description: return the LFS pointer file itself instead of the content of the LFS object
+	//   type: boolean
 	// responses:
 	//   200:
 	//     description: success
@@ -53,8 +57,12 @@ func GetRawFile(ctx *context.APIContext) {
 		}
 		return
 	}
-	if err = repo.ServeBlob(ctx.Context, blob); err != nil {
-		ctx.Error(500, "ServeBlob", err)
+	if err = repo.ServeBlobOrLFS(ctx.Context, blob); err != nil {
+		if err == models.ErrLFSObjectNotExist {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "ServeBlobOrLFS", err)
+		}
 	}
 }
 
@@ -5,6 +5,8 @@
 package repo
 
 import (
+	"time"
+
 	"code.gitea.io/git"
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -247,3 +249,209 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(204)
 }
+
+// HookDelivery represents a delivery of a webhook, including its retries
+type HookDelivery struct {
+	ID    int64  `json:"id"`
+	UUID  string `json:"uuid"`
+	Event string `json:"event"`
+	// whether no more attempts are pending
+	IsDelivered bool `json:"is_delivered"`
+	IsSucceed   bool `json:"is_succeed"`
+	Attempts    int  `json:"attempts"`
+	// swagger:strfmt date-time
+	Delivered *time.Time `json:"delivered,omitempty"`
+	// swagger:strfmt date-time
+	NextDelivery *time.Time `json:"next_delivery,omitempty"`
+	// the request and response of the last attempt
+	RequestHeaders  map[string]string `json:"request_headers"`
+	ResponseStatus  int               `json:"response_status"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	// the start of the response body, or why no response was received
+	ResponseBody string `json:"response_body"`
+	// the request and response of every attempt, in the order they were made
+	History []*HookDeliveryAttempt `json:"history"`
+}
+
+// HookDeliveryAttempt represents an attempt to deliver a webhook
+type HookDeliveryAttempt struct {
+	Attempt   int  `json:"attempt"`
+	IsSucceed bool `json:"is_succeed"`
+	// swagger:strfmt date-time
+	Delivered       *time.Time        `json:"delivered"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	ResponseStatus  int               `json:"response_status"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	// the start of the response body, or why no response was received
+	ResponseBody string `json:"response_body"`
+}
+
+// toHookDelivery convert models.HookTask and its attempts to HookDelivery
+func toHookDelivery(t *models.HookTask, attempts []*models.HookTaskAttempt) *HookDelivery {
+	delivery := &HookDelivery{
+		ID:          t.ID,
+		UUID:        t.UUID,
+		Event:       string(t.EventType),
+		IsDelivered: t.IsDelivered,
+		IsSucceed:   t.IsSucceed,
+		Attempts:    t.Attempts,
+		History:     make([]*HookDeliveryAttempt, len(attempts)),
+	}
+	if t.Delivered > 0 {
+		delivered := time.Unix(0, t.Delivered)
+		delivery.Delivered = &delivered
+	}
+	if !t.IsDelivered && t.NextDeliveryUnix > 0 {
+		delivery.NextDelivery = t.NextDeliveryUnix.AsTimePtr()
+	}
+	if t.RequestInfo != nil {
+		delivery.RequestHeaders = t.RequestInfo.Headers
+	}
+	if t.ResponseInfo != nil {
+		delivery.ResponseStatus = t.ResponseInfo.Status
+		delivery.ResponseHeaders = t.ResponseInfo.Headers
+		delivery.ResponseBody = t.ResponseInfo.Body
+	}
+
+	for i, a := range attempts {
+		delivered := time.Unix(0, a.Delivered)
+		attempt := &HookDeliveryAttempt{
+			Attempt:   a.Attempt,
+			IsSucceed: a.IsSucceed,
+			Delivered: &delivered,
+		}
+		if a.RequestInfo != nil {
+			attempt.RequestHeaders = a.RequestInfo.Headers
+		}
+		if a.ResponseInfo != nil {
+			attempt.ResponseStatus = a.ResponseInfo.Status
+			attempt.ResponseHeaders = a.ResponseInfo.Headers
+			attempt.ResponseBody = a.ResponseInfo.Body
+		}
+		delivery.History[i] = attempt
+	}
+	return delivery
+}
+
+// ListHookDeliveries list the recent deliveries of a repo's hook
+func ListHookDeliveries(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/{id}/deliveries repository repoListHookDeliveries
+	// ---
+	// summary: List the recent deliveries of a hook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookDeliveryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	tasks, err := hook.History(page)
+	if err != nil {
+		ctx.Error(500, "History", err)
+		return
+	}
+
+	taskIDs := make([]int64, len(tasks))
+	for i := range tasks {
+		taskIDs[i] = tasks[i].ID
+	}
+	attempts, err := models.GetHookTaskAttempts(taskIDs)
+	if err != nil {
+		ctx.Error(500, "GetHookTaskAttempts", err)
+		return
+	}
+
+	deliveries := make([]*HookDelivery, len(tasks))
+	for i := range tasks {
+		deliveries[i] = toHookDelivery(tasks[i], attempts[tasks[i].ID])
+	}
+	ctx.JSON(200, &deliveries)
+}
+
+// RedeliverHook delivers the payload of a past delivery of a repo's hook again
+func RedeliverHook(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/deliveries/{delivery}/redeliver repository repoRedeliverHook
+	// ---
+	// summary: Deliver the payload of a past delivery of a hook again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: delivery
+	//   in: path
+	//   description: id of the delivery to deliver again
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/HookDelivery"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+
+	task, err := models.GetHookTaskByHookID(hook.ID, ctx.ParamsInt64(":delivery"))
+	if err != nil {
+		if models.IsErrHookTaskNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetHookTaskByHookID", err)
+		}
+		return
+	}
+
+	redelivery, err := models.RedeliverHookTask(task)
+	if err != nil {
+		ctx.Error(500, "RedeliverHookTask", err)
+		return
+	}
+	ctx.JSON(202, toHookDelivery(redelivery, nil))
+}
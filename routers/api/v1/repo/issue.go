@@ -49,11 +49,13 @@ func ListIssues(ctx *context.APIContext) {
 	//   type: integer
 	// - name: q
 	//   in: query
-	//   description: search string
+	//   description: search string, "author:", "assignee:", "mentions:", "reviewed-by:", "review-requested:" and "involves:" followed by a username or "me" filter by involvement
 	//   type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	var isClosed util.OptionalBool
 	switch ctx.Query("state") {
 	case "closed":
@@ -70,21 +72,36 @@ func ListIssues(ctx *context.APIContext) {
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
+	query, err := models.ParseIssueSearchQuery(keyword, ctx.User)
+	if err != nil {
+		if models.IsErrInvalidIssueSearchQuery(err) {
+			ctx.Error(422, "", err)
+			return
+		} else if !models.IsErrUserNotExist(err) {
+			ctx.Error(500, "ParseIssueSearchQuery", err)
+			return
+		}
+		// nobody is involved in the issues of an unknown user
+		ctx.JSON(200, []*api.Issue{})
+		return
+	}
+
 	var issueIDs []int64
-	var err error
-	if len(keyword) > 0 {
-		issueIDs, err = indexer.SearchIssuesByKeyword(ctx.Repo.Repository.ID, keyword)
+	if len(query.Keyword) > 0 {
+		issueIDs, err = indexer.SearchIssuesByKeyword(ctx.Repo.Repository.ID, query.Keyword)
 	}
 
 	// Only fetch the issues if we either don't have a keyword or the search returned issues
 	// This would otherwise return all issues if no issues were found by the search.
-	if len(keyword) == 0 || len(issueIDs) > 0 {
+	if len(query.Keyword) == 0 || len(issueIDs) > 0 {
 		issues, err = models.Issues(&models.IssuesOptions{
-			RepoIDs:  []int64{ctx.Repo.Repository.ID},
-			Page:     ctx.QueryInt("page"),
-			PageSize: setting.UI.IssuePagingNum,
-			IsClosed: isClosed,
-			IssueIDs: issueIDs,
+			RepoIDs:     []int64{ctx.Repo.Repository.ID},
+			Page:        ctx.QueryInt("page"),
+			PageSize:    setting.UI.IssuePagingNum,
+			IsClosed:    isClosed,
+			IssueIDs:    issueIDs,
+			InvolvedID:  query.InvolvedID,
+			Involvement: query.Involvement,
 		})
 	}
 
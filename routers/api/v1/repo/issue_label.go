@@ -6,8 +6,12 @@
 package repo
 
 import (
+	"fmt"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 
 	api "code.gitea.io/sdk/gitea"
 )
@@ -317,3 +321,141 @@ func ClearIssueLabels(ctx *context.APIContext) {
 
 	ctx.Status(204)
 }
+
+// BulkIssueLabelsResult is the outcome of a bulk label update for one issue
+type BulkIssueLabelsResult struct {
+	Index   int64        `json:"index"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+	Labels  []*api.Label `json:"labels,omitempty"`
+}
+
+// BulkUpdateIssueLabels add and remove labels of several issues
+func BulkUpdateIssueLabels(ctx *context.APIContext, form auth.BulkIssueLabelsForm) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/labels/bulk issue issueBulkUpdateLabels
+	// ---
+	// summary: Add and remove labels of several issues
+	// description: Every issue is updated in its own transaction, so one failing issue does not affect the others.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BulkIssueLabelsForm"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BulkIssueLabelsResultList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if len(form.Add) == 0 && len(form.Remove) == 0 {
+		ctx.Error(422, "", "no labels to add or remove")
+		return
+	}
+
+	removeIDs := make(map[int64]bool, len(form.Remove))
+	for _, id := range form.Remove {
+		removeIDs[id] = true
+	}
+	for _, id := range form.Add {
+		if removeIDs[id] {
+			ctx.Error(422, "", fmt.Sprintf("label %d cannot be both added and removed", id))
+			return
+		}
+	}
+
+	// Reject unknown labels before touching any issue.
+	if _, ok := getBulkLabels(ctx, form.Add); !ok {
+		return
+	}
+	if _, ok := getBulkLabels(ctx, form.Remove); !ok {
+		return
+	}
+
+	results := make([]*BulkIssueLabelsResult, 0, len(form.Issues))
+	for _, index := range form.Issues {
+		result := &BulkIssueLabelsResult{Index: index}
+		results = append(results, result)
+
+		issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, index)
+		if err != nil {
+			if !models.IsErrIssueNotExist(err) {
+				log.Error(4, "GetIssueByIndex: %v", err)
+			}
+			result.Error = err.Error()
+			continue
+		}
+
+		if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+			result.Error = "permission denied"
+			continue
+		}
+
+		// Labels are reloaded for every issue, as updating an issue changes
+		// their issue counters.
+		toAdd, ok := getBulkLabels(ctx, form.Add)
+		if !ok {
+			return
+		}
+		toRemove, ok := getBulkLabels(ctx, form.Remove)
+		if !ok {
+			return
+		}
+
+		if err = issue.UpdateLabels(ctx.User, toAdd, toRemove); err != nil {
+			log.Error(4, "UpdateLabels [issue: %d]: %v", issue.ID, err)
+			result.Error = err.Error()
+			continue
+		}
+
+		labels, err := models.GetLabelsByIssueID(issue.ID)
+		if err != nil {
+			ctx.Error(500, "GetLabelsByIssueID", err)
+			return
+		}
+
+		result.Success = true
+		result.Labels = make([]*api.Label, len(labels))
+		for i := range labels {
+			result.Labels[i] = labels[i].APIFormat()
+		}
+	}
+
+	ctx.JSON(200, results)
+}
+
+// getBulkLabels returns the labels of the repository with given IDs, writing
+// an error response if any of them does not exist.
+func getBulkLabels(ctx *context.APIContext, ids []int64) ([]*models.Label, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+	labels, err := models.GetLabelsInRepoByIDs(ctx.Repo.Repository.ID, ids)
+	if err != nil {
+		ctx.Error(500, "GetLabelsInRepoByIDs", err)
+		return nil, false
+	}
+	found := make(map[int64]bool, len(labels))
+	for _, label := range labels {
+		found[label.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			ctx.Error(422, "", models.ErrLabelNotExist{LabelID: id, RepoID: ctx.Repo.Repository.ID})
+			return nil, false
+		}
+	}
+	return labels, true
+}
@@ -20,6 +20,27 @@ import (
 	api "code.gitea.io/sdk/gitea"
 )
 
+// DraftablePullRequest represents a pull request, which may be a draft
+type DraftablePullRequest struct {
+	*api.PullRequest
+	// whether the pull request is a draft, not ready for review nor to be merged
+	IsDraft bool `json:"is_draft"`
+}
+
+func toDraftablePullRequest(pr *models.PullRequest) *DraftablePullRequest {
+	return &DraftablePullRequest{
+		PullRequest: pr.APIFormat(),
+		IsDraft:     pr.IsDraft,
+	}
+}
+
+// CreateDraftablePullRequestOption options when creating a pull request, which may be a draft
+type CreateDraftablePullRequestOption struct {
+	api.CreatePullRequestOption
+	// whether to open the pull request as a draft
+	Draft bool `json:"draft"`
+}
+
 // ListPullRequests returns a list of all PRs
 func ListPullRequests(ctx *context.APIContext, form api.ListPullRequestsOptions) {
 	// swagger:operation GET /repos/{owner}/{repo}/pulls repository repoListPullRequests
@@ -81,7 +102,7 @@ func ListPullRequests(ctx *context.APIContext, form api.ListPullRequestsOptions)
 		return
 	}
 
-	apiPrs := make([]*api.PullRequest, len(prs))
+	apiPrs := make([]*DraftablePullRequest, len(prs))
 	for i := range prs {
 		if err = prs[i].LoadIssue(); err != nil {
 			ctx.Error(500, "LoadIssue", err)
@@ -99,7 +120,7 @@ func ListPullRequests(ctx *context.APIContext, form api.ListPullRequestsOptions)
 			ctx.Error(500, "GetHeadRepo", err)
 			return
 		}
-		apiPrs[i] = prs[i].APIFormat()
+		apiPrs[i] = toDraftablePullRequest(prs[i])
 	}
 
 	ctx.SetLinkHeader(int(maxResults), models.ItemsPerPage)
@@ -151,11 +172,11 @@ func GetPullRequest(ctx *context.APIContext) {
 		ctx.Error(500, "GetHeadRepo", err)
 		return
 	}
-	ctx.JSON(200, pr.APIFormat())
+	ctx.JSON(200, toDraftablePullRequest(pr))
 }
 
 // CreatePullRequest does what it says
-func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption) {
+func CreatePullRequest(ctx *context.APIContext, form CreateDraftablePullRequestOption) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls repository repoCreatePullRequest
 	// ---
 	// summary: Create a pull request
@@ -177,7 +198,7 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 	// - name: body
 	//   in: body
 	//   schema:
-	//     "$ref": "#/definitions/CreatePullRequestOption"
+	//     "$ref": "#/definitions/CreateDraftablePullRequestOption"
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/PullRequest"
@@ -189,7 +210,7 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 	)
 
 	// Get repo/branch information
-	headUser, headRepo, headGitRepo, prInfo, baseBranch, headBranch := parseCompareInfo(ctx, form)
+	headUser, headRepo, headGitRepo, prInfo, baseBranch, headBranch := parseCompareInfo(ctx, form.CreatePullRequestOption)
 	if ctx.Written() {
 		return
 	}
@@ -274,6 +295,7 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 		BaseRepo:     repo,
 		MergeBase:    prInfo.MergeBase,
 		Type:         models.PullRequestGitea,
+		IsDraft:      form.Draft,
 	}
 
 	// Get all assignee IDs
@@ -298,11 +320,14 @@ func CreatePullRequest(ctx *context.APIContext, form api.CreatePullRequestOption
 		ctx.Error(500, "PushToBaseRepo", err)
 		return
 	}
+	if err := pr.RequestCodeOwnerReviews(); err != nil {
+		log.Error(4, "RequestCodeOwnerReviews [pr_id: %d]: %v", pr.ID, err)
+	}
 
 	notification.NotifyNewPullRequest(pr)
 
 	log.Trace("Pull request created: %d/%d", repo.ID, prIssue.ID)
-	ctx.JSON(201, pr.APIFormat())
+	ctx.JSON(201, toDraftablePullRequest(pr))
 }
 
 // EditPullRequest does what it says
@@ -446,7 +471,7 @@ func EditPullRequest(ctx *context.APIContext, form api.EditPullRequestOption) {
 	}
 
 	// TODO this should be 200, not 201
-	ctx.JSON(201, pr.APIFormat())
+	ctx.JSON(201, toDraftablePullRequest(pr))
 }
 
 // IsPullRequestMerged checks if a PR exists given an index
@@ -494,6 +519,53 @@ func IsPullRequestMerged(ctx *context.APIContext) {
 	ctx.Status(404)
 }
 
+// GetPullRequestDivergence returns how far the head branch of a PR diverged from its base branch
+func GetPullRequestDivergence(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/divergence repository repoGetPullRequestDivergence
+	// ---
+	// summary: Get how far the head branch of a pull request diverged from its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestDivergence"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	divergence, err := pr.GetDivergence()
+	if err != nil {
+		ctx.Error(500, "GetDivergence", err)
+		return
+	}
+	ctx.JSON(200, divergence)
+}
+
 // MergePullRequest merges a PR given an index
 func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge repository repoMergePullRequest
@@ -554,7 +626,7 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 		return
 	}
 
-	if !pr.CanAutoMerge() || pr.HasMerged || pr.IsWorkInProgress() {
+	if !pr.CanAutoMerge() || pr.HasMerged || pr.IsWorkInProgress() || pr.IsDraft {
 		ctx.Status(405)
 		return
 	}
@@ -565,12 +637,7 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
-		}
+		message = pr.GetMergeMessage(models.MergeStyle(form.Do))
 	}
 
 	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
@@ -591,6 +658,70 @@ func MergePullRequest(ctx *context.APIContext, form auth.MergePullRequestForm) {
 	ctx.Status(200)
 }
 
+// MarkPullRequestReady takes a draft pull request out of the draft state
+func MarkPullRequestReady(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/ready repository repoMarkPullRequestReady
+	// ---
+	// summary: Mark a draft pull request as ready for review
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/empty"
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(500, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(500, "LoadIssue", err)
+		return
+	}
+
+	if !pr.Issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWrite(models.UnitTypePullRequests) {
+		ctx.Status(403)
+		return
+	}
+
+	if err = pr.MarkReadyForReview(); err != nil {
+		if models.IsErrPullRequestNotOpen(err) {
+			ctx.Error(409, "MarkReadyForReview", err)
+		} else {
+			ctx.Error(500, "MarkReadyForReview", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
+
 func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption) (*models.User, *models.Repository, *git.Repository, *git.PullRequestInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
 
@@ -100,8 +100,12 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 	// swagger:operation POST /repos/{owner}/{repo}/releases repository repoCreateRelease
 	// ---
 	// summary: Create a release
+	// description: Assets can be uploaded together with the release by sending
+	//   the release options as multipart/form-data fields (tag_name, target,
+	//   title, note, is_draft and is_prerelease) and the files as "attachment".
 	// consumes:
 	// - application/json
+	// - multipart/form-data
 	// produces:
 	// - application/json
 	// parameters:
@@ -122,6 +126,14 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Release"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	uploads, ok := openReleaseUploads(ctx)
+	if !ok {
+		return
+	}
+	defer closeReleaseUploads(uploads)
+
 	rel, err := models.GetRelease(ctx.Repo.Repository.ID, form.TagName)
 	if err != nil {
 		if !models.IsErrReleaseNotExist(err) {
@@ -144,6 +156,8 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 		if err := models.CreateRelease(ctx.Repo.GitRepo, rel, nil); err != nil {
 			if models.IsErrReleaseAlreadyExist(err) {
 				ctx.Status(409)
+			} else if models.IsErrProtectedTagName(err) {
+				ctx.Error(422, "", err)
 			} else {
 				ctx.Error(500, "CreateRelease", err)
 			}
@@ -165,9 +179,26 @@ func CreateRelease(ctx *context.APIContext, form api.CreateReleaseOption) {
 		rel.Publisher = ctx.User
 
 		if err = models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, nil); err != nil {
-			ctx.ServerError("UpdateRelease", err)
+			if models.IsErrProtectedTagName(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.ServerError("UpdateRelease", err)
+			}
+			return
+		}
+	}
+
+	for _, upload := range uploads {
+		attach, err := models.NewReleaseAttachment(rel, upload.name, upload.buf, upload.file)
+		if err != nil {
+			if models.IsErrReleaseAttachmentAlreadyExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "NewReleaseAttachment", err)
+			}
 			return
 		}
+		rel.Attachments = append(rel.Attachments, attach)
 	}
 	ctx.JSON(201, rel.APIFormat())
 }
@@ -205,6 +236,8 @@ func EditRelease(ctx *context.APIContext, form api.EditReleaseOption) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Release"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	id := ctx.ParamsInt64(":id")
 	rel, err := models.GetReleaseByID(id)
 	if err != nil && !models.IsErrReleaseNotExist(err) {
@@ -236,7 +269,11 @@ func EditRelease(ctx *context.APIContext, form api.EditReleaseOption) {
 		rel.IsPrerelease = *form.IsPrerelease
 	}
 	if err := models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, nil); err != nil {
-		ctx.Error(500, "UpdateRelease", err)
+		if models.IsErrProtectedTagName(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "UpdateRelease", err)
+		}
 		return
 	}
 
@@ -6,6 +6,7 @@ package repo
 
 import (
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
@@ -16,6 +17,82 @@ import (
 	api "code.gitea.io/sdk/gitea"
 )
 
+// readAttachmentHead reads the beginning of an uploaded file and checks its
+// content type against the allowed attachment types. The returned buffer has
+// to be stored in front of the rest of the file.
+func readAttachmentHead(file multipart.File) ([]byte, error) {
+	buf := make([]byte, 1024)
+	n, _ := file.Read(buf)
+	if n > 0 {
+		buf = buf[:n]
+	}
+
+	// Check if the filetype is allowed by the settings
+	fileType := http.DetectContentType(buf)
+
+	allowedTypes := strings.Split(setting.AttachmentAllowedTypes, ",")
+	for _, t := range allowedTypes {
+		t := strings.Trim(t, " ")
+		if t == "*/*" || t == fileType {
+			return buf, nil
+		}
+	}
+	return nil, errors.New("File type is not allowed")
+}
+
+// releaseUpload is a file uploaded together with a new release.
+type releaseUpload struct {
+	name string
+	buf  []byte
+	file multipart.File
+}
+
+func closeReleaseUploads(uploads []*releaseUpload) {
+	for _, upload := range uploads {
+		upload.file.Close()
+	}
+}
+
+// openReleaseUploads opens and checks the files uploaded as "attachment"
+// together with a release, before the release itself is stored.
+func openReleaseUploads(ctx *context.APIContext) ([]*releaseUpload, bool) {
+	if ctx.Req.MultipartForm == nil || len(ctx.Req.MultipartForm.File["attachment"]) == 0 {
+		return nil, true
+	}
+	if !setting.AttachmentEnabled {
+		ctx.Error(404, "AttachmentEnabled", errors.New("attachment is not enabled"))
+		return nil, false
+	}
+
+	headers := ctx.Req.MultipartForm.File["attachment"]
+	uploads := make([]*releaseUpload, 0, len(headers))
+	for _, header := range headers {
+		for _, upload := range uploads {
+			if upload.name == header.Filename {
+				closeReleaseUploads(uploads)
+				ctx.Error(422, "", models.ErrReleaseAttachmentAlreadyExist{Name: header.Filename})
+				return nil, false
+			}
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			closeReleaseUploads(uploads)
+			ctx.Error(500, "Open", err)
+			return nil, false
+		}
+		buf, err := readAttachmentHead(file)
+		if err != nil {
+			file.Close()
+			closeReleaseUploads(uploads)
+			ctx.Error(400, "DetectContentType", err)
+			return nil, false
+		}
+		uploads = append(uploads, &releaseUpload{header.Filename, buf, file})
+	}
+	return uploads, true
+}
+
 // GetReleaseAttachment gets a single attachment of the release
 func GetReleaseAttachment(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id} repository repoGetReleaseAttachment
@@ -147,6 +224,8 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Attachment"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	// Check if attachments are enabled
 	if !setting.AttachmentEnabled {
@@ -170,27 +249,9 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	}
 	defer file.Close()
 
-	buf := make([]byte, 1024)
-	n, _ := file.Read(buf)
-	if n > 0 {
-		buf = buf[:n]
-	}
-
-	// Check if the filetype is allowed by the settings
-	fileType := http.DetectContentType(buf)
-
-	allowedTypes := strings.Split(setting.AttachmentAllowedTypes, ",")
-	allowed := false
-	for _, t := range allowedTypes {
-		t := strings.Trim(t, " ")
-		if t == "*/*" || t == fileType {
-			allowed = true
-			break
-		}
-	}
-
-	if !allowed {
-		ctx.Error(400, "DetectContentType", errors.New("File type is not allowed"))
+	buf, err := readAttachmentHead(file)
+	if err != nil {
+		ctx.Error(400, "DetectContentType", err)
 		return
 	}
 
@@ -200,14 +261,13 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	}
 
 	// Create a new attachment and save the file
-	attach, err := models.NewAttachment(filename, buf, file)
+	attach, err := models.NewReleaseAttachment(release, filename, buf, file)
 	if err != nil {
-		ctx.Error(500, "NewAttachment", err)
-		return
-	}
-	attach.ReleaseID = release.ID
-	if err := models.UpdateAttachment(attach); err != nil {
-		ctx.Error(500, "UpdateAttachment", err)
+		if models.IsErrReleaseAttachmentAlreadyExist(err) {
+			ctx.Error(422, "", err)
+		} else {
+			ctx.Error(500, "NewReleaseAttachment", err)
+		}
 		return
 	}
 	ctx.JSON(201, attach.APIFormat())
@@ -218,10 +278,13 @@ func EditReleaseAttachment(ctx *context.APIContext, form api.EditAttachmentOptio
 	// swagger:operation PATCH /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id} repository repoEditReleaseAttachment
 	// ---
 	// summary: Edit a release attachment
+	// description: The content of the attachment is replaced when a new file
+	//   is uploaded as "attachment" in a multipart/form-data request.
 	// produces:
 	// - application/json
 	// consumes:
 	// - application/json
+	// - multipart/form-data
 	// parameters:
 	// - name: owner
 	//   in: path
@@ -252,10 +315,12 @@ func EditReleaseAttachment(ctx *context.APIContext, form api.EditAttachmentOptio
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Attachment"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	// Check if release exists an load release
 	releaseID := ctx.ParamsInt64(":id")
-	attachID := ctx.ParamsInt64(":attachment")
+	attachID := ctx.ParamsInt64(":asset")
 	attach, err := models.GetAttachmentByID(attachID)
 	if err != nil {
 		ctx.Error(500, "GetAttachmentByID", err)
@@ -266,12 +331,34 @@ func EditReleaseAttachment(ctx *context.APIContext, form api.EditAttachmentOptio
 		return
 	}
 	// FIXME Should prove the existence of the given repo, but results in unnecessary database requests
-	if form.Name != "" {
-		attach.Name = form.Name
+	if form.Name != "" && form.Name != attach.Name {
+		if err := models.RenameReleaseAttachment(attach, form.Name); err != nil {
+			if models.IsErrReleaseAttachmentAlreadyExist(err) {
+				ctx.Error(422, "", err)
+			} else {
+				ctx.Error(500, "RenameReleaseAttachment", err)
+			}
+			return
+		}
 	}
 
-	if err := models.UpdateAttachment(attach); err != nil {
-		ctx.Error(500, "UpdateAttachment", attach)
+	file, _, err := ctx.GetFile("attachment")
+	switch err {
+	case nil:
+		defer file.Close()
+		buf, err := readAttachmentHead(file)
+		if err != nil {
+			ctx.Error(400, "DetectContentType", err)
+			return
+		}
+		if err = attach.ReplaceContent(buf, file); err != nil {
+			ctx.Error(500, "ReplaceContent", err)
+			return
+		}
+	case http.ErrMissingFile, http.ErrNotMultipart:
+	default:
+		ctx.Error(500, "GetFile", err)
+		return
 	}
 	ctx.JSON(201, attach.APIFormat())
 }
@@ -312,7 +399,7 @@ func DeleteReleaseAttachment(ctx *context.APIContext) {
 
 	// Check if release exists an load release
 	releaseID := ctx.ParamsInt64(":id")
-	attachID := ctx.ParamsInt64(":attachment")
+	attachID := ctx.ParamsInt64(":asset")
 	attach, err := models.GetAttachmentByID(attachID)
 	if err != nil {
 		ctx.Error(500, "GetAttachmentByID", err)
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/v1/convert"
+	"code.gitea.io/gitea/services/migrate"
 
 	api "code.gitea.io/sdk/gitea"
 )
@@ -392,6 +393,13 @@ func Migrate(ctx *context.APIContext, form auth.MigrateRepoForm) {
 		return
 	}
 
+	if form.Issues {
+		if _, _, err := migrate.ParseGithubURL(form.CloneAddr); err != nil {
+			ctx.Error(422, "", "Issues can only be imported from github.com repositories.")
+			return
+		}
+	}
+
 	repo, err := models.MigrateRepository(ctx.User, ctxUser, models.MigrateRepoOptions{
 		Name:        form.RepoName,
 		Description: form.Description,
@@ -410,10 +418,55 @@ func Migrate(ctx *context.APIContext, form auth.MigrateRepoForm) {
 		return
 	}
 
+	if form.Issues {
+		if err = migrate.StartGithubIssueMigration(ctx.User, repo, form.CloneAddr, form.AuthToken); err != nil {
+			ctx.Error(500, "StartGithubIssueMigration", err)
+			return
+		}
+	}
+
 	log.Trace("Repository migrated: %s/%s", ctxUser.Name, form.RepoName)
 	ctx.JSON(201, repo.APIFormat(models.AccessModeAdmin))
 }
 
+// RetryIssueMigration resumes the failed import of the issues of a migrated repository
+func RetryIssueMigration(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issue_migration/retry repository repoRetryIssueMigration
+	// ---
+	// summary: Resume the failed import of the issues of a migrated repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/empty"
+	if err := migrate.RetryIssueMigration(ctx.Repo.Repository); err != nil {
+		if models.IsErrIssueMigrationNotExist(err) {
+			ctx.Status(404)
+		} else if models.IsErrIssueMigrationNotFailed(err) {
+			ctx.Error(409, "", err)
+		} else {
+			ctx.Error(500, "RetryIssueMigration", err)
+		}
+		return
+	}
+	ctx.Status(204)
+}
+
 // Get one repository
 func Get(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo} repository repoGet
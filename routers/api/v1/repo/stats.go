@@ -0,0 +1,178 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	api "code.gitea.io/sdk/gitea"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/repository/contributors"
+)
+
+// contributorStatsWaitTimeout is how long a request waits for the statistics
+// being computed before answering with the previous ones.
+const contributorStatsWaitTimeout = 2 * time.Second
+
+// ContributorStats represents the changes of a contributor over a time range
+type ContributorStats struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// user the email belongs to, if any
+	User      *api.User `json:"user"`
+	Commits   int       `json:"commits"`
+	Additions int       `json:"additions"`
+	Deletions int       `json:"deletions"`
+}
+
+// RepoContributorStats represents the contributor statistics of a repository
+type RepoContributorStats struct {
+	// whether the statistics of the latest commit are still being computed,
+	// the statistics of the previous commit are returned in the meantime
+	Pending bool `json:"pending"`
+	// commit the statistics are computed for, empty if none has been computed yet
+	CommitID     string              `json:"commit_id"`
+	From         *time.Time          `json:"from,omitempty"`
+	To           *time.Time          `json:"to,omitempty"`
+	Contributors []*ContributorStats `json:"contributors"`
+}
+
+// GetContributorStats returns the commits, additions and deletions of each
+// contributor to the default branch
+func GetContributorStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/contributors repository repoGetContributorStats
+	// ---
+	// summary: Get the commits, additions and deletions of each contributor to the default branch
+	// description: The statistics are computed in the background. While they are, the
+	//              statistics of the previous commit of the default branch are returned
+	//              with a 202 status. The changes are summed up by day in UTC, the days
+	//              overlapping the from and to range are counted in full.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: from
+	//   in: query
+	//   description: time in RFC 3339 format, only the commits of the UTC days ending after it are counted, each of these days in full
+	//   type: string
+	//   format: date-time
+	// - name: to
+	//   in: query
+	//   description: time in RFC 3339 format, only the commits of the UTC days starting before it are counted, each of these days in full
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoContributorStats"
+	//   "202":
+	//     "$ref": "#/responses/RepoContributorStats"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	if ctx.Repo.Repository.IsBare {
+		ctx.Status(404)
+		return
+	}
+
+	stats := &RepoContributorStats{}
+	var from, to time.Time
+	var err error
+	if from, err = parseTimeQuery(ctx, "from"); err != nil {
+		ctx.Error(422, "", err)
+		return
+	} else if !from.IsZero() {
+		stats.From = &from
+	}
+	if to, err = parseTimeQuery(ctx, "to"); err != nil {
+		ctx.Error(422, "", err)
+		return
+	} else if !to.IsZero() {
+		stats.To = &to
+	}
+
+	commitID, err := ctx.Repo.GitRepo.GetBranchCommitID(ctx.Repo.Repository.DefaultBranch)
+	if err != nil {
+		ctx.Error(500, "GetBranchCommitID", err)
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	summed, statsCommitID, status := contributors.GetStats(repo.ID, repo.RepoPath(), commitID)
+	if status == contributors.StatusPending {
+		status, err = contributors.Wait(repo.ID, commitID, contributorStatsWaitTimeout)
+		switch status {
+		case contributors.StatusFailed:
+			ctx.Error(500, "ContributorStats", err)
+			return
+		case contributors.StatusReady:
+			summed, statsCommitID, status = contributors.GetStats(repo.ID, repo.RepoPath(), commitID)
+		}
+	}
+	stats.Pending = status == contributors.StatusPending
+	stats.CommitID = statsCommitID
+
+	list := summed.Contributors(from, to)
+	emails := make([]string, len(list))
+	for i, contributor := range list {
+		emails[i] = contributor.Email
+	}
+	users, err := models.GetUsersByEmails(emails)
+	if err != nil {
+		ctx.Error(500, "GetUsersByEmails", err)
+		return
+	}
+
+	for _, contributor := range list {
+		var user *api.User
+		if u, ok := users[strings.ToLower(contributor.Email)]; ok {
+			user = u.APIFormat()
+		}
+		stats.Contributors = append(stats.Contributors, &ContributorStats{
+			Name:      contributor.Name,
+			Email:     contributor.Email,
+			User:      user,
+			Commits:   contributor.Commits,
+			Additions: contributor.Additions,
+			Deletions: contributor.Deletions,
+		})
+	}
+	if stats.Contributors == nil {
+		stats.Contributors = []*ContributorStats{}
+	}
+
+	if stats.Pending {
+		ctx.JSON(202, stats)
+	} else {
+		ctx.JSON(200, stats)
+	}
+}
+
+// parseTimeQuery returns the time of the query parameter, which is zero if it
+// is not set.
+func parseTimeQuery(ctx *context.APIContext, name string) (time.Time, error) {
+	value := ctx.Query(name)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q: %v", name, value, err)
+	}
+	return t, nil
+}
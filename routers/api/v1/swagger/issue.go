@@ -5,6 +5,8 @@
 package swagger
 
 import (
+	"code.gitea.io/gitea/routers/api/v1/repo"
+
 	api "code.gitea.io/sdk/gitea"
 )
 
@@ -50,6 +52,13 @@ type swaggerResponseLabelList struct {
 	Body []api.Label `json:"body"`
 }
 
+// BulkIssueLabelsResultList
+// swagger:response BulkIssueLabelsResultList
+type swaggerResponseBulkIssueLabelsResultList struct {
+	// in:body
+	Body []repo.BulkIssueLabelsResult `json:"body"`
+}
+
 // Milestone
 // swagger:response Milestone
 type swaggerResponseMilestone struct {
@@ -6,6 +6,7 @@ package swagger
 
 import (
 	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/routers/api/v1/repo"
 	api "code.gitea.io/sdk/gitea"
 )
 
@@ -42,6 +43,8 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	IssueLabelsOption api.IssueLabelsOption
+	// in:body
+	BulkIssueLabelsForm auth.BulkIssueLabelsForm
 
 	// in:body
 	CreateKeyOption api.CreateKeyOption
@@ -67,6 +70,8 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreatePullRequestOption api.CreatePullRequestOption
 	// in:body
+	CreateDraftablePullRequestOption repo.CreateDraftablePullRequestOption
+	// in:body
 	EditPullRequestOption api.EditPullRequestOption
 
 	// in:body
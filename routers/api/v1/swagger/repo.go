@@ -5,6 +5,9 @@
 package swagger
 
 import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/api/v1/admin"
+	"code.gitea.io/gitea/routers/api/v1/repo"
 	api "code.gitea.io/sdk/gitea"
 )
 
@@ -36,6 +39,13 @@ type swaggerResponseBranchList struct {
 	Body []api.Branch `json:"body"`
 }
 
+// MergedBranchList
+// swagger:response MergedBranchList
+type swaggerResponseMergedBranchList struct {
+	// in:body
+	Body []repo.MergedBranch `json:"body"`
+}
+
 // Reference
 // swagger:response Reference
 type swaggerResponseReference struct {
@@ -64,6 +74,20 @@ type swaggerResponseHookList struct {
 	Body []api.Branch `json:"body"`
 }
 
+// HookDelivery
+// swagger:response HookDelivery
+type swaggerResponseHookDelivery struct {
+	// in:body
+	Body repo.HookDelivery `json:"body"`
+}
+
+// HookDeliveryList
+// swagger:response HookDeliveryList
+type swaggerResponseHookDeliveryList struct {
+	// in:body
+	Body []repo.HookDelivery `json:"body"`
+}
+
 // Release
 // swagger:response Release
 type swaggerResponseRelease struct {
@@ -82,14 +106,14 @@ type swaggerResponseReleaseList struct {
 // swagger:response PullRequest
 type swaggerResponsePullRequest struct {
 	// in:body
-	Body api.PullRequest `json:"body"`
+	Body repo.DraftablePullRequest `json:"body"`
 }
 
 // PullRequestList
 // swagger:response PullRequestList
 type swaggerResponsePullRequestList struct {
 	// in:body
-	Body []api.PullRequest `json:"body"`
+	Body []repo.DraftablePullRequest `json:"body"`
 }
 
 // Status
@@ -133,3 +157,45 @@ type swaggerResponseAttachment struct {
 	//in: body
 	Body api.Attachment `json:"body"`
 }
+
+// CommitList
+// swagger:response CommitList
+type swaggerResponseCommitList struct {
+	// in:body
+	Body []api.PayloadCommit `json:"body"`
+}
+
+// FileBlame
+// swagger:response FileBlame
+type swaggerResponseFileBlame struct {
+	// in:body
+	Body repo.FileBlame `json:"body"`
+}
+
+// PullRequestDivergence
+// swagger:response PullRequestDivergence
+type swaggerResponsePullRequestDivergence struct {
+	// in:body
+	Body models.PullRequestDivergence `json:"body"`
+}
+
+// Comparison
+// swagger:response Comparison
+type swaggerResponseComparison struct {
+	// in:body
+	Body models.Comparison `json:"body"`
+}
+
+// RepoHealthCheck
+// swagger:response RepoHealthCheck
+type swaggerResponseRepoHealthCheck struct {
+	// in:body
+	Body admin.RepoHealthCheck `json:"body"`
+}
+
+// RepoContributorStats
+// swagger:response RepoContributorStats
+type swaggerResponseRepoContributorStats struct {
+	// in:body
+	Body repo.RepoContributorStats `json:"body"`
+}
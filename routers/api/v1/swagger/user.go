@@ -6,6 +6,7 @@ package swagger
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/routers/api/v1/user"
 	api "code.gitea.io/sdk/gitea"
 )
 
@@ -42,3 +43,10 @@ type swaggerResponseUserHeatmapData struct {
 	// in:body
 	Body []models.UserHeatmapData `json:"body"`
 }
+
+// SessionList
+// swagger:response SessionList
+type swaggerResponseSessionList struct {
+	// in:body
+	Body []user.Session `json:"body"`
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Session represents a web session of a signed in user
+type Session struct {
+	ID        int64  `json:"id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	LastSeen time.Time `json:"last_seen_at"`
+	// whether it is the session the request is made with
+	Current bool `json:"current"`
+}
+
+// ListUserSessions lists the active sessions of the given user.
+func ListUserSessions(ctx *context.APIContext, u *models.User) {
+	sessions, err := models.ListUserSessions(u.ID)
+	if err != nil {
+		ctx.Error(500, "ListUserSessions", err)
+		return
+	}
+
+	apiSessions := make([]*Session, len(sessions))
+	for i, s := range sessions {
+		apiSessions[i] = &Session{
+			ID:        s.ID,
+			IP:        s.IP,
+			UserAgent: s.UserAgent,
+			Created:   s.CreatedUnix.AsTime(),
+			LastSeen:  s.LastSeenUnix.AsTime(),
+			Current:   s.IsSession(ctx.Session.ID()),
+		}
+	}
+	ctx.JSON(200, &apiSessions)
+}
+
+// DeleteUserSession revokes a session of the given user by ID. If it is the
+// session the request is made with, the request is signed out at once.
+func DeleteUserSession(ctx *context.APIContext, u *models.User, id int64) {
+	s, err := models.GetUserSessionByID(u.ID, id)
+	if err != nil {
+		if models.IsErrUserSessionNotExist(err) {
+			ctx.Status(404)
+		} else {
+			ctx.Error(500, "GetUserSessionByID", err)
+		}
+		return
+	}
+
+	if err = models.RevokeUserSession(u, s.ID); err != nil {
+		ctx.Error(500, "RevokeUserSession", err)
+		return
+	}
+	log.Trace("Session %d of user %s revoked by %s", s.ID, u.Name, ctx.User.Name)
+
+	if s.IsSession(ctx.Session.ID()) {
+		auth.SignOutSession(ctx.Context.Context, ctx.Session)
+		if err = models.DeleteUserSessionBySessionID(ctx.Session.ID()); err != nil {
+			log.Error(4, "DeleteUserSessionBySessionID: %v", err)
+		}
+	}
+	ctx.Status(204)
+}
+
+// DeleteUserSessions revokes all sessions of the given user.
+func DeleteUserSessions(ctx *context.APIContext, u *models.User) {
+	if err := models.RevokeUserSessions(u); err != nil {
+		ctx.Error(500, "RevokeUserSessions", err)
+		return
+	}
+	log.Trace("All sessions of user %s revoked by %s", u.Name, ctx.User.Name)
+
+	if u.ID == ctx.User.ID {
+		auth.SignOutSession(ctx.Context.Context, ctx.Session)
+		if err := models.DeleteUserSessionBySessionID(ctx.Session.ID()); err != nil {
+			log.Error(4, "DeleteUserSessionBySessionID: %v", err)
+		}
+	}
+	ctx.Status(204)
+}
+
+// ListMySessions list the active web sessions of the authenticated user
+func ListMySessions(ctx *context.APIContext) {
+	// swagger:operation GET /user/sessions user userCurrentListSessions
+	// ---
+	// summary: List the authenticated user's active web sessions
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SessionList"
+	ListUserSessions(ctx, ctx.User)
+}
+
+// DeleteMySession revokes a web session of the authenticated user
+func DeleteMySession(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/sessions/{id} user userCurrentDeleteSession
+	// ---
+	// summary: Revoke a web session of the authenticated user
+	// description: Revoking the session the request is made with signs it out.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the session to revoke
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	DeleteUserSession(ctx, ctx.User, ctx.ParamsInt64(":id"))
+}
+
+// DeleteMySessions revokes all web sessions of the authenticated user
+func DeleteMySessions(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/sessions user userCurrentDeleteSessions
+	// ---
+	// summary: Revoke all web sessions of the authenticated user
+	// description: Remember me cookies are invalidated as well, access tokens are kept.
+	// produces:
+	// - application/json
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	DeleteUserSessions(ctx, ctx.User)
+}
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/sdk/gitea"
 
 	"github.com/Unknwon/com"
@@ -57,6 +58,8 @@ func Search(ctx *context.APIContext) {
 	}
 	if opts.PageSize == 0 {
 		opts.PageSize = 10
+	} else if opts.PageSize > setting.UI.ExplorePagingNum {
+		opts.PageSize = setting.UI.ExplorePagingNum
 	}
 
 	users, _, err := models.SearchUsers(opts)
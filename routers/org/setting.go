@@ -25,10 +25,22 @@ const (
 	tplSettingsHooks base.TplName = "org/settings/hooks"
 )
 
+// optionalRepoUnits returns the units which could be disabled by default on
+// the repositories of an organization.
+func optionalRepoUnits() []models.Unit {
+	unitTypes := models.OptionalDefaultRepoUnits()
+	units := make([]models.Unit, len(unitTypes))
+	for i, tp := range unitTypes {
+		units[i] = models.Units[tp]
+	}
+	return units
+}
+
 // Settings render the main settings page
 func Settings(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("org.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+	ctx.Data["RepoUnits"] = optionalRepoUnits()
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -36,6 +48,7 @@ func Settings(ctx *context.Context) {
 func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 	ctx.Data["Title"] = ctx.Tr("org.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+	ctx.Data["RepoUnits"] = optionalRepoUnits()
 
 	if ctx.HasError() {
 		ctx.HTML(200, tplSettingsOptions)
@@ -43,6 +56,19 @@ func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 	}
 
 	org := ctx.Org.Organization
+	displayName := org.DisplayName()
+
+	// Owners must not lock themselves out of the organization.
+	if form.RequireTwoFactor && !org.RequireTwoFactor && !ctx.User.IsAdmin {
+		has, err := models.HasTwoFactorByUID(ctx.User.ID)
+		if err != nil {
+			ctx.ServerError("HasTwoFactorByUID", err)
+			return
+		} else if !has {
+			ctx.RenderWithErr(ctx.Tr("org.settings.require_two_factor_not_enrolled"), tplSettingsOptions, &form)
+			return
+		}
+	}
 
 	// Check if organization name has been changed.
 	if org.LowerName != strings.ToLower(form.Name) {
@@ -73,16 +99,29 @@ func SettingsPost(ctx *context.Context, form auth.UpdateOrgSettingForm) {
 
 	if ctx.User.IsAdmin {
 		org.MaxRepoCreation = form.MaxRepoCreation
+		org.MaxRepoSize = form.MaxRepoSize
 	}
 
 	org.FullName = form.FullName
 	org.Description = form.Description
 	org.Website = form.Website
 	org.Location = form.Location
+	org.RequireTwoFactor = form.RequireTwoFactor
+	org.DisabledRepoUnits = make([]models.UnitType, 0, len(models.OptionalDefaultRepoUnits()))
+	for _, tp := range models.OptionalDefaultRepoUnits() {
+		if !form.IsRepoUnitEnabled(tp) {
+			org.DisabledRepoUnits = append(org.DisabledRepoUnits, tp)
+		}
+	}
 	if err := models.UpdateUser(org); err != nil {
 		ctx.ServerError("UpdateUser", err)
 		return
 	}
+	if org.DisplayName() != displayName {
+		if err := org.RefreshGeneratedAvatar(); err != nil {
+			log.Error(4, "RefreshGeneratedAvatar[%d]: %v", org.ID, err)
+		}
+	}
 	log.Trace("Organization setting updated: %s", org.Name)
 	ctx.Flash.Success(ctx.Tr("org.settings.update_setting_success"))
 	ctx.Redirect(ctx.Org.OrgLink + "/settings")
@@ -50,3 +50,25 @@ func CanUserPush(ctx *macaron.Context) {
 		})
 	}
 }
+
+// CanUserPushProtectedFiles returns if user can push changes to protected files
+func CanUserPushProtectedFiles(ctx *macaron.Context) {
+	pbID := ctx.ParamsInt64(":pbid")
+	userID := ctx.ParamsInt64(":userid")
+
+	protectBranch, err := models.GetProtectedBranchByID(pbID)
+	if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	} else if protectBranch != nil {
+		ctx.JSON(200, map[string]interface{}{
+			"can_push": protectBranch.IsProtectedFilesWhitelisted(userID),
+		})
+	} else {
+		ctx.JSON(200, map[string]interface{}{
+			"can_push": false,
+		})
+	}
+}
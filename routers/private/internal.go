@@ -83,8 +83,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/repositories/:repoid/user/:userid/checkunituser", CheckUnitUser)
 		m.Get("/repositories/:repoid/has-keys/:keyid", HasDeployKey)
 		m.Get("/repositories/:repoid/wiki/init", InitWiki)
+		m.Get("/repositories/:repoid/size-limit", CheckRepoSizeLimit)
 		m.Post("/push/update", PushUpdate)
 		m.Get("/protectedbranch/:pbid/:userid", CanUserPush)
+		m.Get("/protectedbranch/:pbid/:userid/protected-files", CanUserPushProtectedFiles)
+		m.Get("/protectedtag/:repoid/:userid/*", CanUserPushTag)
 		m.Get("/repo/:owner/:repo", GetRepositoryByOwnerAndName)
 		m.Get("/branch/:id/*", GetProtectedBranchBy)
 		m.Get("/repository/:rid", GetRepository)
@@ -82,3 +82,28 @@ func GetActivePullRequest(ctx *macaron.Context) {
 
 	ctx.JSON(http.StatusOK, pr)
 }
+
+// CheckRepoSizeLimit checks if the repository can receive a push of the given size
+func CheckRepoSizeLimit(ctx *macaron.Context) {
+	repo, err := models.GetRepositoryByID(ctx.ParamsInt64(":repoid"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+
+	err = repo.CheckSizeLimit(ctx.QueryInt64("pushSize"))
+	if models.IsErrRepoSizeLimitExceeded(err) {
+		ctx.JSON(http.StatusOK, map[string]interface{}{
+			"exceeded": err,
+		})
+		return
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{})
+}
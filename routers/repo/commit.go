@@ -45,24 +45,19 @@ func Commits(ctx *context.Context) {
 	}
 	ctx.Data["PageIsViewCode"] = true
 
-	commitsCount, err := ctx.Repo.GetCommitsCount()
-	if err != nil {
-		ctx.ServerError("GetCommitsCount", err)
-		return
-	}
-
 	page := ctx.QueryInt("page")
 	if page <= 1 {
 		page = 1
 	}
-	ctx.Data["Page"] = paginater.New(int(commitsCount), git.CommitsRangeSize, page, 5)
 
-	// Both `git log branchName` and `git log commitId` work.
-	commits, err := ctx.Repo.Commit.CommitsByRange(page)
+	// Paging by the commit rather than the branch name keeps the pages and
+	// their count consistent while the branch moves on.
+	commits, commitsCount, err := ctx.Repo.Repository.GetCommitsPage(ctx.Repo.GitRepo, ctx.Repo.Commit.ID.String(), page, git.CommitsRangeSize)
 	if err != nil {
-		ctx.ServerError("CommitsByRange", err)
+		ctx.ServerError("GetCommitsPage", err)
 		return
 	}
+	ctx.Data["Page"] = paginater.New(commitsCount, git.CommitsRangeSize, page, 5)
 	commits = models.ValidateCommitsWithEmails(commits)
 	commits = models.ParseCommitsWithSignature(commits)
 	commits = models.ParseCommitsWithStatus(commits, ctx.Repo.Repository)
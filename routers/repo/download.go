@@ -6,15 +6,22 @@
 package repo
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"path"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/git"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // ServeData download file from io.Reader
@@ -34,6 +41,7 @@ func ServeData(ctx *context.Context, name string, reader io.Reader) error {
 	if base.IsTextFile(buf) || ctx.QueryBool("render") {
 		ctx.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	} else if base.IsImageFile(buf) || base.IsPDFFile(buf) {
+		ctx.Resp.Header().Set("Content-Type", http.DetectContentType(buf))
 		ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, name))
 	} else {
 		ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
@@ -55,6 +63,48 @@ func ServeBlob(ctx *context.Context, blob *git.Blob) error {
 	return ServeData(ctx, ctx.Repo.TreePath, dataRc)
 }
 
+// ServeBlobOrLFS download the content of the LFS object the git.Blob is a
+// pointer file of, or the git.Blob itself if it is not an LFS pointer or the
+// pointer query flag is set. It returns models.ErrLFSObjectNotExist if the
+// LFS object is missing.
+func ServeBlobOrLFS(ctx *context.Context, blob *git.Blob) error {
+	if !setting.LFS.StartServer || ctx.QueryBool("pointer") || blob.Size() > lfs.PointerSizeCutoff {
+		return ServeBlob(ctx, blob)
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return err
+	}
+	defer dataRc.Close()
+	buf, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		return err
+	}
+
+	pointer := lfs.IsPointerFile(buf)
+	if pointer == nil {
+		return ServeData(ctx, ctx.Repo.TreePath, bytes.NewReader(buf))
+	}
+
+	meta, err := ctx.Repo.Repository.GetLFSMetaObjectByOid(pointer.Oid)
+	if err != nil {
+		return err
+	}
+	contentStore := &lfs.ContentStore{BasePath: setting.LFS.ContentPath}
+	if !contentStore.Exists(meta) {
+		return models.ErrLFSObjectNotExist
+	}
+	lfsDataRc, err := contentStore.Get(meta, 0)
+	if err != nil {
+		return err
+	}
+	defer lfsDataRc.Close()
+
+	ctx.Resp.Header().Set("Content-Length", strconv.FormatInt(meta.Size, 10))
+	return ServeData(ctx, ctx.Repo.TreePath, lfsDataRc)
+}
+
 // SingleDownload download a file by repos path
 func SingleDownload(ctx *context.Context) {
 	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
@@ -66,8 +116,12 @@ func SingleDownload(ctx *context.Context) {
 		}
 		return
 	}
-	if err = ServeBlob(ctx, blob); err != nil {
-		ctx.ServerError("ServeBlob", err)
+	if err = ServeBlobOrLFS(ctx, blob); err != nil {
+		if err == models.ErrLFSObjectNotExist {
+			ctx.NotFound("ServeBlobOrLFS", nil)
+		} else {
+			ctx.ServerError("ServeBlobOrLFS", err)
+		}
 	}
 }
 
@@ -82,7 +136,11 @@ func DownloadByID(ctx *context.Context) {
 		}
 		return
 	}
-	if err = ServeBlob(ctx, blob); err != nil {
-		ctx.ServerError("ServeBlob", err)
+	if err = ServeBlobOrLFS(ctx, blob); err != nil {
+		if err == models.ErrLFSObjectNotExist {
+			ctx.NotFound("ServeBlobOrLFS", nil)
+		} else {
+			ctx.ServerError("ServeBlobOrLFS", err)
+		}
 	}
 }
@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -15,12 +17,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
@@ -189,6 +193,14 @@ func HTTP(ctx *context.Context) {
 		}
 
 		if !perm.CanAccess(accessMode, unitType) {
+			required, err := models.IsTwoFactorRequired(repo.MustOwner(), authUser)
+			if err != nil {
+				ctx.ServerError("IsTwoFactorRequired", err)
+				return
+			} else if required {
+				ctx.HandleText(http.StatusForbidden, "The organization requires two-factor authentication. Please enable it on the user security settings page")
+				return
+			}
 			ctx.HandleText(http.StatusForbidden, "User permission denied")
 			return
 		}
@@ -217,6 +229,10 @@ func HTTP(ctx *context.Context) {
 		}
 	}
 
+	if !checkGitHTTPRateLimit(ctx, authUser) {
+		return
+	}
+
 	HTTPBackend(ctx, &serviceConfig{
 		UploadPack:  true,
 		ReceivePack: true,
@@ -224,6 +240,66 @@ func HTTP(ctx *context.Context) {
 	})(ctx.Resp, ctx.Req.Request)
 }
 
+var (
+	gitHTTPLimitersOnce         sync.Once
+	anonymousGitHTTPLimiter     *ratelimit.Limiter
+	authenticatedGitHTTPLimiter *ratelimit.Limiter
+)
+
+func initGitHTTPLimiters() {
+	cfg := setting.Git.HTTPRateLimit
+	anonymousGitHTTPLimiter = ratelimit.NewLimiter(cfg.AnonymousRequestsPerMinute, cfg.AnonymousBurst)
+	if cfg.AuthenticatedRequestsPerMinute > 0 {
+		authenticatedGitHTTPLimiter = ratelimit.NewLimiter(cfg.AuthenticatedRequestsPerMinute, cfg.AuthenticatedBurst)
+	}
+}
+
+// checkGitHTTPRateLimit checks the request against the rate limit of its
+// user, or of its client IP if it is anonymous. It responds with a 429 and
+// returns false if the limit is exceeded.
+func checkGitHTTPRateLimit(ctx *context.Context, authUser *models.User) bool {
+	if !setting.Git.HTTPRateLimit.Enabled {
+		return true
+	}
+	gitHTTPLimitersOnce.Do(initGitHTTPLimiters)
+
+	if authUser == nil && ctx.IsSigned {
+		authUser = ctx.User
+	}
+
+	var allowed bool
+	var retryAfter time.Duration
+	if authUser != nil {
+		if authenticatedGitHTTPLimiter == nil {
+			return true
+		}
+		allowed, retryAfter = authenticatedGitHTTPLimiter.Allow(strconv.FormatInt(authUser.ID, 10))
+	} else {
+		allowed, retryAfter = anonymousGitHTTPLimiter.Allow(gitHTTPClientIP(ctx))
+	}
+	if allowed {
+		return true
+	}
+
+	ctx.Resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	ctx.HandleText(http.StatusTooManyRequests, "rate limit exceeded")
+	return false
+}
+
+// gitHTTPClientIP returns the IP address the anonymous requests of a client
+// are limited by. The X-Real-IP and X-Forwarded-For headers are only trusted
+// if configured so, as otherwise a client could pick a new IP per request.
+func gitHTTPClientIP(ctx *context.Context) string {
+	if setting.Git.HTTPRateLimit.TrustProxyHeaders {
+		return ctx.RemoteAddr()
+	}
+	host, _, err := net.SplitHostPort(ctx.Req.RemoteAddr)
+	if err != nil {
+		return ctx.Req.RemoteAddr
+	}
+	return host
+}
+
 type serviceConfig struct {
 	UploadPack  bool
 	ReceivePack bool
@@ -335,6 +411,21 @@ func hasAccess(service string, h serviceHandler, checkContentType bool) bool {
 	return getConfigSetting(service, h.dir)
 }
 
+// serviceArgs returns the git arguments to run the given service. Partial
+// clone is enabled for upload-pack when the installed Git supports it, the
+// filtering itself is left to the client's "filter" request.
+func serviceArgs(service string, args ...string) []string {
+	var serviceArgs []string
+	if service == "upload-pack" && setting.Git.SupportPartialClone {
+		// missing objects of a partial clone are fetched by their ID later on,
+		// only the objects reachable from a ref can be, so that no deleted or
+		// force-pushed away object can be fetched
+		serviceArgs = append(serviceArgs, "-c", "uploadpack.allowFilter=true", "-c", "uploadpack.allowReachableSHA1InWant=true")
+	}
+	serviceArgs = append(serviceArgs, service)
+	return append(serviceArgs, args...)
+}
+
 func serviceRPC(h serviceHandler, service string) {
 	defer h.r.Body.Close()
 
@@ -362,7 +453,7 @@ func serviceRPC(h serviceHandler, service string) {
 	h.environ = append(h.environ, "SSH_ORIGINAL_COMMAND="+service)
 
 	var stderr bytes.Buffer
-	cmd := exec.Command("git", service, "--stateless-rpc", h.dir)
+	cmd := exec.Command("git", serviceArgs(service, "--stateless-rpc", h.dir)...)
 	cmd.Dir = h.dir
 	if service == "receive-pack" {
 		cmd.Env = append(os.Environ(), h.environ...)
@@ -408,7 +499,7 @@ func getInfoRefs(h serviceHandler) {
 	h.setHeaderNoCache()
 	if hasAccess(getServiceType(h.r), h, false) {
 		service := getServiceType(h.r)
-		refs := gitCommand(h.dir, service, "--stateless-rpc", "--advertise-refs", ".")
+		refs := gitCommand(h.dir, serviceArgs(service, "--stateless-rpc", "--advertise-refs", ".")...)
 
 		h.w.Header().Set("Content-Type", fmt.Sprintf("application/x-git-%s-advertisement", service))
 		h.w.WriteHeader(http.StatusOK)
@@ -0,0 +1,66 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckGitHTTPRateLimit(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	oldLimit := setting.Git.HTTPRateLimit
+	defer func() {
+		setting.Git.HTTPRateLimit = oldLimit
+		gitHTTPLimitersOnce = sync.Once{}
+	}()
+	setting.Git.HTTPRateLimit.Enabled = true
+	setting.Git.HTTPRateLimit.AnonymousRequestsPerMinute = 1
+	setting.Git.HTTPRateLimit.AnonymousBurst = 2
+	setting.Git.HTTPRateLimit.AuthenticatedRequestsPerMinute = 0
+	gitHTTPLimitersOnce = sync.Once{}
+
+	// anonymous requests are limited by the address of the client, whatever
+	// the proxy headers it sends
+	mockContext := func(remoteAddr, forwardedFor string) *context.Context {
+		ctx := test.MockContext(t, "user2/repo1.git/info/refs")
+		ctx.Req.RemoteAddr = remoteAddr
+		ctx.Req.Header = http.Header{}
+		if len(forwardedFor) > 0 {
+			ctx.Req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		return ctx
+	}
+	assert.True(t, checkGitHTTPRateLimit(mockContext("10.0.0.1:1234", ""), nil))
+	assert.True(t, checkGitHTTPRateLimit(mockContext("10.0.0.1:5678", "1.2.3.4"), nil))
+
+	ctx := mockContext("10.0.0.1:1234", "5.6.7.8")
+	assert.False(t, checkGitHTTPRateLimit(ctx, nil))
+	assert.EqualValues(t, http.StatusTooManyRequests, ctx.Resp.Status())
+	assert.Equal(t, "60", ctx.Resp.Header().Get("Retry-After"))
+	assert.True(t, checkGitHTTPRateLimit(mockContext("10.0.0.2:1234", ""), nil))
+
+	// behind a trusted reverse proxy, the proxy headers give the client
+	setting.Git.HTTPRateLimit.TrustProxyHeaders = true
+	assert.True(t, checkGitHTTPRateLimit(mockContext("10.0.0.1:1234", "1.2.3.4"), nil))
+	assert.True(t, checkGitHTTPRateLimit(mockContext("10.0.0.1:1234", "1.2.3.4"), nil))
+	assert.False(t, checkGitHTTPRateLimit(mockContext("10.0.0.1:1234", "1.2.3.4"), nil))
+
+	// authenticated requests are not limited
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	for i := 0; i < 3; i++ {
+		ctx := test.MockContext(t, "user2/repo1.git/info/refs")
+		assert.True(t, checkGitHTTPRateLimit(ctx, user))
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/indexer"
+	issuetemplate "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/notification"
@@ -55,6 +56,17 @@ var (
 		".github/ISSUE_TEMPLATE.md",
 		".github/issue_template.md",
 	}
+	// IssueTemplateYAMLCandidates issue templates declaring fields
+	IssueTemplateYAMLCandidates = []string{
+		".gitea/ISSUE_TEMPLATE.yaml",
+		".gitea/ISSUE_TEMPLATE.yml",
+		".gitea/issue_template.yaml",
+		".gitea/issue_template.yml",
+		".github/ISSUE_TEMPLATE.yaml",
+		".github/ISSUE_TEMPLATE.yml",
+		".github/issue_template.yaml",
+		".github/issue_template.yml",
+	}
 )
 
 // MustEnableIssues check if repository enable internal issues
@@ -121,9 +133,19 @@ func issues(ctx *context.Context, milestoneID int64, isPullOption util.OptionalB
 		keyword = ""
 	}
 
+	query, err := models.ParseIssueSearchQuery(keyword, ctx.User)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) && !models.IsErrInvalidIssueSearchQuery(err) {
+			ctx.ServerError("ParseIssueSearchQuery", err)
+			return
+		}
+		query = &models.IssueSearchQuery{}
+		forceEmpty = true
+	}
+
 	var issueIDs []int64
-	if len(keyword) > 0 {
-		issueIDs, err = indexer.SearchIssuesByKeyword(repo.ID, keyword)
+	if len(query.Keyword) > 0 {
+		issueIDs, err = indexer.SearchIssuesByKeyword(repo.ID, query.Keyword)
 		if len(issueIDs) == 0 {
 			forceEmpty = true
 		}
@@ -142,6 +164,8 @@ func issues(ctx *context.Context, milestoneID int64, isPullOption util.OptionalB
 			PosterID:    posterID,
 			IsPull:      isPullOption,
 			IssueIDs:    issueIDs,
+			InvolvedID:  query.InvolvedID,
+			Involvement: query.Involvement,
 		})
 		if err != nil {
 			ctx.ServerError("GetIssueStats", err)
@@ -179,6 +203,8 @@ func issues(ctx *context.Context, milestoneID int64, isPullOption util.OptionalB
 			Labels:      selectLabels,
 			SortType:    sortType,
 			IssueIDs:    issueIDs,
+			InvolvedID:  query.InvolvedID,
+			Involvement: query.Involvement,
 		})
 		if err != nil {
 			ctx.ServerError("Issues", err)
@@ -198,6 +224,21 @@ func issues(ctx *context.Context, milestoneID int64, isPullOption util.OptionalB
 	}
 	ctx.Data["Issues"] = issues
 
+	if isPullOption.IsTrue() {
+		prs := make(models.PullRequestList, 0, len(issues))
+		for _, issue := range issues {
+			if issue.PullRequest != nil {
+				prs = append(prs, issue.PullRequest)
+			}
+		}
+		commitStatus, err := prs.GetCombinedCommitStatuses(repo)
+		if err != nil {
+			ctx.ServerError("GetCombinedCommitStatuses", err)
+			return
+		}
+		ctx.Data["CommitStatus"] = commitStatus
+	}
+
 	// Get assignees.
 	ctx.Data["Assignees"], err = repo.GetAssignees()
 	if err != nil {
@@ -355,6 +396,46 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleFiles
 	}
 }
 
+// getIssueTemplate returns the YAML issue template of the repository, if any.
+func getIssueTemplate(ctx *context.Context) *issuetemplate.Template {
+	for _, filename := range IssueTemplateYAMLCandidates {
+		content, found := getFileContentFromDefaultBranch(ctx, filename)
+		if !found {
+			continue
+		}
+		t, err := issuetemplate.Parse([]byte(content))
+		if err != nil {
+			log.Warn("Invalid issue template %s of repository %d: %v", filename, ctx.Repo.Repository.ID, err)
+			return nil
+		}
+		return t
+	}
+	return nil
+}
+
+// issueContentFromTemplate returns the issue body submitted for t, either made
+// of the template fields filled in the form or written in the raw body.
+func issueContentFromTemplate(ctx *context.Context, t *issuetemplate.Template, content string) string {
+	values := make(map[string]string, len(t.Fields))
+	isFormBased := false
+	for _, field := range t.Fields {
+		if _, ok := ctx.Req.Form[field.FormName()]; ok {
+			isFormBased = true
+		}
+		values[field.Name] = ctx.Query(field.FormName())
+	}
+	ctx.Data["IssueTemplateFields"] = t.Fields
+	ctx.Data["IssueTemplateValues"] = values
+
+	if !isFormBased {
+		return content
+	}
+	if content = strings.TrimSpace(content); len(content) > 0 {
+		content += "\n\n"
+	}
+	return content + t.RenderFields(values)
+}
+
 // NewIssue render createing issue page
 func NewIssue(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.issues.new")
@@ -373,7 +454,13 @@ func NewIssue(ctx *context.Context) {
 		ctx.Data["Milestone"] = milestone
 	}
 
-	setTemplateIfExists(ctx, issueTemplateKey, IssueTemplateCandidates)
+	if t := getIssueTemplate(ctx); t != nil {
+		ctx.Data[issueTemplateKey] = strings.TrimSpace(t.Body)
+		ctx.Data["IssueTemplateFields"] = t.Fields
+		ctx.Data["IssueTemplateValues"] = map[string]string{}
+	} else {
+		setTemplateIfExists(ctx, issueTemplateKey, IssueTemplateCandidates)
+	}
 	renderAttachmentSettings(ctx)
 
 	RetrieveRepoMetas(ctx, ctx.Repo.Repository)
@@ -494,13 +581,23 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		return
 	}
 
+	content := form.Content
+	if t := getIssueTemplate(ctx); t != nil {
+		content = issueContentFromTemplate(ctx, t, content)
+		if missing := t.MissingFields(content); len(missing) > 0 {
+			ctx.Data["Err_Content"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.issues.new.required_field_missing", missing[0].Title()), tplIssueNew, &form)
+			return
+		}
+	}
+
 	issue := &models.Issue{
 		RepoID:      repo.ID,
 		Title:       form.Title,
 		PosterID:    ctx.User.ID,
 		Poster:      ctx.User,
 		MilestoneID: milestoneID,
-		Content:     form.Content,
+		Content:     content,
 		Ref:         form.Ref,
 	}
 	if err := models.NewIssue(repo, issue, labelIDs, assigneeIDs, attachments); err != nil {
@@ -775,6 +872,17 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadDepIssueDetails", err)
 				return
 			}
+		} else if comment.Type == models.CommentTypeIssueMoved {
+			if err = comment.LoadDepIssueDetails(); err != nil && !models.IsErrIssueNotExist(err) {
+				ctx.ServerError("LoadDepIssueDetails", err)
+				return
+			}
+			if comment.DependentIssue != nil {
+				if err = comment.DependentIssue.LoadRepo(); err != nil {
+					ctx.ServerError("LoadRepo", err)
+					return
+				}
+			}
 		} else if comment.Type == models.CommentTypeCode || comment.Type == models.CommentTypeReview {
 			if err = comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
 				ctx.ServerError("LoadReview", err)
@@ -851,6 +959,22 @@ func ViewIssue(ctx *context.Context) {
 				ctx.Data["MergeStyle"] = ""
 			}
 		}
+		// The title of a merge message goes to the title field of the merge
+		// forms, the rest to their message field.
+		mergeTitles := make(map[string]string)
+		mergeBodies := make(map[string]string)
+		for _, style := range []models.MergeStyle{models.MergeStyleMerge, models.MergeStyleRebaseMerge, models.MergeStyleSquash} {
+			if prConfig.IsMergeStyleAllowed(style) {
+				parts := strings.SplitN(pull.GetMergeMessage(style), "\n", 2)
+				mergeTitles[string(style)] = strings.TrimSpace(parts[0])
+				if len(parts) == 2 {
+					mergeBodies[string(style)] = strings.TrimSpace(parts[1])
+				}
+			}
+		}
+		ctx.Data["MergeTitles"] = mergeTitles
+		ctx.Data["MergeBodies"] = mergeBodies
+
 		if err = pull.LoadProtectedBranch(); err != nil {
 			ctx.ServerError("LoadProtectedBranch", err)
 			return
@@ -859,6 +983,22 @@ func ViewIssue(ctx *context.Context) {
 			cnt := pull.ProtectedBranch.GetGrantedApprovalsCount(pull)
 			ctx.Data["IsBlockedByApprovals"] = pull.ProtectedBranch.RequiredApprovals > 0 && cnt < pull.ProtectedBranch.RequiredApprovals
 			ctx.Data["GrantedApprovals"] = cnt
+			if pull.ProtectedBranch.RequireTeamApproval && !pull.ProtectedBranch.HasTeamApproval(pull) {
+				// a deleted approvals team is no approvals team
+				team, err := models.GetTeamByID(pull.ProtectedBranch.ApprovalsTeamID)
+				if err == nil {
+					ctx.Data["IsBlockedByTeamApproval"] = true
+					ctx.Data["ApprovalsTeamName"] = team.Name
+				} else if err != models.ErrTeamNotExist {
+					ctx.ServerError("GetTeamByID", err)
+					return
+				}
+			}
+			passed, err := pull.ProtectedBranch.HasRequiredStatusChecks(pull)
+			if err != nil {
+				log.Error(4, "HasRequiredStatusChecks: %v", err)
+			}
+			ctx.Data["IsBlockedByStatusChecks"] = !passed
 		}
 		ctx.Data["IsPullBranchDeletable"] = canDelete && pull.HeadRepo != nil && git.IsBranchExist(pull.HeadRepo.RepoPath(), pull.HeadBranch)
 
@@ -867,6 +1007,11 @@ func ViewIssue(ctx *context.Context) {
 			ctx.ServerError("GetReviewersByPullID", err)
 			return
 		}
+		ctx.Data["PullReviewRequests"], err = models.GetReviewRequestsByPullID(issue.ID)
+		if err != nil {
+			ctx.ServerError("GetReviewRequestsByPullID", err)
+			return
+		}
 	}
 
 	// Get Dependencies
@@ -972,6 +1117,97 @@ func UpdateIssueTitle(ctx *context.Context) {
 	})
 }
 
+// UpdateIssuePoints change issue's points
+func UpdateIssuePoints(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.IsSigned || !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(403)
+		return
+	}
+
+	issueLink := ctx.Repo.RepoLink + "/issues/" + com.ToStr(issue.Index)
+	points := ctx.QueryInt("points")
+	if points < 0 {
+		ctx.Flash.Error(ctx.Tr("repo.issues.points_invalid"))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	if err := issue.ChangePoints(points); err != nil {
+		ctx.ServerError("ChangePoints", err)
+		return
+	}
+	ctx.Redirect(issueLink)
+}
+
+// MoveIssue moves an issue to another repository
+func MoveIssue(ctx *context.Context, form auth.MoveIssueForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsPull {
+		ctx.NotFound("MoveIssue", nil)
+		return
+	}
+
+	issueLink := ctx.Repo.RepoLink + "/issues/" + com.ToStr(issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(form.TargetRepo), "/", 2)
+	if len(fields) != 2 {
+		ctx.Flash.Error(ctx.Tr("repo.issues.move.target_not_exist", form.TargetRepo))
+		ctx.Redirect(issueLink)
+		return
+	}
+	target, err := models.GetRepositoryByOwnerAndName(fields[0], fields[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.move.target_not_exist", form.TargetRepo))
+			ctx.Redirect(issueLink)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	perm, err := models.GetUserRepoPermission(target, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanWrite(models.UnitTypeIssues) {
+		// Don't reveal the existence of repositories the user cannot see.
+		ctx.Flash.Error(ctx.Tr("repo.issues.move.target_not_exist", form.TargetRepo))
+		ctx.Redirect(issueLink)
+		return
+	}
+
+	moved, err := models.MoveIssue(ctx.User, issue, target)
+	if err != nil {
+		if models.IsErrIssueCannotBeMoved(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.move.same_repo"))
+			ctx.Redirect(issueLink)
+		} else if models.IsErrDependenciesLeft(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.dependency.issue_close_blocked"))
+			ctx.Redirect(issueLink)
+		} else {
+			ctx.ServerError("MoveIssue", err)
+		}
+		return
+	}
+
+	ctx.Redirect(moved.HTMLURL())
+}
+
 // UpdateIssueContent change issue's content
 func UpdateIssueContent(ctx *context.Context) {
 	issue := GetActionIssue(ctx)
@@ -119,6 +119,7 @@ func NewMilestonePost(ctx *context.Context, form auth.CreateMilestoneForm) {
 		Name:         form.Title,
 		Content:      form.Content,
 		DeadlineUnix: util.TimeStamp(deadline.Unix()),
+		ProgressMode: milestoneProgressMode(form),
 	}); err != nil {
 		ctx.ServerError("NewMilestone", err)
 		return
@@ -128,6 +129,13 @@ func NewMilestonePost(ctx *context.Context, form auth.CreateMilestoneForm) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/milestones")
 }
 
+func milestoneProgressMode(form auth.CreateMilestoneForm) models.MilestoneProgressMode {
+	if form.Weighted {
+		return models.MilestoneProgressWeighted
+	}
+	return models.MilestoneProgressCount
+}
+
 // EditMilestone render edting milestone page
 func EditMilestone(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.milestones.edit")
@@ -147,6 +155,7 @@ func EditMilestone(ctx *context.Context) {
 	}
 	ctx.Data["title"] = m.Name
 	ctx.Data["content"] = m.Content
+	ctx.Data["weighted"] = m.ProgressMode == models.MilestoneProgressWeighted
 	if len(m.DeadlineString) > 0 {
 		ctx.Data["deadline"] = m.DeadlineString
 	}
@@ -189,6 +198,7 @@ func EditMilestonePost(ctx *context.Context, form auth.CreateMilestoneForm) {
 	m.Name = form.Title
 	m.Content = form.Content
 	m.DeadlineUnix = util.TimeStamp(deadline.Unix())
+	m.ProgressMode = milestoneProgressMode(form)
 	if err = models.UpdateMilestone(m); err != nil {
 		ctx.ServerError("UpdateMilestone", err)
 		return
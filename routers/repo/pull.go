@@ -541,6 +541,12 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
+	if pr.IsDraft {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_draft"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
 	if ctx.HasError() {
 		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
 		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
@@ -549,15 +555,7 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 
 	message := strings.TrimSpace(form.MergeTitleField)
 	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
-		}
+		message = pr.GetMergeMessage(models.MergeStyle(form.Do))
 	}
 
 	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
@@ -595,6 +593,34 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
 }
 
+// MarkPullRequestReady takes a draft pull request out of the draft state
+func MarkPullRequestReady(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsClosed {
+		ctx.NotFound("MarkPullRequestReady", nil)
+		return
+	}
+
+	if !issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWrite(models.UnitTypePullRequests) {
+		ctx.Error(403)
+		return
+	}
+
+	if err := issue.PullRequest.MarkReadyForReview(); err != nil {
+		if models.IsErrPullRequestNotOpen(err) {
+			ctx.NotFound("MarkReadyForReview", err)
+		} else {
+			ctx.ServerError("MarkReadyForReview", err)
+		}
+		return
+	}
+
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+}
+
 // ParseCompareInfo parse compare info between two commit for preparing pull request
 func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *git.Repository, *git.PullRequestInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
@@ -886,6 +912,7 @@ func CompareAndPullRequestPost(ctx *context.Context, form auth.CreateIssueForm)
 		BaseRepo:     repo,
 		MergeBase:    prInfo.MergeBase,
 		Type:         models.PullRequestGitea,
+		IsDraft:      form.Draft,
 	}
 	// FIXME: check error in the case two people send pull request at almost same time, give nice error prompt
 	// instead of 500.
@@ -901,6 +928,9 @@ func CompareAndPullRequestPost(ctx *context.Context, form auth.CreateIssueForm)
 		ctx.ServerError("PushToBaseRepo", err)
 		return
 	}
+	if err := pullRequest.RequestCodeOwnerReviews(); err != nil {
+		log.Error(4, "RequestCodeOwnerReviews [pr_id: %d]: %v", pullRequest.ID, err)
+	}
 
 	notification.NotifyNewPullRequest(pullRequest)
 
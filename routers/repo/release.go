@@ -181,6 +181,8 @@ func NewReleasePost(ctx *context.Context, form auth.NewReleaseForm) {
 				ctx.RenderWithErr(ctx.Tr("repo.release.tag_name_already_exist"), tplReleaseNew, &form)
 			case models.IsErrInvalidTagName(err):
 				ctx.RenderWithErr(ctx.Tr("repo.release.tag_name_invalid"), tplReleaseNew, &form)
+			case models.IsErrProtectedTagName(err):
+				ctx.RenderWithErr(ctx.Tr("repo.release.tag_name_protected"), tplReleaseNew, &form)
 			default:
 				ctx.ServerError("CreateRelease", err)
 			}
@@ -203,7 +205,11 @@ func NewReleasePost(ctx *context.Context, form auth.NewReleaseForm) {
 
 		if err = models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, attachmentUUIDs); err != nil {
 			ctx.Data["Err_TagName"] = true
-			ctx.ServerError("UpdateRelease", err)
+			if models.IsErrProtectedTagName(err) {
+				ctx.RenderWithErr(ctx.Tr("repo.release.tag_name_protected"), tplReleaseNew, &form)
+			} else {
+				ctx.ServerError("UpdateRelease", err)
+			}
 			return
 		}
 	}
@@ -281,7 +287,11 @@ func EditReleasePost(ctx *context.Context, form auth.EditReleaseForm) {
 	rel.IsDraft = len(form.Draft) > 0
 	rel.IsPrerelease = form.Prerelease
 	if err = models.UpdateRelease(ctx.User, ctx.Repo.GitRepo, rel, attachmentUUIDs); err != nil {
-		ctx.ServerError("UpdateRelease", err)
+		if models.IsErrProtectedTagName(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.release.tag_name_protected"), tplReleaseNew, &form)
+		} else {
+			ctx.ServerError("UpdateRelease", err)
+		}
 		return
 	}
 	ctx.Redirect(ctx.Repo.RepoLink + "/releases")
@@ -290,7 +300,11 @@ func EditReleasePost(ctx *context.Context, form auth.EditReleaseForm) {
 // DeleteRelease delete a release
 func DeleteRelease(ctx *context.Context) {
 	if err := models.DeleteReleaseByID(ctx.QueryInt64("id"), ctx.User, true); err != nil {
-		ctx.Flash.Error("DeleteReleaseByID: " + err.Error())
+		if models.IsErrProtectedTagName(err) {
+			ctx.Flash.Error(ctx.Tr("repo.release.tag_name_protected"))
+		} else {
+			ctx.Flash.Error("DeleteReleaseByID: " + err.Error())
+		}
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.release.deletion_success"))
 	}
@@ -6,11 +6,8 @@ package repo
 
 import (
 	"fmt"
-	"os"
-	"path"
 	"strings"
-
-	"github.com/Unknwon/com"
+	"time"
 
 	"code.gitea.io/git"
 
@@ -21,6 +18,8 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/migrate"
+	"code.gitea.io/gitea/services/repository/archiver"
 )
 
 const (
@@ -243,6 +242,14 @@ func MigratePost(ctx *context.Context, form auth.MigrateRepoForm) {
 		return
 	}
 
+	if form.Issues {
+		if _, _, err := migrate.ParseGithubURL(form.CloneAddr); err != nil {
+			ctx.Data["Err_CloneAddr"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.migrate.issues_github_only"), tplMigrate, &form)
+			return
+		}
+	}
+
 	repo, err := models.MigrateRepository(ctx.User, ctxUser, models.MigrateRepoOptions{
 		Name:        form.RepoName,
 		Description: form.Description,
@@ -252,6 +259,12 @@ func MigratePost(ctx *context.Context, form auth.MigrateRepoForm) {
 	})
 	if err == nil {
 		log.Trace("Repository migrated [%d]: %s/%s", repo.ID, ctxUser.Name, form.RepoName)
+		if form.Issues {
+			if err = migrate.StartGithubIssueMigration(ctx.User, repo, form.CloneAddr, form.AuthToken); err != nil {
+				ctx.ServerError("StartGithubIssueMigration", err)
+				return
+			}
+		}
 		ctx.Redirect(setting.AppSubURL + "/" + ctxUser.Name + "/" + form.RepoName)
 		return
 	}
@@ -310,24 +323,25 @@ func Action(ctx *context.Context) {
 	ctx.RedirectToFirst(ctx.Query("redirect_to"), ctx.Repo.RepoLink)
 }
 
+// archiveWaitTimeout is how long a download request waits for a pending archive
+// before responding with 202 Accepted.
+const archiveWaitTimeout = 2 * time.Second
+
 // Download download an archive of a repository
 func Download(ctx *context.Context) {
 	var (
 		uri         = ctx.Params("*")
 		refName     string
 		ext         string
-		archivePath string
 		archiveType git.ArchiveType
 	)
 
 	switch {
 	case strings.HasSuffix(uri, ".zip"):
 		ext = ".zip"
-		archivePath = path.Join(ctx.Repo.GitRepo.Path, "archives/zip")
 		archiveType = git.ZIP
 	case strings.HasSuffix(uri, ".tar.gz"):
 		ext = ".tar.gz"
-		archivePath = path.Join(ctx.Repo.GitRepo.Path, "archives/targz")
 		archiveType = git.TARGZ
 	default:
 		log.Trace("Unknown format: %s", uri)
@@ -336,13 +350,6 @@ func Download(ctx *context.Context) {
 	}
 	refName = strings.TrimSuffix(uri, ext)
 
-	if !com.IsDir(archivePath) {
-		if err := os.MkdirAll(archivePath, os.ModePerm); err != nil {
-			ctx.ServerError("Download -> os.MkdirAll(archivePath)", err)
-			return
-		}
-	}
-
 	// Get corresponding commit.
 	var (
 		commit *git.Commit
@@ -372,13 +379,28 @@ func Download(ctx *context.Context) {
 		return
 	}
 
-	archivePath = path.Join(archivePath, base.ShortSha(commit.ID.String())+ext)
-	if !com.IsFile(archivePath) {
-		if err := commit.CreateArchive(archivePath, archiveType); err != nil {
-			ctx.ServerError("Download -> CreateArchive "+archivePath, err)
-			return
-		}
+	archivePath, status, err := archiver.Archive(gitRepo.Path, commit, archiveType)
+	if err != nil {
+		ctx.ServerError("Download -> Archive", err)
+		return
+	}
+	if status == archiver.StatusPending {
+		// Small archives are usually done quickly, so give the job a chance to
+		// finish before asking the client to come back later.
+		status, err = archiver.Wait(archivePath, archiveWaitTimeout)
 	}
 
-	ctx.ServeFile(archivePath, ctx.Repo.Repository.Name+"-"+refName+ext)
+	switch status {
+	case archiver.StatusReady:
+		ctx.ServeFile(archivePath, ctx.Repo.Repository.Name+"-"+refName+ext)
+	case archiver.StatusFailed:
+		ctx.ServerError("Download -> Archive "+archivePath, err)
+	default:
+		ctx.Resp.Header().Set("Location", ctx.Req.URL.RequestURI())
+		ctx.Resp.Header().Set("Retry-After", "5")
+		ctx.JSON(202, map[string]interface{}{
+			"status": status.String(),
+			"url":    ctx.Req.URL.RequestURI(),
+		})
+	}
 }
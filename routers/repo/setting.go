@@ -17,7 +17,6 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers/utils"
 )
@@ -26,6 +25,7 @@ const (
 	tplSettingsOptions base.TplName = "repo/settings/options"
 	tplCollaboration   base.TplName = "repo/settings/collaboration"
 	tplBranches        base.TplName = "repo/settings/branches"
+	tplTags            base.TplName = "repo/settings/tags"
 	tplGithooks        base.TplName = "repo/settings/githooks"
 	tplGithookEdit     base.TplName = "repo/settings/githook_edit"
 	tplDeployKeys      base.TplName = "repo/settings/deploy_keys"
@@ -36,6 +36,7 @@ const (
 func Settings(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+	ctx.Data["close_issues_branches"] = strings.Join(ctx.Repo.Repository.MustGetUnit(models.UnitTypeIssues).IssuesConfig().CloseIssuesBranches, "\n")
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -117,20 +118,18 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		}
 
 		interval, err := time.ParseDuration(form.Interval)
-		if err != nil && (interval != 0 || interval < setting.Mirror.MinInterval) {
+		if err != nil {
 			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
-		} else {
-			ctx.Repo.Mirror.EnablePrune = form.EnablePrune
-			ctx.Repo.Mirror.Interval = interval
-			if interval != 0 {
-				ctx.Repo.Mirror.NextUpdateUnix = util.TimeStampNow().AddDuration(interval)
-			} else {
-				ctx.Repo.Mirror.NextUpdateUnix = 0
-			}
-			if err := models.UpdateMirror(ctx.Repo.Mirror); err != nil {
-				ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
-				return
-			}
+			return
+		}
+		if err = ctx.Repo.Mirror.SetInterval(interval); err != nil {
+			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_too_short", setting.Mirror.MinInterval), tplSettingsOptions, &form)
+			return
+		}
+		ctx.Repo.Mirror.EnablePrune = form.EnablePrune
+		if err := models.UpdateMirror(ctx.Repo.Mirror); err != nil {
+			ctx.ServerError("UpdateMirror", err)
+			return
 		}
 		if err := ctx.Repo.Mirror.SaveAddress(form.MirrorAddress); err != nil {
 			ctx.ServerError("SaveAddress", err)
@@ -207,19 +206,36 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					},
 				})
 			} else {
+				config := &models.IssuesConfig{
+					EnableTimetracker:                form.EnableTimetracker,
+					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
+					EnableDependencies:               form.EnableIssueDependencies,
+					CloseIssuesBranches:              make([]string, 0, 2),
+				}
+				for _, pattern := range strings.Split(form.CloseIssuesBranches, "\n") {
+					if pattern = strings.TrimSpace(pattern); len(pattern) > 0 {
+						config.CloseIssuesBranches = append(config.CloseIssuesBranches, pattern)
+					}
+				}
+				if err := config.ValidateCloseIssuesBranches(); err != nil {
+					ctx.Flash.Error(ctx.Tr("repo.settings.close_issues_branches_invalid", err.(models.ErrInvalidCloseIssuesBranchPattern).Pattern))
+					ctx.Redirect(repo.Link() + "/settings")
+					return
+				}
 				units = append(units, models.RepoUnit{
 					RepoID: repo.ID,
 					Type:   models.UnitTypeIssues,
-					Config: &models.IssuesConfig{
-						EnableTimetracker:                form.EnableTimetracker,
-						AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
-						EnableDependencies:               form.EnableIssueDependencies,
-					},
+					Config: config,
 				})
 			}
 		}
 
 		if form.EnablePulls {
+			if err := models.ValidateMergeMessageTemplate(form.PullsMergeMessageTemplate); err != nil {
+				ctx.Flash.Error(ctx.Tr("repo.settings.pulls.merge_message_template_unknown_placeholder", err.(models.ErrUnknownMergeMessagePlaceholder).Name))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypePullRequests,
@@ -229,6 +245,8 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					AllowRebase:               form.PullsAllowRebase,
 					AllowRebaseMerge:          form.PullsAllowRebaseMerge,
 					AllowSquash:               form.PullsAllowSquash,
+					SquashUseCommitAuthor:     form.PullsSquashUseCommitAuthor,
+					MergeMessageTemplate:      strings.TrimSpace(form.PullsMergeMessageTemplate),
 				},
 			})
 		}
@@ -298,12 +316,16 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		}
 
 		newOwner := ctx.Query("new_owner_name")
-		isExist, err := models.IsUserExist(0, newOwner)
+		problems, err := models.CheckTransferOwnership(ctx.User, newOwner, repo)
 		if err != nil {
-			ctx.ServerError("IsUserExist", err)
+			ctx.ServerError("CheckTransferOwnership", err)
 			return
-		} else if !isExist {
-			ctx.RenderWithErr(ctx.Tr("form.enterred_invalid_owner_name"), tplSettingsOptions, nil)
+		} else if len(problems) > 0 {
+			msgs := make([]string, len(problems))
+			for i, problem := range problems {
+				msgs[i] = ctx.Tr("repo.settings.transfer_problem_" + string(problem))
+			}
+			ctx.RenderWithErr(strings.Join(msgs, "<br>"), tplSettingsOptions, nil)
 			return
 		}
 
@@ -319,6 +341,26 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.transfer_succeed"))
 		ctx.Redirect(setting.AppSubURL + "/" + newOwner + "/" + repo.Name)
 
+	case "archive", "unarchive":
+		if !ctx.Repo.IsOwner() {
+			ctx.Error(404)
+			return
+		}
+
+		isArchived := ctx.Query("action") == "archive"
+		if err := repo.SetArchiveRepoState(ctx.User, isArchived); err != nil {
+			ctx.ServerError("SetArchiveRepoState", err)
+			return
+		}
+		if isArchived {
+			log.Trace("Repository archived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+			ctx.Flash.Success(ctx.Tr("repo.settings.archive_success"))
+		} else {
+			log.Trace("Repository unarchived: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+			ctx.Flash.Success(ctx.Tr("repo.settings.unarchive_success"))
+		}
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	case "delete":
 		if !ctx.Repo.IsOwner() {
 			ctx.Error(404)
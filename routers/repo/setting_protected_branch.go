@@ -112,7 +112,8 @@ func SettingsProtectedBranch(c *context.Context) {
 	if protectBranch == nil {
 		// No options found, create defaults.
 		protectBranch = &models.ProtectedBranch{
-			BranchName: branch,
+			BranchName:          branch,
+			ApprovalsTeamWeight: 1,
 		}
 	}
 
@@ -125,6 +126,8 @@ func SettingsProtectedBranch(c *context.Context) {
 	c.Data["whitelist_users"] = strings.Join(base.Int64sToStrings(protectBranch.WhitelistUserIDs), ",")
 	c.Data["merge_whitelist_users"] = strings.Join(base.Int64sToStrings(protectBranch.MergeWhitelistUserIDs), ",")
 	c.Data["approvals_whitelist_users"] = strings.Join(base.Int64sToStrings(protectBranch.ApprovalsWhitelistUserIDs), ",")
+	c.Data["status_check_contexts"] = strings.Join(protectBranch.StatusCheckContexts, "\n")
+	c.Data["protected_files_whitelist_users"] = strings.Join(base.Int64sToStrings(protectBranch.ProtectedFilesUserIDs), ",")
 
 	if c.Repo.Owner.IsOrganization() {
 		teams, err := c.Repo.Owner.TeamsWithAccessToRepo(c.Repo.Repository.ID, models.AccessModeRead)
@@ -136,6 +139,7 @@ func SettingsProtectedBranch(c *context.Context) {
 		c.Data["whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.WhitelistTeamIDs), ",")
 		c.Data["merge_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.MergeWhitelistTeamIDs), ",")
 		c.Data["approvals_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.ApprovalsWhitelistTeamIDs), ",")
+		c.Data["protected_files_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.ProtectedFilesTeamIDs), ",")
 	}
 
 	c.Data["Branch"] = protectBranch
@@ -162,16 +166,24 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		if protectBranch == nil {
 			// No options found, create defaults.
 			protectBranch = &models.ProtectedBranch{
-				RepoID:     ctx.Repo.Repository.ID,
-				BranchName: branch,
+				RepoID:              ctx.Repo.Repository.ID,
+				BranchName:          branch,
+				ApprovalsTeamWeight: 1,
 			}
 		}
 		if f.RequiredApprovals < 0 {
 			ctx.Flash.Error(ctx.Tr("repo.settings.protected_branch_required_approvals_min"))
 			ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
+			return
+		}
+		if ctx.Repo.Owner.IsOrganization() && f.ApprovalsTeamWeight < 1 {
+			ctx.Flash.Error(ctx.Tr("repo.settings.protected_branch_approvals_team_weight_min"))
+			ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
+			return
 		}
 
 		var whitelistUsers, whitelistTeams, mergeWhitelistUsers, mergeWhitelistTeams, approvalsWhitelistUsers, approvalsWhitelistTeams []int64
+		var protectedFilesWhitelistUsers, protectedFilesWhitelistTeams []int64
 		protectBranch.EnableWhitelist = f.EnableWhitelist
 		if strings.TrimSpace(f.WhitelistUsers) != "" {
 			whitelistUsers, _ = base.StringsToInt64s(strings.Split(f.WhitelistUsers, ","))
@@ -193,6 +205,34 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		if strings.TrimSpace(f.ApprovalsWhitelistTeams) != "" {
 			approvalsWhitelistTeams, _ = base.StringsToInt64s(strings.Split(f.ApprovalsWhitelistTeams, ","))
 		}
+		if ctx.Repo.Owner.IsOrganization() {
+			protectBranch.ApprovalsTeamWeight = f.ApprovalsTeamWeight
+			protectBranch.RequireTeamApproval = f.RequireTeamApproval
+		}
+		protectBranch.EnableStatusCheck = f.EnableStatusCheck
+		protectBranch.StatusCheckContexts = make([]string, 0, 2)
+		for _, statusContext := range strings.Split(f.StatusCheckContexts, "\n") {
+			if statusContext = strings.TrimSpace(statusContext); len(statusContext) > 0 {
+				protectBranch.StatusCheckContexts = append(protectBranch.StatusCheckContexts, statusContext)
+			}
+		}
+		if err = protectBranch.ValidateStatusCheckContexts(); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.protect_status_check_contexts_invalid", err.(models.ErrInvalidStatusCheckPattern).Pattern))
+			ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
+			return
+		}
+		protectBranch.ProtectedFilePatterns = strings.TrimSpace(f.ProtectedFilePatterns)
+		if err = protectBranch.ValidateProtectedFilePatterns(); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.protect_protected_file_patterns_invalid", err.(models.ErrInvalidProtectedFilePattern).Pattern))
+			ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
+			return
+		}
+		if strings.TrimSpace(f.ProtectedFilesWhitelistUsers) != "" {
+			protectedFilesWhitelistUsers, _ = base.StringsToInt64s(strings.Split(f.ProtectedFilesWhitelistUsers, ","))
+		}
+		if strings.TrimSpace(f.ProtectedFilesWhitelistTeams) != "" {
+			protectedFilesWhitelistTeams, _ = base.StringsToInt64s(strings.Split(f.ProtectedFilesWhitelistTeams, ","))
+		}
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
 			TeamIDs:          whitelistTeams,
@@ -200,6 +240,10 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 			MergeTeamIDs:     mergeWhitelistTeams,
 			ApprovalsUserIDs: approvalsWhitelistUsers,
 			ApprovalsTeamIDs: approvalsWhitelistTeams,
+			ApprovalsTeamID:  f.ApprovalsTeamID,
+
+			ProtectedFilesUserIDs: protectedFilesWhitelistUsers,
+			ProtectedFilesTeamIDs: protectedFilesWhitelistTeams,
 		})
 		if err != nil {
 			ctx.ServerError("UpdateProtectBranch", err)
@@ -0,0 +1,152 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// setTagsContext loads the protected tags and the users and teams which can be
+// whitelisted for the tags settings page.
+func setTagsContext(ctx *context.Context) bool {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.tags")
+	ctx.Data["PageIsSettingsTags"] = true
+
+	protectedTags, err := ctx.Repo.Repository.GetProtectedTags()
+	if err != nil {
+		ctx.ServerError("GetProtectedTags", err)
+		return false
+	}
+	ctx.Data["ProtectedTags"] = protectedTags
+
+	users, err := ctx.Repo.Repository.GetWriters()
+	if err != nil {
+		ctx.ServerError("Repo.Repository.GetWriters", err)
+		return false
+	}
+	ctx.Data["Users"] = users
+
+	if ctx.Repo.Owner.IsOrganization() {
+		teams, err := ctx.Repo.Owner.TeamsWithAccessToRepo(ctx.Repo.Repository.ID, models.AccessModeRead)
+		if err != nil {
+			ctx.ServerError("Repo.Owner.TeamsWithAccessToRepo", err)
+			return false
+		}
+		ctx.Data["Teams"] = teams
+	}
+	return true
+}
+
+// ProtectedTags render the page to protect the tags of the repository
+func ProtectedTags(ctx *context.Context) {
+	if !setTagsContext(ctx) {
+		return
+	}
+	ctx.HTML(200, tplTags)
+}
+
+// NewProtectedTagPost response for protecting the tags matching a pattern
+func NewProtectedTagPost(ctx *context.Context, form auth.ProtectTagForm) {
+	if !setTagsContext(ctx) {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(200, tplTags)
+		return
+	}
+
+	saveProtectedTag(ctx, &models.ProtectedTag{}, form)
+}
+
+// EditProtectedTag render the page to edit a protected tag of the repository
+func EditProtectedTag(ctx *context.Context) {
+	if !setTagsContext(ctx) {
+		return
+	}
+
+	protectTag := getProtectedTag(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["ProtectedTag"] = protectTag
+	ctx.Data["name_pattern"] = protectTag.NamePattern
+	ctx.Data["whitelist_users"] = strings.Join(base.Int64sToStrings(protectTag.WhitelistUserIDs), ",")
+	ctx.Data["whitelist_teams"] = strings.Join(base.Int64sToStrings(protectTag.WhitelistTeamIDs), ",")
+	ctx.HTML(200, tplTags)
+}
+
+// EditProtectedTagPost response for updating a protected tag of the repository
+func EditProtectedTagPost(ctx *context.Context, form auth.ProtectTagForm) {
+	if !setTagsContext(ctx) {
+		return
+	}
+
+	protectTag := getProtectedTag(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["ProtectedTag"] = protectTag
+	if ctx.HasError() {
+		ctx.HTML(200, tplTags)
+		return
+	}
+
+	saveProtectedTag(ctx, protectTag, form)
+}
+
+// DeleteProtectedTagPost response for removing a protected tag of the repository
+func DeleteProtectedTagPost(ctx *context.Context) {
+	if err := ctx.Repo.Repository.DeleteProtectedTag(ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteProtectedTag: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.protected_tag_deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/settings/tags",
+	})
+}
+
+func getProtectedTag(ctx *context.Context) *models.ProtectedTag {
+	protectTag, err := ctx.Repo.Repository.GetProtectedTagByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProtectedTagNotExist(err) {
+			ctx.NotFound("GetProtectedTagByID", err)
+		} else {
+			ctx.ServerError("GetProtectedTagByID", err)
+		}
+		return nil
+	}
+	return protectTag
+}
+
+func saveProtectedTag(ctx *context.Context, protectTag *models.ProtectedTag, form auth.ProtectTagForm) {
+	var whitelistUsers, whitelistTeams []int64
+	if strings.TrimSpace(form.WhitelistUsers) != "" {
+		whitelistUsers, _ = base.StringsToInt64s(strings.Split(form.WhitelistUsers, ","))
+	}
+	if strings.TrimSpace(form.WhitelistTeams) != "" {
+		whitelistTeams, _ = base.StringsToInt64s(strings.Split(form.WhitelistTeams, ","))
+	}
+
+	protectTag.NamePattern = strings.TrimSpace(form.NamePattern)
+	if err := models.UpdateProtectedTag(ctx.Repo.Repository, protectTag, whitelistUsers, whitelistTeams); err != nil {
+		if models.IsErrInvalidProtectedTagPattern(err) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.protected_tag_pattern_invalid", protectTag.NamePattern))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings/tags")
+			return
+		}
+		ctx.ServerError("UpdateProtectedTag", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_protected_tag_success", protectTag.NamePattern))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/tags")
+}
@@ -338,6 +338,16 @@ func renderCode(ctx *context.Context) {
 	}
 	ctx.Data["Topics"] = topics
 
+	if len(ctx.Repo.TreePath) == 0 {
+		// the language bar is left out rather than failing the whole page
+		languageStats, err := ctx.Repo.Repository.GetLanguageStats(ctx.Repo.CommitID)
+		if err != nil {
+			log.Error(3, "GetLanguageStats: %v", err)
+		} else {
+			ctx.Data["LanguageStats"] = languageStats
+		}
+	}
+
 	// Get current entry user currently looking at.
 	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 	if err != nil {
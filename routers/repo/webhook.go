@@ -128,9 +128,23 @@ func ParseHookEvent(form auth.WebhookForm) *models.HookEvent {
 			PullRequest:  form.PullRequest,
 			Repository:   form.Repository,
 		},
+		BranchFilter: form.BranchFilter,
+		PathFilter:   form.PathFilter,
 	}
 }
 
+// hasInvalidHookFilters flashes an error and redirects back to the form if a
+// branch or path filter of the form is not a valid glob.
+func hasInvalidHookFilters(ctx *context.Context, form auth.WebhookForm) bool {
+	err := ParseHookEvent(form).ValidateFilters()
+	if err == nil {
+		return false
+	}
+	ctx.Flash.Error(ctx.Tr("repo.settings.webhook_filter_invalid", err.(models.ErrInvalidWebhookFilterPattern).Pattern))
+	ctx.Redirect(ctx.Link)
+	return true
+}
+
 // WebHooksNewPost response for creating webhook
 func WebHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings.add_webhook")
@@ -150,6 +164,9 @@ func WebHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	contentType := models.ContentTypeJSON
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
@@ -197,6 +214,9 @@ func GogsHooksNewPost(ctx *context.Context, form auth.NewGogshookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	contentType := models.ContentTypeJSON
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
@@ -242,6 +262,9 @@ func DiscordHooksNewPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	meta, err := json.Marshal(&models.DiscordMeta{
 		Username: form.Username,
@@ -291,6 +314,9 @@ func DingtalkHooksNewPost(ctx *context.Context, form auth.NewDingtalkHookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	w := &models.Webhook{
 		RepoID:       orCtx.RepoID,
@@ -331,6 +357,9 @@ func SlackHooksNewPost(ctx *context.Context, form auth.NewSlackHookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	if form.HasInvalidChannel() {
 		ctx.Flash.Error(ctx.Tr("repo.settings.add_webhook.invalid_channel_name"))
@@ -442,6 +471,9 @@ func WebHooksEditPost(ctx *context.Context, form auth.NewWebhookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	contentType := models.ContentTypeJSON
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
@@ -481,6 +513,9 @@ func GogsHooksEditPost(ctx *context.Context, form auth.NewGogshookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	contentType := models.ContentTypeJSON
 	if models.HookContentType(form.ContentType) == models.ContentTypeForm {
@@ -520,6 +555,9 @@ func SlackHooksEditPost(ctx *context.Context, form auth.NewSlackHookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	if form.HasInvalidChannel() {
 		ctx.Flash.Error(ctx.Tr("repo.settings.add_webhook.invalid_channel_name"))
@@ -570,6 +608,9 @@ func DiscordHooksEditPost(ctx *context.Context, form auth.NewDiscordHookForm) {
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	meta, err := json.Marshal(&models.DiscordMeta{
 		Username: form.Username,
@@ -612,6 +653,9 @@ func DingtalkHooksEditPost(ctx *context.Context, form auth.NewDingtalkHookForm)
 		ctx.HTML(200, orCtx.NewTemplate)
 		return
 	}
+	if hasInvalidHookFilters(ctx, form.WebhookForm) {
+		return
+	}
 
 	w.URL = form.PayloadURL
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
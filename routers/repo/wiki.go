@@ -65,6 +65,48 @@ func findEntryForFile(commit *git.Commit, target string) (*git.TreeEntry, error)
 	return nil, nil
 }
 
+// isWikiMarkupFile returns true if the file is rendered by a markup parser
+// other than Markdown, e.g. an external AsciiDoc renderer.
+func isWikiMarkupFile(filename string) bool {
+	tp := markup.Type(filename)
+	return tp != "" && tp != markdown.MarkupName
+}
+
+// wikiFilenameToName converts a wiki filename to its corresponding page name.
+// Besides Markdown files, files of the other registered markup formats are
+// accepted.
+func wikiFilenameToName(filename string) (string, error) {
+	if isWikiMarkupFile(filename) {
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".md"
+	}
+	return models.WikiFilenameToName(filename)
+}
+
+// findEntryForPage finds the tree entry of a wiki page. The Markdown file of
+// the page is looked for first, then a file of another markup format.
+func findEntryForPage(commit *git.Commit, wikiName string) (*git.TreeEntry, error) {
+	entries, err := commit.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	filename := models.WikiNameToFilename(wikiName)
+	basename := strings.TrimSuffix(filename, ".md")
+	var markupEntry *git.TreeEntry
+	for _, entry := range entries {
+		if entry.Type != git.ObjectBlob {
+			continue
+		}
+		if entry.Name() == filename {
+			return entry, nil
+		}
+		if markupEntry == nil && isWikiMarkupFile(entry.Name()) &&
+			strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())) == basename {
+			markupEntry = entry
+		}
+	}
+	return markupEntry, nil
+}
+
 func findWikiRepoCommit(ctx *context.Context) (*git.Repository, *git.Commit, error) {
 	wikiRepo, err := git.OpenRepository(ctx.Repo.Repository.WikiPath())
 	if err != nil {
@@ -129,7 +171,7 @@ func renderWikiPage(ctx *context.Context, isViewPage bool) (*git.Repository, *gi
 			if entry.Type != git.ObjectBlob {
 				continue
 			}
-			wikiName, err := models.WikiFilenameToName(entry.Name())
+			wikiName, err := wikiFilenameToName(entry.Name())
 			if err != nil {
 				if models.IsErrWikiInvalidFileName(err) {
 					continue
@@ -158,10 +200,9 @@ func renderWikiPage(ctx *context.Context, isViewPage bool) (*git.Repository, *gi
 	ctx.Data["title"] = pageName
 	ctx.Data["RequireHighlightJS"] = true
 
-	pageFilename := models.WikiNameToFilename(pageName)
 	var entry *git.TreeEntry
-	if entry, err = findEntryForFile(commit, pageFilename); err != nil {
-		ctx.ServerError("findEntryForFile", err)
+	if entry, err = findEntryForPage(commit, pageName); err != nil {
+		ctx.ServerError("findEntryForPage", err)
 		return nil, nil
 	} else if entry == nil {
 		ctx.Redirect(ctx.Repo.RepoLink + "/wiki/_pages")
@@ -184,7 +225,11 @@ func renderWikiPage(ctx *context.Context, isViewPage bool) (*git.Repository, *gi
 		}
 
 		metas := ctx.Repo.Repository.ComposeMetas()
-		ctx.Data["content"] = markdown.RenderWiki(data, ctx.Repo.RepoLink, metas)
+		if isWikiMarkupFile(entry.Name()) {
+			ctx.Data["content"] = markup.RenderWiki(entry.Name(), data, ctx.Repo.RepoLink, metas)
+		} else {
+			ctx.Data["content"] = markdown.RenderWiki(data, ctx.Repo.RepoLink, metas)
+		}
 		ctx.Data["sidebarPresent"] = sidebarPresent
 		ctx.Data["sidebarContent"] = markdown.RenderWiki(sidebarContent, ctx.Repo.RepoLink, metas)
 		ctx.Data["footerPresent"] = footerPresent
@@ -222,7 +267,8 @@ func Wiki(ctx *context.Context) {
 	}
 
 	wikiPath := entry.Name()
-	if markup.Type(wikiPath) != markdown.MarkupName {
+	ctx.Data["IsEditablePage"] = !isWikiMarkupFile(wikiPath)
+	if markup.Type(wikiPath) == "" {
 		ext := strings.ToUpper(filepath.Ext(wikiPath))
 		ctx.Data["FormatWarning"] = fmt.Sprintf("%s rendering is not supported at the moment. Rendered as Markdown.", ext)
 	}
@@ -268,7 +314,7 @@ func WikiPages(ctx *context.Context) {
 			ctx.ServerError("GetCommit", err)
 			return
 		}
-		wikiName, err := models.WikiFilenameToName(entry.Name())
+		wikiName, err := wikiFilenameToName(entry.Name())
 		if err != nil {
 			if models.IsErrWikiInvalidFileName(err) {
 				continue
@@ -369,10 +415,15 @@ func EditWiki(ctx *context.Context) {
 		return
 	}
 
-	renderWikiPage(ctx, false)
+	_, entry := renderWikiPage(ctx, false)
 	if ctx.Written() {
 		return
 	}
+	// Only Markdown pages can be edited online.
+	if entry != nil && isWikiMarkupFile(entry.Name()) {
+		ctx.NotFound("EditWiki", nil)
+		return
+	}
 
 	ctx.HTML(200, tplWikiNew)
 }
@@ -40,7 +40,6 @@ import (
 	"github.com/go-macaron/i18n"
 	"github.com/go-macaron/session"
 	"github.com/go-macaron/toolbox"
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tstranex/u2f"
 	"gopkg.in/macaron.v1"
 )
@@ -242,6 +241,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Combo("").Get(userSetting.Account).Post(bindIgnErr(auth.ChangePasswordForm{}), userSetting.AccountPost)
 			m.Post("/email", bindIgnErr(auth.AddEmailForm{}), userSetting.EmailPost)
 			m.Post("/email/delete", userSetting.DeleteEmail)
+			m.Post("/notifications", bindIgnErr(auth.EmailNotificationsForm{}), userSetting.EmailNotificationsPost)
 			m.Post("/delete", userSetting.DeleteAccount)
 		})
 		m.Group("/security", func() {
@@ -398,6 +398,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 	reqRepoReleaseWriter := context.RequireRepoWriter(models.UnitTypeReleases)
 	reqRepoReleaseReader := context.RequireRepoReader(models.UnitTypeReleases)
 	reqRepoWikiWriter := context.RequireRepoWriter(models.UnitTypeWiki)
+	reqRepoIssueWriter := context.RequireRepoWriter(models.UnitTypeIssues)
 	reqRepoIssueReader := context.RequireRepoReader(models.UnitTypeIssues)
 	reqRepoPullsWriter := context.RequireRepoWriter(models.UnitTypePullRequests)
 	reqRepoPullsReader := context.RequireRepoReader(models.UnitTypePullRequests)
@@ -489,6 +490,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/*").Get(repo.SettingsProtectedBranch).
 					Post(bindIgnErr(auth.ProtectBranchForm{}), repo.SettingsProtectedBranchPost)
 			}, repo.MustBeNotBare)
+			m.Group("/tags", func() {
+				m.Combo("").Get(repo.ProtectedTags).
+					Post(bindIgnErr(auth.ProtectTagForm{}), repo.NewProtectedTagPost)
+				m.Post("/delete", repo.DeleteProtectedTagPost)
+				m.Combo("/:id").Get(repo.EditProtectedTag).
+					Post(bindIgnErr(auth.ProtectTagForm{}), repo.EditProtectedTagPost)
+			}, repo.MustBeNotBare)
 
 			m.Group("/hooks", func() {
 				m.Get("", repo.Webhooks)
@@ -539,6 +547,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/title", repo.UpdateIssueTitle)
 				m.Post("/content", repo.UpdateIssueContent)
 				m.Post("/watch", repo.IssueWatch)
+				m.Post("/move", reqRepoIssueWriter, bindIgnErr(auth.MoveIssueForm{}), repo.MoveIssue)
+				m.Post("/points", repo.UpdateIssuePoints)
 				m.Group("/dependency", func() {
 					m.Post("/add", repo.AddDependency)
 					m.Post("/delete", repo.RemoveDependency)
@@ -691,6 +701,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", reqRepoPullsWriter, bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cleanup", context.RepoRef(), repo.CleanUpPullRequest)
+			m.Post("/ready", reqSignIn, repo.MarkPullRequestReady)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
 				m.Group("/reviews", func() {
@@ -813,8 +824,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 	// prometheus metrics endpoint
 	if setting.Metrics.Enabled {
-		c := metrics.NewCollector()
-		prometheus.MustRegister(c)
+		if err := metrics.Register(); err != nil {
+			log.Fatal(4, "Failed to register metrics collector: %v", err)
+		}
 
 		m.Get("/metrics", routers.Metrics)
 	}
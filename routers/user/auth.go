@@ -507,6 +507,15 @@ func SignInOAuth(ctx *context.Context) {
 		return
 	}
 
+	if loginSource == nil {
+		ctx.NotFound("SignIn", nil)
+		return
+	}
+
+	if !registerOAuth2Provider(ctx, loginSource) {
+		return
+	}
+
 	// try to do a direct callback flow, so we don't authenticate the user again but use the valid accesstoken to get the user
 	user, gothUser, err := oAuth2UserLoginCallback(loginSource, ctx.Req.Request, ctx.Resp)
 	if err == nil && user != nil {
@@ -538,11 +547,28 @@ func SignInOAuthCallback(ctx *context.Context) {
 		return
 	}
 
+	if !registerOAuth2Provider(ctx, loginSource) {
+		return
+	}
+
 	u, gothUser, err := oAuth2UserLoginCallback(loginSource, ctx.Req.Request, ctx.Resp)
 
 	handleOAuth2SignIn(u, gothUser, ctx, err)
 }
 
+// registerOAuth2Provider makes sure the provider of the login source is
+// registered, which fails while the discovery document of an OpenID Connect
+// provider is unreachable. Returns false when the user was redirected.
+func registerOAuth2Provider(ctx *context.Context, loginSource *models.LoginSource) bool {
+	if err := models.RegisterOAuth2ProviderIfMissing(loginSource); err != nil {
+		log.Error(4, "RegisterOAuth2ProviderIfMissing: %v", err)
+		ctx.Flash.Error(ctx.Tr("auth.oauth_provider_unavailable", loginSource.Name))
+		ctx.Redirect(setting.AppSubURL + "/user/login")
+		return false
+	}
+	return true
+}
+
 func handleOAuth2SignIn(u *models.User, gothUser goth.User, ctx *context.Context, err error) {
 	if err != nil {
 		ctx.ServerError("UserSignIn", err)
@@ -621,22 +647,35 @@ func oAuth2UserLoginCallback(loginSource *models.LoginSource, request *http.Requ
 		return nil, goth.User{}, err
 	}
 
-	if hasUser {
-		return user, goth.User{}, nil
-	}
-
-	// search in external linked users
+	// the tokens are stored on the link of the user to the login source
 	externalLoginUser := &models.ExternalLoginUser{
 		ExternalID:    gothUser.UserID,
 		LoginSourceID: loginSource.ID,
 	}
+
+	if hasUser {
+		if err = models.UpdateExternalLoginUserTokens(externalLoginUser, gothUser); err != nil {
+			return nil, goth.User{}, err
+		}
+		syncOAuth2GroupTeams(loginSource, user, gothUser)
+		return user, gothUser, nil
+	}
+
+	// search in external linked users
 	hasUser, err = models.GetExternalLogin(externalLoginUser)
 	if err != nil {
 		return nil, goth.User{}, err
 	}
 	if hasUser {
+		if err = models.UpdateExternalLoginUserTokens(externalLoginUser, gothUser); err != nil {
+			return nil, goth.User{}, err
+		}
 		user, err = models.GetUserByID(externalLoginUser.UserID)
-		return user, goth.User{}, err
+		if err != nil {
+			return nil, goth.User{}, err
+		}
+		syncOAuth2GroupTeams(loginSource, user, gothUser)
+		return user, gothUser, nil
 	}
 
 	// no user found to login
@@ -644,6 +683,14 @@ func oAuth2UserLoginCallback(loginSource *models.LoginSource, request *http.Requ
 
 }
 
+// syncOAuth2GroupTeams updates the team memberships mapped to the groups of
+// the user, a failure must not prevent the user from signing in.
+func syncOAuth2GroupTeams(loginSource *models.LoginSource, u *models.User, gothUser goth.User) {
+	if err := models.SyncOAuth2GroupTeams(loginSource, u, gothUser.RawData); err != nil {
+		log.Error(4, "SyncOAuth2GroupTeams: %v", err)
+	}
+}
+
 // LinkAccount shows the page where the user can decide to login or create a new account
 func LinkAccount(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("link_account")
@@ -883,13 +930,10 @@ func LinkAccountPostRegister(ctx *context.Context, cpt *captcha.Captcha, form au
 
 // SignOut sign out from login status
 func SignOut(ctx *context.Context) {
-	ctx.Session.Delete("uid")
-	ctx.Session.Delete("uname")
-	ctx.Session.Delete("socialId")
-	ctx.Session.Delete("socialName")
-	ctx.Session.Delete("socialEmail")
-	ctx.SetCookie(setting.CookieUserName, "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
-	ctx.SetCookie(setting.CookieRememberName, "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
+	if err := models.DeleteUserSessionBySessionID(ctx.Session.ID()); err != nil {
+		log.Error(4, "DeleteUserSessionBySessionID: %v", err)
+	}
+	auth.SignOutSession(ctx.Context, ctx.Session)
 	ctx.SetCookie(setting.CSRFCookieName, "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
 	ctx.SetCookie("lang", "", -1, setting.AppSubURL, "", setting.SessionConfig.Secure, true) // Setting the lang cookie will trigger the middleware to reset the language ot previous state.
 	ctx.Redirect(setting.AppSubURL + "/")
@@ -1208,7 +1252,7 @@ func ResetPasswdPost(ctx *context.Context) {
 		}
 		u.HashPassword(passwd)
 		u.MustChangePassword = false
-		if err := models.UpdateUserCols(u, "must_change_password", "passwd", "rands", "salt"); err != nil {
+		if err := models.UpdateUserCols(u, "must_change_password", "passwd", "passwd_hash_algo", "rands", "salt"); err != nil {
 			ctx.ServerError("UpdateUser", err)
 			return
 		}
@@ -1272,7 +1316,7 @@ func MustChangePasswordPost(ctx *context.Context, cpt *captcha.Captcha, form aut
 	u.HashPassword(form.Password)
 	u.MustChangePassword = false
 
-	if err := models.UpdateUserCols(u, "must_change_password", "passwd", "salt"); err != nil {
+	if err := models.UpdateUserCols(u, "must_change_password", "passwd", "passwd_hash_algo", "salt"); err != nil {
 		ctx.ServerError("UpdateUser", err)
 		return
 	}
@@ -54,7 +54,7 @@ func AccountPost(ctx *context.Context, form auth.ChangePasswordForm) {
 			return
 		}
 		ctx.User.HashPassword(form.Password)
-		if err := models.UpdateUserCols(ctx.User, "salt", "passwd"); err != nil {
+		if err := models.UpdateUserCols(ctx.User, "salt", "passwd", "passwd_hash_algo"); err != nil {
 			ctx.ServerError("UpdateUser", err)
 			return
 		}
@@ -121,6 +121,36 @@ func EmailPost(ctx *context.Context, form auth.AddEmailForm) {
 	ctx.Redirect(setting.AppSubURL + "/user/settings/account")
 }
 
+// EmailNotificationsPost response for changing the kinds of events the user is notified about by email
+func EmailNotificationsPost(ctx *context.Context, form auth.EmailNotificationsForm) {
+	var events models.EmailNotificationEvents
+	if form.Watching {
+		events |= models.EmailNotifyWatching
+	}
+	if form.OwnThread {
+		events |= models.EmailNotifyOwnThread
+	}
+	if form.Mention {
+		events |= models.EmailNotifyMention
+	}
+	if form.Assigned {
+		events |= models.EmailNotifyAssigned
+	}
+	if form.ReviewRequested {
+		events |= models.EmailNotifyReviewRequested
+	}
+
+	ctx.User.EmailNotifications = events
+	if err := models.UpdateUserCols(ctx.User, "email_notifications"); err != nil {
+		ctx.ServerError("UpdateUserCols", err)
+		return
+	}
+	log.Trace("Email notifications updated: %s", ctx.User.Name)
+
+	ctx.Flash.Success(ctx.Tr("settings.update_email_notifications_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/account")
+}
+
 // DeleteEmail response for delete user's email
 func DeleteEmail(ctx *context.Context) {
 	if err := models.DeleteEmailAddress(&models.EmailAddress{ID: ctx.QueryInt64("id"), UID: ctx.User.ID}); err != nil {
@@ -175,4 +205,10 @@ func loadAccountData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Emails"] = emails
+
+	ctx.Data["EmailNotifyWatching"] = ctx.User.EmailNotifications.Has(models.EmailNotifyWatching)
+	ctx.Data["EmailNotifyOwnThread"] = ctx.User.EmailNotifications.Has(models.EmailNotifyOwnThread)
+	ctx.Data["EmailNotifyMention"] = ctx.User.EmailNotifications.Has(models.EmailNotifyMention)
+	ctx.Data["EmailNotifyAssigned"] = ctx.User.EmailNotifications.Has(models.EmailNotifyAssigned)
+	ctx.Data["EmailNotifyReviewRequested"] = ctx.User.EmailNotifications.Has(models.EmailNotifyReviewRequested)
 }
@@ -66,3 +66,18 @@ func TestChangePassword(t *testing.T) {
 		assert.EqualValues(t, http.StatusFound, ctx.Resp.Status())
 	}
 }
+
+func TestEmailNotificationsPost(t *testing.T) {
+	models.PrepareTestEnv(t)
+	ctx := test.MockContext(t, "user/settings/account/notifications")
+	test.LoadUser(t, ctx, 2)
+
+	EmailNotificationsPost(ctx, auth.EmailNotificationsForm{
+		Mention:  true,
+		Assigned: true,
+	})
+
+	assert.EqualValues(t, http.StatusFound, ctx.Resp.Status())
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.Equal(t, models.EmailNotifyMention|models.EmailNotifyAssigned, user.EmailNotifications)
+}
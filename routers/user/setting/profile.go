@@ -81,6 +81,7 @@ func ProfilePost(ctx *context.Context, form auth.UpdateProfileForm) {
 		return
 	}
 
+	displayName := ctx.User.DisplayName()
 	handleUsernameChange(ctx, form.Name)
 	if ctx.Written() {
 		return
@@ -101,6 +102,11 @@ func ProfilePost(ctx *context.Context, form auth.UpdateProfileForm) {
 		ctx.ServerError("UpdateUser", err)
 		return
 	}
+	if ctx.User.DisplayName() != displayName {
+		if err := ctx.User.RefreshGeneratedAvatar(); err != nil {
+			log.Error(4, "RefreshGeneratedAvatar[%d]: %v", ctx.User.ID, err)
+		}
+	}
 
 	// Update the language to the one we just set
 	ctx.SetCookie("lang", ctx.User.Language, nil, setting.AppSubURL, "", setting.SessionConfig.Secure, true)
@@ -0,0 +1,245 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// GithubAPIURL is the URL of the API of github.com.
+const GithubAPIURL = "https://api.github.com"
+
+// maxRateLimitWaits is the number of times a request waits for the rate
+// limit to be reset before giving up.
+const maxRateLimitWaits = 5
+
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// GithubUser represents a GitHub user.
+type GithubUser struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+// GithubLabel represents a label of a GitHub repository.
+type GithubLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// GithubMilestone represents a milestone of a GitHub repository.
+type GithubMilestone struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+}
+
+// GithubIssue represents an issue of a GitHub repository. The GitHub API lists
+// the pull requests as issues too, they have the PullRequest field set.
+type GithubIssue struct {
+	Number      int64            `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	State       string           `json:"state"`
+	Locked      bool             `json:"locked"`
+	User        *GithubUser      `json:"user"`
+	Labels      []*GithubLabel   `json:"labels"`
+	Milestone   *GithubMilestone `json:"milestone"`
+	Comments    int              `json:"comments"`
+	HTMLURL     string           `json:"html_url"`
+	CreatedAt   time.Time        `json:"created_at"`
+	PullRequest *struct{}        `json:"pull_request"`
+}
+
+// GithubComment represents a comment of a GitHub issue.
+type GithubComment struct {
+	Body      string      `json:"body"`
+	User      *GithubUser `json:"user"`
+	HTMLURL   string      `json:"html_url"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ParseGithubURL returns the owner and the name of the github.com repository
+// of a clone URL.
+func ParseGithubURL(cloneAddr string) (owner, repo string, err error) {
+	u, err := url.Parse(strings.TrimSpace(cloneAddr))
+	if err != nil {
+		return "", "", err
+	}
+	if !strings.EqualFold(u.Host, "github.com") && !strings.EqualFold(u.Host, "www.github.com") {
+		return "", "", fmt.Errorf("not a GitHub repository: %s", u.Host)
+	}
+	fields := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(fields) != 2 || len(fields[0]) == 0 || len(fields[1]) == 0 {
+		return "", "", fmt.Errorf("not a GitHub repository path: %s", u.Path)
+	}
+	return fields[0], strings.TrimSuffix(fields[1], ".git"), nil
+}
+
+// GithubClient is a client of the GitHub REST API, which pauses while the
+// rate limit of the API is exceeded.
+type GithubClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+
+	// now and sleep are replaced by tests to control the rate limit waits.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewGithubClient returns a client of the GitHub API at baseURL, authenticated
+// with token unless it is empty.
+func NewGithubClient(baseURL, token string) *GithubClient {
+	return &GithubClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: time.Minute},
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying a request rejected
+// by the rate limit, or 0 if the request was not rejected by the rate limit.
+func (c *GithubClient) rateLimitWait(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	// secondary rate limits tell how long to wait
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Minute
+	}
+	if wait := time.Unix(reset, 0).Sub(c.now()) + time.Second; wait > time.Second {
+		return wait
+	}
+	return time.Second
+}
+
+// get decodes the JSON response of a GET request to the given URL into v and
+// returns the URL of the next page, if any.
+func (c *GithubClient) get(rawurl string, v interface{}) (next string, err error) {
+	for waits := 0; ; waits++ {
+		req, err := http.NewRequest("GET", rawurl, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		if len(c.token) > 0 {
+			req.Header.Set("Authorization", "token "+c.token)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		if wait := c.rateLimitWait(resp); wait > 0 {
+			resp.Body.Close()
+			if waits >= maxRateLimitWaits {
+				return "", fmt.Errorf("GitHub API rate limit still exceeded after %d waits", waits)
+			}
+			log.Info("GitHub API rate limit exceeded, resuming in %v", wait)
+			c.sleep(wait)
+			continue
+		}
+
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return "", fmt.Errorf("GET %s: %s: %s", req.URL.Path, resp.Status, body)
+		}
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return "", fmt.Errorf("Decode: %v", err)
+		}
+		if m := linkNextPattern.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+		return next, nil
+	}
+}
+
+func (c *GithubClient) repoURL(owner, repo, path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/%s", c.baseURL, url.PathEscape(owner), url.PathEscape(repo), path)
+}
+
+// ListLabels returns all the labels of a repository.
+func (c *GithubClient) ListLabels(owner, repo string) ([]*GithubLabel, error) {
+	labels := make([]*GithubLabel, 0, 10)
+	for next := c.repoURL(owner, repo, "labels?per_page=100"); len(next) > 0; {
+		page := make([]*GithubLabel, 0, 100)
+		var err error
+		if next, err = c.get(next, &page); err != nil {
+			return nil, err
+		}
+		labels = append(labels, page...)
+	}
+	return labels, nil
+}
+
+// ListMilestones returns all the open and closed milestones of a repository.
+func (c *GithubClient) ListMilestones(owner, repo string) ([]*GithubMilestone, error) {
+	milestones := make([]*GithubMilestone, 0, 10)
+	for next := c.repoURL(owner, repo, "milestones?state=all&per_page=100"); len(next) > 0; {
+		page := make([]*GithubMilestone, 0, 100)
+		var err error
+		if next, err = c.get(next, &page); err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, page...)
+	}
+	return milestones, nil
+}
+
+// EachIssue calls fn for each open and closed issue of a repository, in
+// ascending order of their numbers, fetching the issues page by page.
+func (c *GithubClient) EachIssue(owner, repo string, fn func(*GithubIssue) error) error {
+	for next := c.repoURL(owner, repo, "issues?state=all&sort=created&direction=asc&per_page=100"); len(next) > 0; {
+		page := make([]*GithubIssue, 0, 100)
+		var err error
+		if next, err = c.get(next, &page); err != nil {
+			return err
+		}
+		for _, issue := range page {
+			if err = fn(issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ListComments returns all the comments of an issue, oldest first.
+func (c *GithubClient) ListComments(owner, repo string, number int64) ([]*GithubComment, error) {
+	comments := make([]*GithubComment, 0, 10)
+	for next := c.repoURL(owner, repo, fmt.Sprintf("issues/%d/comments?per_page=100", number)); len(next) > 0; {
+		page := make([]*GithubComment, 0, 100)
+		var err error
+		if next, err = c.get(next, &page); err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+	}
+	return comments, nil
+}
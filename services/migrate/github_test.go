@@ -0,0 +1,163 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// githubServer mocks the GitHub API of the repository octo/hello, serving
+// the fixtures of testdata/github.
+type githubServer struct {
+	*httptest.Server
+
+	lock sync.Mutex
+	// rateLimited is the number of requests of the second page of issues
+	// rejected by the rate limit
+	rateLimited int
+	// failComments makes the requests of the comments of the issue fail
+	failComments int64
+	requests     []string
+}
+
+// rateLimitReset is the reset time of the rate limit of the mocked API.
+var rateLimitReset = time.Date(2019, 2, 1, 12, 0, 0, 0, time.UTC)
+
+func newGithubServer(t *testing.T) *githubServer {
+	s := &githubServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.requests = append(s.requests, r.URL.RequestURI())
+
+		var fixture string
+		switch r.URL.Path {
+		case "/repos/octo/hello/labels":
+			fixture = "labels.json"
+		case "/repos/octo/hello/milestones":
+			fixture = "milestones.json"
+		case "/repos/octo/hello/issues":
+			if r.URL.Query().Get("page") != "2" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/octo/hello/issues?page=2>; rel="next", <%[1]s/repos/octo/hello/issues?page=2>; rel="last"`, s.URL))
+				fixture = "issues_1.json"
+				break
+			}
+			if s.rateLimited > 0 {
+				s.rateLimited--
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", fmt.Sprint(rateLimitReset.Unix()))
+				http.Error(w, `{"message": "API rate limit exceeded"}`, http.StatusForbidden)
+				return
+			}
+			fixture = "issues_2.json"
+		case "/repos/octo/hello/issues/1/comments":
+			fixture = "comments_1.json"
+		case "/repos/octo/hello/issues/3/comments":
+			fixture = "comments_3.json"
+		}
+		if len(fixture) == 0 || r.URL.Path == fmt.Sprintf("/repos/octo/hello/issues/%d/comments", s.failComments) {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "github", fixture))
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	return s
+}
+
+// newClient returns a client of the mocked API which records its waits
+// instead of sleeping.
+func (s *githubServer) newClient(waits *[]time.Duration) *GithubClient {
+	client := NewGithubClient(s.URL, "token")
+	client.now = func() time.Time {
+		return rateLimitReset.Add(-time.Minute)
+	}
+	client.sleep = func(d time.Duration) {
+		*waits = append(*waits, d)
+	}
+	return client
+}
+
+func TestParseGithubURL(t *testing.T) {
+	owner, repo, err := ParseGithubURL("https://github.com/octo/hello.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "octo", owner)
+	assert.Equal(t, "hello", repo)
+
+	owner, repo, err = ParseGithubURL(" https://github.com/octo/hello/ ")
+	assert.NoError(t, err)
+	assert.Equal(t, "octo", owner)
+	assert.Equal(t, "hello", repo)
+
+	_, _, err = ParseGithubURL("https://gitlab.com/octo/hello.git")
+	assert.Error(t, err)
+	_, _, err = ParseGithubURL("https://github.com/octo")
+	assert.Error(t, err)
+}
+
+func TestGithubClient_EachIssue(t *testing.T) {
+	s := newGithubServer(t)
+	defer s.Close()
+	s.rateLimited = 1
+
+	var waits []time.Duration
+	client := s.newClient(&waits)
+	numbers := make([]int64, 0, 3)
+	assert.NoError(t, client.EachIssue("octo", "hello", func(issue *GithubIssue) error {
+		numbers = append(numbers, issue.Number)
+		return nil
+	}))
+
+	// both pages are fetched, the second one once the rate limit is reset
+	assert.Equal(t, []int64{1, 2, 3}, numbers)
+	assert.Equal(t, []time.Duration{time.Minute + time.Second}, waits)
+	assert.Equal(t, []string{
+		"/repos/octo/hello/issues?state=all&sort=created&direction=asc&per_page=100",
+		"/repos/octo/hello/issues?page=2",
+		"/repos/octo/hello/issues?page=2",
+	}, s.requests)
+}
+
+func TestGithubClient_RateLimitExceeded(t *testing.T) {
+	s := newGithubServer(t)
+	defer s.Close()
+	s.rateLimited = maxRateLimitWaits + 1
+
+	var waits []time.Duration
+	client := s.newClient(&waits)
+	err := client.EachIssue("octo", "hello", func(issue *GithubIssue) error {
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Len(t, waits, maxRateLimitWaits)
+}
+
+func TestGithubClient_ListComments(t *testing.T) {
+	s := newGithubServer(t)
+	defer s.Close()
+
+	client := NewGithubClient(s.URL, "")
+	comments, err := client.ListComments("octo", "hello", 1)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "Same here.", comments[0].Body)
+		assert.Equal(t, "hubot", comments[0].User.Login)
+	}
+
+	_, err = client.ListComments("octo", "hello", 2)
+	assert.Error(t, err)
+}
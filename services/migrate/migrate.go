@@ -0,0 +1,267 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/Unknwon/com"
+)
+
+// runningMigrations prevents an issue migration from being run twice at once.
+var runningMigrations = sync.NewStatusTable()
+
+// StartGithubIssueMigration records the import of the labels, milestones,
+// issues and comments of the github.com repository at cloneAddr into repo,
+// and runs it in the background.
+func StartGithubIssueMigration(doer *models.User, repo *models.Repository, cloneAddr, token string) error {
+	owner, name, err := ParseGithubURL(cloneAddr)
+	if err != nil {
+		return err
+	}
+
+	m := &models.IssueMigration{
+		RepoID:      repo.ID,
+		Repo:        repo,
+		DoerID:      doer.ID,
+		Doer:        doer,
+		APIURL:      GithubAPIURL,
+		RemoteOwner: owner,
+		RemoteRepo:  name,
+		Status:      models.IssueMigrationRunning,
+	}
+	if err = m.SetToken(token); err != nil {
+		return fmt.Errorf("SetToken: %v", err)
+	}
+	if err = models.NewIssueMigration(m); err != nil {
+		return fmt.Errorf("NewIssueMigration: %v", err)
+	}
+	go runIssueMigration(m)
+	return nil
+}
+
+// ResumeIssueMigrations resumes in the background the issue migrations
+// interrupted by a shutdown.
+func ResumeIssueMigrations() {
+	migrations, err := models.GetRunningIssueMigrations()
+	if err != nil {
+		log.Error(4, "GetRunningIssueMigrations: %v", err)
+		return
+	}
+	for _, m := range migrations {
+		go runIssueMigration(m)
+	}
+}
+
+// RetryIssueMigration resumes in the background the failed issue migration
+// of the repository, skipping the issues it already imported.
+func RetryIssueMigration(repo *models.Repository) error {
+	m, err := models.RetryIssueMigration(repo.ID)
+	if err != nil {
+		return err
+	}
+	go runIssueMigration(m)
+	return nil
+}
+
+func runIssueMigration(m *models.IssueMigration) {
+	if !runningMigrations.StartIfNotRunning(com.ToStr(m.ID)) {
+		return
+	}
+	defer runningMigrations.Stop(com.ToStr(m.ID))
+
+	// the token is kept when the migration fails so that it can be retried
+	if err := MigrateIssues(m); err != nil {
+		log.Error(4, "MigrateIssues [repo_id: %d]: %v", m.RepoID, err)
+		m.Status = models.IssueMigrationFailed
+		m.Error = err.Error()
+	} else {
+		log.Trace("Issues migrated [repo_id: %d]: %s/%s", m.RepoID, m.RemoteOwner, m.RemoteRepo)
+		m.Status = models.IssueMigrationFinished
+		// the token is not needed anymore
+		m.Token = ""
+	}
+	if err := models.UpdateIssueMigrationCols(m, "status", "error", "token"); err != nil {
+		log.Error(4, "UpdateIssueMigrationCols [repo_id: %d]: %v", m.RepoID, err)
+	}
+}
+
+// MigrateIssues imports the labels, milestones, issues and comments of the
+// GitHub repository of the migration. The issues imported by a previous run
+// are skipped, labels and milestones are matched by name.
+func MigrateIssues(m *models.IssueMigration) error {
+	token, err := m.GetToken()
+	if err != nil {
+		return fmt.Errorf("GetToken: %v", err)
+	}
+	return migrateIssues(NewGithubClient(m.APIURL, token), m)
+}
+
+func migrateIssues(client *GithubClient, m *models.IssueMigration) error {
+	if err := m.LoadAttributes(); err != nil {
+		return err
+	}
+
+	labelIDs, err := migrateLabels(client, m)
+	if err != nil {
+		return err
+	}
+	milestoneIDs, err := migrateMilestones(client, m)
+	if err != nil {
+		return err
+	}
+
+	return client.EachIssue(m.RemoteOwner, m.RemoteRepo, func(issue *GithubIssue) error {
+		// pull requests are not migrated
+		if issue.PullRequest != nil || issue.Number <= m.LastIssueIndex {
+			return nil
+		}
+
+		opts := models.MigratedIssueOptions{
+			Index:    issue.Number,
+			Title:    issue.Title,
+			Content:  issueContent(issue),
+			LabelIDs: make([]int64, 0, len(issue.Labels)),
+			IsClosed: issue.State == "closed",
+		}
+		for _, label := range issue.Labels {
+			if id, ok := labelIDs[label.Name]; ok {
+				opts.LabelIDs = append(opts.LabelIDs, id)
+			}
+		}
+		if issue.Milestone != nil {
+			opts.MilestoneID = milestoneIDs[issue.Milestone.Title]
+		}
+
+		if issue.Comments > 0 {
+			comments, err := client.ListComments(m.RemoteOwner, m.RemoteRepo, issue.Number)
+			if err != nil {
+				return fmt.Errorf("ListComments [number: %d]: %v", issue.Number, err)
+			}
+			opts.Comments = make([]string, 0, len(comments))
+			for _, comment := range comments {
+				opts.Comments = append(opts.Comments, commentContent(comment))
+			}
+		}
+
+		if _, err := models.InsertMigratedIssue(m, opts); err != nil {
+			return fmt.Errorf("InsertMigratedIssue [number: %d]: %v", issue.Number, err)
+		}
+		return nil
+	})
+}
+
+// migrateLabels creates the labels missing in the repository and returns the
+// IDs of the labels by name.
+func migrateLabels(client *GithubClient, m *models.IssueMigration) (map[string]int64, error) {
+	githubLabels, err := client.ListLabels(m.RemoteOwner, m.RemoteRepo)
+	if err != nil {
+		return nil, fmt.Errorf("ListLabels: %v", err)
+	}
+	labels, err := models.GetLabelsByRepoID(m.RepoID, "")
+	if err != nil {
+		return nil, fmt.Errorf("GetLabelsByRepoID: %v", err)
+	}
+
+	ids := make(map[string]int64, len(githubLabels))
+	for _, label := range labels {
+		ids[label.Name] = label.ID
+	}
+	for _, githubLabel := range githubLabels {
+		if _, ok := ids[githubLabel.Name]; ok {
+			continue
+		}
+		label := &models.Label{
+			RepoID:      m.RepoID,
+			Name:        githubLabel.Name,
+			Description: githubLabel.Description,
+			Color:       "#" + githubLabel.Color,
+		}
+		if err = models.NewLabel(label); err != nil {
+			return nil, fmt.Errorf("NewLabel: %v", err)
+		}
+		ids[label.Name] = label.ID
+	}
+	return ids, nil
+}
+
+// migrateMilestones creates the milestones missing in the repository and
+// returns the IDs of the milestones by name.
+func migrateMilestones(client *GithubClient, m *models.IssueMigration) (map[string]int64, error) {
+	githubMilestones, err := client.ListMilestones(m.RemoteOwner, m.RemoteRepo)
+	if err != nil {
+		return nil, fmt.Errorf("ListMilestones: %v", err)
+	}
+
+	ids := make(map[string]int64, len(githubMilestones))
+	for _, isClosed := range []bool{false, true} {
+		milestones, err := models.GetMilestones(m.RepoID, 0, isClosed, "")
+		if err != nil {
+			return nil, fmt.Errorf("GetMilestones: %v", err)
+		}
+		for _, milestone := range milestones {
+			ids[milestone.Name] = milestone.ID
+		}
+	}
+	for _, githubMilestone := range githubMilestones {
+		if _, ok := ids[githubMilestone.Title]; ok {
+			continue
+		}
+		deadline, _ := time.ParseInLocation("2006-01-02", "9999-12-31", time.Local)
+		if githubMilestone.DueOn != nil {
+			deadline = *githubMilestone.DueOn
+		}
+		milestone := &models.Milestone{
+			RepoID:       m.RepoID,
+			Name:         githubMilestone.Title,
+			Content:      githubMilestone.Description,
+			DeadlineUnix: util.TimeStamp(deadline.Unix()),
+		}
+		if err = models.NewMilestone(milestone); err != nil {
+			return nil, fmt.Errorf("NewMilestone: %v", err)
+		}
+		if githubMilestone.State == "closed" {
+			if err = models.ChangeMilestoneStatus(milestone, true); err != nil {
+				return nil, fmt.Errorf("ChangeMilestoneStatus: %v", err)
+			}
+		}
+		ids[milestone.Name] = milestone.ID
+	}
+	return ids, nil
+}
+
+// userLink returns a markdown link to the profile of a GitHub user.
+func userLink(user *GithubUser) string {
+	// deleted users are shown as ghost by GitHub
+	if user == nil {
+		return "@ghost"
+	}
+	return fmt.Sprintf("[@%s](%s)", user.Login, user.HTMLURL)
+}
+
+// issueContent returns the content of an imported issue, noting its original
+// author and state as all the migrated issues are posted by the importer.
+func issueContent(issue *GithubIssue) string {
+	note := fmt.Sprintf("> Originally opened by %s on %s as %s",
+		userLink(issue.User), issue.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), issue.HTMLURL)
+	if issue.Locked {
+		note += "\n>\n> The conversation was locked on GitHub."
+	}
+	return note + "\n\n" + strings.TrimSpace(issue.Body)
+}
+
+// commentContent returns the content of an imported comment, noting its
+// original author.
+func commentContent(comment *GithubComment) string {
+	return fmt.Sprintf("> Originally posted by %s on %s\n\n%s",
+		userLink(comment.User), comment.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), strings.TrimSpace(comment.Body))
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateIssues(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	s := newGithubServer(t)
+	defer s.Close()
+	s.rateLimited = 1
+	// the import is interrupted after the first issue
+	s.failComments = 3
+
+	m := &models.IssueMigration{
+		RepoID:      1,
+		DoerID:      2,
+		APIURL:      s.URL,
+		RemoteOwner: "octo",
+		RemoteRepo:  "hello",
+		Status:      models.IssueMigrationRunning,
+	}
+	assert.NoError(t, models.NewIssueMigration(m))
+	numIssues := models.GetCount(t, &models.Issue{RepoID: 1})
+
+	var waits []time.Duration
+	assert.Error(t, migrateIssues(s.newClient(&waits), m))
+	assert.Len(t, waits, 1)
+	m = models.AssertExistsAndLoadBean(t, &models.IssueMigration{ID: m.ID}).(*models.IssueMigration)
+	assert.EqualValues(t, 1, m.LastIssueIndex)
+	assert.Equal(t, numIssues+1, models.GetCount(t, &models.Issue{RepoID: 1}))
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, Title: "Crash on startup"}).(*models.Issue)
+	assert.EqualValues(t, 2, issue.PosterID)
+	assert.False(t, issue.IsClosed)
+	assert.Equal(t, "> Originally opened by [@octocat](https://github.com/octocat) on 2019-01-02 10:30 UTC as https://github.com/octo/hello/issues/1\n\nIt crashes.", issue.Content)
+	assert.EqualValues(t, 2, issue.NumComments)
+	comments, err := models.FindComments(models.FindCommentsOptions{IssueID: issue.ID, Type: models.CommentTypeComment})
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "> Originally posted by [@hubot](https://github.com/hubot) on 2019-01-02 11:00 UTC\n\nSame here.", comments[0].Content)
+	}
+
+	label := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: 1, Name: "bug"}).(*models.Label)
+	assert.Equal(t, "#ee0701", label.Color)
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: label.ID})
+	milestone := models.AssertExistsAndLoadBean(t, &models.Milestone{RepoID: 1, Name: "v1.0"}).(*models.Milestone)
+	assert.True(t, milestone.IsClosed)
+	assert.Equal(t, milestone.ID, issue.MilestoneID)
+
+	// the resumed import skips the issues already imported
+	s.failComments = 0
+	assert.NoError(t, migrateIssues(s.newClient(&waits), m))
+	assert.EqualValues(t, 3, m.LastIssueIndex)
+	// the pull request is not imported
+	assert.Equal(t, numIssues+2, models.GetCount(t, &models.Issue{RepoID: 1}))
+	models.AssertCount(t, &models.Label{RepoID: 1, Name: "bug"}, 1)
+	models.AssertCount(t, &models.Milestone{RepoID: 1, Name: "v1.0"}, 1)
+
+	issue = models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, Title: "Spam"}).(*models.Issue)
+	assert.True(t, issue.IsClosed)
+	assert.Equal(t, "> Originally opened by @ghost on 2019-01-04 10:30 UTC as https://github.com/octo/hello/issues/3\n>\n> The conversation was locked on GitHub.\n\nBuy now!", issue.Content)
+	assert.EqualValues(t, 1, issue.NumComments)
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package archiver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Status represents the state of an archive request.
+type Status int
+
+// Possible states of an archive request.
+const (
+	StatusPending Status = iota
+	StatusReady
+	StatusFailed
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusReady:
+		return "ready"
+	case StatusFailed:
+		return "failed"
+	}
+	return "pending"
+}
+
+// archiveJob tracks the generation of a single archive file.
+type archiveJob struct {
+	path    string
+	status  Status
+	err     error
+	updated time.Time
+	done    chan struct{}
+}
+
+var (
+	lock = sync.Mutex{}
+	jobs = make(map[string]*archiveJob)
+
+	cleanupOnce sync.Once
+
+	// createArchive is replaced by tests to control archive generation.
+	createArchive = func(commit *git.Commit, target string, archiveType git.ArchiveType) error {
+		return commit.CreateArchive(target, archiveType)
+	}
+)
+
+// ArchivePath returns the cache path of the archive of given commit and type
+// within the repository.
+func ArchivePath(repoPath, commitID string, archiveType git.ArchiveType) (string, error) {
+	switch archiveType {
+	case git.ZIP:
+		return filepath.Join(repoPath, "archives", "zip", commitID+".zip"), nil
+	case git.TARGZ:
+		return filepath.Join(repoPath, "archives", "targz", commitID+".tar.gz"), nil
+	}
+	return "", fmt.Errorf("unknown archive type: %v", archiveType)
+}
+
+// Archive requests an archive of the commit, starting its generation in the
+// background if it is neither cached nor already being generated. It returns
+// the path of the archive and its current status; requests for the same
+// commit and type share one generation job.
+func Archive(repoPath string, commit *git.Commit, archiveType git.ArchiveType) (string, Status, error) {
+	cleanupOnce.Do(func() {
+		go cleanup()
+	})
+
+	archivePath, err := ArchivePath(repoPath, commit.ID.String(), archiveType)
+	if err != nil {
+		return "", StatusFailed, err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if job, ok := jobs[archivePath]; ok {
+		if job.status == StatusPending {
+			return archivePath, job.status, nil
+		}
+		// Failed archives get another chance on every new request and ready
+		// ones are checked against the disk, as they may have been purged.
+		delete(jobs, archivePath)
+	}
+
+	if info, err := os.Stat(archivePath); err == nil {
+		jobs[archivePath] = &archiveJob{
+			path:    archivePath,
+			status:  StatusReady,
+			updated: info.ModTime(),
+			done:    closedChan(),
+		}
+		return archivePath, StatusReady, nil
+	}
+
+	job := &archiveJob{
+		path:    archivePath,
+		status:  StatusPending,
+		updated: time.Now(),
+		done:    make(chan struct{}),
+	}
+	jobs[archivePath] = job
+	go job.run(commit, archiveType)
+	return archivePath, StatusPending, nil
+}
+
+// Wait waits at most timeout for the archive at archivePath to be generated,
+// and returns its status.
+func Wait(archivePath string, timeout time.Duration) (Status, error) {
+	lock.Lock()
+	job, ok := jobs[archivePath]
+	lock.Unlock()
+	if !ok {
+		return StatusFailed, fmt.Errorf("archive %s has not been requested", archivePath)
+	}
+
+	select {
+	case <-job.done:
+	case <-time.After(timeout):
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	return job.status, job.err
+}
+
+func (job *archiveJob) run(commit *git.Commit, archiveType git.ArchiveType) {
+	err := generate(commit, job.path, archiveType)
+
+	lock.Lock()
+	if err != nil {
+		log.Error(4, "CreateArchive [%s]: %v", job.path, err)
+		job.status = StatusFailed
+		job.err = err
+	} else {
+		job.status = StatusReady
+	}
+	job.updated = time.Now()
+	lock.Unlock()
+	close(job.done)
+}
+
+// generate writes the archive to a temporary file first so that an archive
+// which is still being written will never be served as ready.
+func generate(commit *git.Commit, archivePath string, archiveType git.ArchiveType) error {
+	if err := os.MkdirAll(filepath.Dir(archivePath), os.ModePerm); err != nil {
+		return fmt.Errorf("MkdirAll: %v", err)
+	}
+
+	tmpPath := archivePath + ".tmp"
+	if err := createArchive(commit, tmpPath, archiveType); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("CreateArchive: %v", err)
+	}
+	if err := os.Rename(tmpPath, archivePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("Rename: %v", err)
+	}
+	return nil
+}
+
+// cleanup periodically evicts archives older than the configured maximum age.
+func cleanup() {
+	if setting.Repository.Archive.CleanupInterval <= 0 {
+		return
+	}
+	for {
+		time.Sleep(setting.Repository.Archive.CleanupInterval)
+		evictExpired(time.Now().Add(-setting.Repository.Archive.MaxAge))
+	}
+}
+
+// removeArchive removes an archive file from the cache. This is a best-effort
+// purge, so a failed removal is only traced.
+func removeArchive(archivePath string) {
+	if err := os.Remove(archivePath); err != nil && !os.IsNotExist(err) {
+		log.Trace("Unable to delete %s, but proceeding: %v", archivePath, err)
+	}
+}
+
+// evictExpired removes every finished archive last updated before deadline,
+// along with its file. The cache directories of the repositories are swept
+// as well, for the files written before the last restart have no job.
+func evictExpired(deadline time.Time) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for archivePath, job := range jobs {
+		if job.status == StatusPending || !job.updated.Before(deadline) {
+			continue
+		}
+		if job.status == StatusReady {
+			removeArchive(archivePath)
+		}
+		delete(jobs, archivePath)
+	}
+
+	archivePaths, err := filepath.Glob(filepath.Join(setting.RepoRootPath, "*", "*.git", "archives", "*", "*"))
+	if err != nil {
+		log.Error(4, "Glob: %v", err)
+		return
+	}
+	for _, archivePath := range archivePaths {
+		// The temporary file of a pending job is still being written.
+		if job, ok := jobs[strings.TrimSuffix(archivePath, ".tmp")]; ok && job.status == StatusPending {
+			continue
+		}
+		if info, err := os.Stat(archivePath); err == nil && !info.IsDir() && info.ModTime().Before(deadline) {
+			removeArchive(archivePath)
+			delete(jobs, archivePath)
+		}
+	}
+}
+
+func closedChan() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package archiver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stubCreateArchive(release <-chan struct{}) *int32 {
+	var calls int32
+	createArchive = func(commit *git.Commit, target string, archiveType git.ArchiveType) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return ioutil.WriteFile(target, []byte(commit.ID.String()), 0644)
+	}
+	return &calls
+}
+
+func TestArchive(t *testing.T) {
+	defer func(f func(*git.Commit, string, git.ArchiveType) error) { createArchive = f }(createArchive)
+
+	repoPath, err := ioutil.TempDir("", "archiver")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+
+	release := make(chan struct{})
+	calls := stubCreateArchive(release)
+	commit := &git.Commit{ID: git.MustIDFromString("65f1bf27bc3bf70f64657658635e66094edbcb4d")}
+
+	archivePath, status, err := Archive(repoPath, commit, git.ZIP)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, status)
+
+	// Concurrent requests coalesce onto the pending job.
+	samePath, status, err := Archive(repoPath, commit, git.ZIP)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, status)
+	assert.Equal(t, archivePath, samePath)
+
+	status, err = Wait(archivePath, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, status)
+
+	close(release)
+	status, err = Wait(archivePath, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReady, status)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	// Completed archives are reused.
+	_, status, err = Archive(repoPath, commit, git.ZIP)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReady, status)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	// Another format is another job.
+	otherPath, _, err := Archive(repoPath, commit, git.TARGZ)
+	assert.NoError(t, err)
+	assert.NotEqual(t, archivePath, otherPath)
+	_, err = Wait(otherPath, time.Second)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestEvictExpired(t *testing.T) {
+	defer func(f func(*git.Commit, string, git.ArchiveType) error) { createArchive = f }(createArchive)
+	defer func(p string) { setting.RepoRootPath = p }(setting.RepoRootPath)
+
+	var err error
+	setting.RepoRootPath, err = ioutil.TempDir("", "archiver")
+	assert.NoError(t, err)
+	defer os.RemoveAll(setting.RepoRootPath)
+	repoPath := filepath.Join(setting.RepoRootPath, "user2", "repo1.git")
+
+	release := make(chan struct{})
+	close(release)
+	stubCreateArchive(release)
+	commit := &git.Commit{ID: git.MustIDFromString("2a47ca4b614a9f5a43abbd5ad851a54a616ffee6")}
+
+	archivePath, _, err := Archive(repoPath, commit, git.ZIP)
+	assert.NoError(t, err)
+	_, err = Wait(archivePath, time.Second)
+	assert.NoError(t, err)
+	assert.FileExists(t, archivePath)
+
+	evictExpired(time.Now().Add(-time.Hour))
+	assert.FileExists(t, archivePath)
+
+	evictExpired(time.Now().Add(time.Hour))
+	_, err = os.Stat(archivePath)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = Wait(archivePath, time.Millisecond)
+	assert.Error(t, err)
+
+	// Archives written before a restart have no job.
+	oldPath, err := ArchivePath(repoPath, "65f1bf27bc3bf70f64657658635e66094edbcb4d", git.TARGZ)
+	assert.NoError(t, err)
+	newPath, err := ArchivePath(repoPath, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", git.TARGZ)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(oldPath), os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(oldPath, nil, 0644))
+	assert.NoError(t, ioutil.WriteFile(newPath, nil, 0644))
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(oldPath, old, old))
+
+	evictExpired(time.Now().Add(-time.Hour))
+	_, err = os.Stat(oldPath)
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, newPath)
+}
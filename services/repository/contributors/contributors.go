@@ -0,0 +1,329 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package contributors
+
+import (
+	"bufio"
+	"container/list"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Status represents the state of the statistics of a repository.
+type Status int
+
+// Possible states of the statistics.
+const (
+	StatusPending Status = iota
+	StatusReady
+	StatusFailed
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusReady:
+		return "ready"
+	case StatusFailed:
+		return "failed"
+	}
+	return "pending"
+}
+
+// CommitStats represents the changes of a single non-merge commit.
+type CommitStats struct {
+	AuthorName  string
+	AuthorEmail string
+	When        time.Time
+	Additions   int
+	Deletions   int
+}
+
+// Contributor represents the changes of an author over a time range.
+type Contributor struct {
+	Name      string
+	Email     string
+	Commits   int
+	Additions int
+	Deletions int
+}
+
+// Stats holds the changes of the non-merge commits of the history of a
+// commit, summed up by author and by day in UTC.
+type Stats struct {
+	// authors are listed in the order of their latest commit
+	authors []*authorStats
+}
+
+type authorStats struct {
+	// the name of the latest commit of the author
+	name  string
+	email string
+	days  map[int64]*dayStats
+}
+
+type dayStats struct {
+	commits   int
+	additions int
+	deletions int
+}
+
+const day = 24 * 60 * 60
+
+// NewStats sums up the given commits, which are listed from the latest.
+func NewStats(commits []*CommitStats) *Stats {
+	stats := &Stats{authors: make([]*authorStats, 0, 10)}
+	byEmail := make(map[string]*authorStats, 10)
+	for _, commit := range commits {
+		email := strings.ToLower(commit.AuthorEmail)
+		author, ok := byEmail[email]
+		if !ok {
+			author = &authorStats{
+				name:  commit.AuthorName,
+				email: commit.AuthorEmail,
+				days:  make(map[int64]*dayStats),
+			}
+			byEmail[email] = author
+			stats.authors = append(stats.authors, author)
+		}
+
+		unix := commit.When.Unix()
+		start := unix - (unix%day+day)%day
+		changes, ok := author.days[start]
+		if !ok {
+			changes = &dayStats{}
+			author.days[start] = changes
+		}
+		changes.commits++
+		changes.additions += commit.Additions
+		changes.deletions += commit.Deletions
+	}
+	return stats
+}
+
+// Contributors sums up the changes made in the days overlapping [from, to), a
+// zero time leaving the range open on that side. The contributors are sorted
+// by number of commits, the name of an author being the one of their latest
+// commit.
+func (stats *Stats) Contributors(from, to time.Time) []*Contributor {
+	contributors := make([]*Contributor, 0, len(stats.authors))
+	for _, author := range stats.authors {
+		contributor := &Contributor{
+			Name:  author.name,
+			Email: author.email,
+		}
+		for start, changes := range author.days {
+			if (!from.IsZero() && start+day <= from.Unix()) || (!to.IsZero() && !time.Unix(start, 0).Before(to)) {
+				continue
+			}
+			contributor.Commits += changes.commits
+			contributor.Additions += changes.additions
+			contributor.Deletions += changes.deletions
+		}
+		if contributor.Commits > 0 {
+			contributors = append(contributors, contributor)
+		}
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Commits > contributors[j].Commits
+	})
+	return contributors
+}
+
+// repoStats holds the statistics of a repository. The statistics of the last
+// computed commit are kept while the ones of a newer commit are computed, so
+// that they can still be served in between.
+type repoStats struct {
+	repoID   int64
+	commitID string
+	stats    *Stats
+
+	pendingCommitID string
+	err             error
+	done            chan struct{}
+}
+
+// maxRepos is the number of repositories whose statistics are kept, the
+// least recently requested ones are dropped first.
+var maxRepos = 100
+
+var (
+	lock  = sync.Mutex{}
+	repos = make(map[int64]*list.Element)
+	// recent lists the statistics of the repositories from the most
+	// recently requested one.
+	recent = list.New()
+
+	// computeStats is replaced by tests to control the computation.
+	computeStats = getCommitStats
+)
+
+// getRepoStats returns the statistics of the repository, marking them as the
+// most recently requested. It must be called with the lock held.
+func getRepoStats(repoID int64, create bool) *repoStats {
+	if elem, ok := repos[repoID]; ok {
+		recent.MoveToFront(elem)
+		return elem.Value.(*repoStats)
+	} else if !create {
+		return nil
+	}
+
+	stats := &repoStats{repoID: repoID}
+	repos[repoID] = recent.PushFront(stats)
+	for recent.Len() > maxRepos {
+		delete(repos, recent.Remove(recent.Back()).(*repoStats).repoID)
+	}
+	return stats
+}
+
+// GetStats returns the statistics of the history of the commit in the
+// repository, starting their computation in the background if they are
+// neither computed nor being computed. While they are pending, the statistics
+// of the commit previously computed for the repository are returned with its
+// ID, if any.
+func GetStats(repoID int64, repoPath, commitID string) (*Stats, string, Status) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	stats := getRepoStats(repoID, true)
+	if stats.commitID == commitID && stats.stats != nil {
+		return stats.stats, stats.commitID, StatusReady
+	}
+	// Failed computations get another chance on every new request.
+	if stats.pendingCommitID != commitID || stats.err != nil {
+		stats.pendingCommitID = commitID
+		stats.err = nil
+		stats.done = make(chan struct{})
+		go stats.run(repoPath, commitID, stats.done)
+	}
+	if stats.stats == nil {
+		return &Stats{}, stats.commitID, StatusPending
+	}
+	return stats.stats, stats.commitID, StatusPending
+}
+
+// Wait waits at most timeout for the statistics of the commit in the repository
+// to be computed, and returns their status.
+func Wait(repoID int64, commitID string, timeout time.Duration) (Status, error) {
+	lock.Lock()
+	stats := getRepoStats(repoID, false)
+	if stats == nil || (stats.commitID != commitID && stats.pendingCommitID != commitID) {
+		lock.Unlock()
+		return StatusFailed, fmt.Errorf("statistics of %s in repository %d have not been requested", commitID, repoID)
+	}
+	done := stats.done
+	lock.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	switch {
+	case stats.commitID == commitID && stats.stats != nil:
+		return StatusReady, nil
+	case stats.pendingCommitID == commitID && stats.err != nil:
+		return StatusFailed, stats.err
+	}
+	return StatusPending, nil
+}
+
+func (stats *repoStats) run(repoPath, commitID string, done chan struct{}) {
+	commits, err := computeStats(repoPath, commitID)
+	var summed *Stats
+	if err == nil {
+		summed = NewStats(commits)
+	}
+
+	lock.Lock()
+	// a newer commit may have been requested in between
+	if stats.pendingCommitID == commitID {
+		if err != nil {
+			log.Error(4, "ContributorStats [%s: %s]: %v", repoPath, commitID, err)
+			stats.err = err
+		} else {
+			stats.commitID = commitID
+			stats.stats = summed
+		}
+	}
+	lock.Unlock()
+	close(done)
+}
+
+// getCommitStats reads the changes of the non-merge commits of the history of
+// the commit from git log.
+func getCommitStats(repoPath, commitID string) ([]*CommitStats, error) {
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer stdoutReader.Close()
+
+	go func() {
+		stderr := new(strings.Builder)
+		err := git.NewCommand("log", "--no-merges", "--numstat", "--format=commit %H%n%aN%n%aE%n%at", commitID, "--").
+			RunInDirPipeline(repoPath, stdoutWriter, stderr)
+		if err != nil {
+			stdoutWriter.CloseWithError(fmt.Errorf("%v - %s", err, stderr))
+		} else {
+			stdoutWriter.Close()
+		}
+	}()
+
+	return parseCommitStats(stdoutReader)
+}
+
+// parseCommitStats parses the output of git log --numstat with the format
+// "commit %H%n%aN%n%aE%n%at". The changes of binary files are not counted.
+func parseCommitStats(r io.Reader) ([]*CommitStats, error) {
+	commits := make([]*CommitStats, 0, 100)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "commit ") {
+			if len(commits) == 0 {
+				continue
+			}
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) != 3 {
+				continue
+			}
+			added, _ := strconv.Atoi(fields[0])
+			deleted, _ := strconv.Atoi(fields[1])
+			commits[len(commits)-1].Additions += added
+			commits[len(commits)-1].Deletions += deleted
+			continue
+		}
+
+		var header [3]string
+		for i := range header {
+			if !scanner.Scan() {
+				return nil, fmt.Errorf("unexpected end of commit %s", line)
+			}
+			header[i] = scanner.Text()
+		}
+		unix, err := strconv.ParseInt(header[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date of commit %s: %v", line, err)
+		}
+		commits = append(commits, &CommitStats{
+			AuthorName:  header[0],
+			AuthorEmail: header[1],
+			When:        time.Unix(unix, 0),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
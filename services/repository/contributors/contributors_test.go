@@ -0,0 +1,201 @@
+// Copyright 2019 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package contributors
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitStats(t *testing.T) {
+	commits, err := parseCommitStats(strings.NewReader(`commit 69554a64c1e6030f051e5c3f94bfbd773cd6a324
+User Two
+user2@example.com
+1502042309
+
+1	1	README.md
+-	-	logo.png
+
+commit 27566bd5738fc8b4e3fef3c5e72cce608537bd95
+User Two
+user21@example.com
+1502042234
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, &CommitStats{
+			AuthorName:  "User Two",
+			AuthorEmail: "user2@example.com",
+			When:        time.Unix(1502042309, 0),
+			Additions:   1,
+			Deletions:   1,
+		}, commits[0])
+		assert.Equal(t, "user21@example.com", commits[1].AuthorEmail)
+		assert.Zero(t, commits[1].Additions)
+	}
+
+	_, err = parseCommitStats(strings.NewReader("commit 69554a64c1e6030f051e5c3f94bfbd773cd6a324\nUser Two\n"))
+	assert.Error(t, err)
+	_, err = parseCommitStats(strings.NewReader("commit 69554a64c1e6030f051e5c3f94bfbd773cd6a324\nUser Two\nuser2@example.com\nyesterday\n"))
+	assert.Error(t, err)
+}
+
+func TestStats_Contributors(t *testing.T) {
+	// commits made from the 1st to the 3rd of January 1970
+	stats := NewStats([]*CommitStats{
+		{AuthorName: "New Name", AuthorEmail: "User2@example.com", When: time.Unix(2*day+100, 0), Additions: 1},
+		{AuthorName: "user21", AuthorEmail: "user21@example.com", When: time.Unix(2*day, 0), Additions: 4, Deletions: 2},
+		{AuthorName: "user21", AuthorEmail: "user21@example.com", When: time.Unix(day+200, 0), Additions: 1},
+		{AuthorName: "Between", AuthorEmail: "user2@example.com", When: time.Unix(day+100, 0), Additions: 3},
+		{AuthorName: "Old Name", AuthorEmail: "user2@example.com", When: time.Unix(11*3600, 0), Additions: 2, Deletions: 1},
+		{AuthorName: "Old Name", AuthorEmail: "user2@example.com", When: time.Unix(10*3600, 0), Deletions: 1},
+	})
+	assert.Len(t, stats.authors, 2)
+
+	assert.Equal(t, []*Contributor{
+		{Name: "New Name", Email: "User2@example.com", Commits: 4, Additions: 6, Deletions: 2},
+		{Name: "user21", Email: "user21@example.com", Commits: 2, Additions: 5, Deletions: 2},
+	}, stats.Contributors(time.Time{}, time.Time{}))
+
+	// the days overlapping the range are counted in full
+	assert.Equal(t, []*Contributor{
+		{Name: "New Name", Email: "User2@example.com", Commits: 3, Additions: 5, Deletions: 2},
+		{Name: "user21", Email: "user21@example.com", Commits: 1, Additions: 1},
+	}, stats.Contributors(time.Time{}, time.Unix(day+1, 0)))
+
+	assert.Equal(t, []*Contributor{
+		{Name: "New Name", Email: "User2@example.com", Commits: 1, Additions: 1},
+		{Name: "user21", Email: "user21@example.com", Commits: 1, Additions: 4, Deletions: 2},
+	}, stats.Contributors(time.Unix(3*day-1, 0), time.Time{}))
+
+	assert.Equal(t, []*Contributor{
+		{Name: "New Name", Email: "User2@example.com", Commits: 2, Additions: 2, Deletions: 2},
+	}, stats.Contributors(time.Unix(10*3600, 0), time.Unix(11*3600, 0)))
+
+	assert.Empty(t, stats.Contributors(time.Unix(3*day, 0), time.Time{}))
+	assert.Empty(t, (&Stats{}).Contributors(time.Time{}, time.Time{}))
+}
+
+func TestGetStats(t *testing.T) {
+	defer func() { computeStats = getCommitStats }()
+
+	release := make(chan struct{})
+	var calls int32
+	fail := true
+	computeStats = func(repoPath, commitID string) ([]*CommitStats, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		if fail {
+			return nil, errors.New("git log failed")
+		}
+		return []*CommitStats{{AuthorEmail: commitID}}, nil
+	}
+	const repoID = 1
+	contributors := func(stats *Stats) []string {
+		var emails []string
+		for _, c := range stats.Contributors(time.Time{}, time.Time{}) {
+			emails = append(emails, c.Email)
+		}
+		return emails
+	}
+
+	status, err := Wait(repoID, "first", time.Millisecond)
+	assert.Error(t, err)
+	assert.Equal(t, StatusFailed, status)
+
+	stats, commitID, status := GetStats(repoID, "repo.git", "first")
+	assert.Equal(t, StatusPending, status)
+	assert.Empty(t, commitID)
+	assert.Empty(t, contributors(stats))
+
+	// Concurrent requests wait for the same computation.
+	_, _, status = GetStats(repoID, "repo.git", "first")
+	assert.Equal(t, StatusPending, status)
+	status, err = Wait(repoID, "first", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, status)
+
+	release <- struct{}{}
+	status, err = Wait(repoID, "first", time.Second)
+	assert.EqualError(t, err, "git log failed")
+	assert.Equal(t, StatusFailed, status)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	// Failed computations are retried.
+	fail = false
+	_, _, status = GetStats(repoID, "repo.git", "first")
+	assert.Equal(t, StatusPending, status)
+	release <- struct{}{}
+	status, err = Wait(repoID, "first", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReady, status)
+
+	stats, commitID, status = GetStats(repoID, "repo.git", "first")
+	assert.Equal(t, StatusReady, status)
+	assert.Equal(t, "first", commitID)
+	assert.Equal(t, []string{"first"}, contributors(stats))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	// The statistics of the previous commit are served while the new ones are computed.
+	stats, commitID, status = GetStats(repoID, "repo.git", "second")
+	assert.Equal(t, StatusPending, status)
+	assert.Equal(t, "first", commitID)
+	assert.Equal(t, []string{"first"}, contributors(stats))
+
+	release <- struct{}{}
+	status, err = Wait(repoID, "second", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusReady, status)
+	stats, commitID, status = GetStats(repoID, "repo.git", "second")
+	assert.Equal(t, StatusReady, status)
+	assert.Equal(t, "second", commitID)
+	assert.Equal(t, []string{"second"}, contributors(stats))
+}
+
+func TestGetStats_MaxRepos(t *testing.T) {
+	defer func(max int) {
+		computeStats = getCommitStats
+		maxRepos = max
+	}(maxRepos)
+	computeStats = func(repoPath, commitID string) ([]*CommitStats, error) {
+		return []*CommitStats{{AuthorEmail: commitID}}, nil
+	}
+	maxRepos = 2
+
+	for repoID := int64(10); repoID < 13; repoID++ {
+		GetStats(repoID, "repo.git", "first")
+		_, err := Wait(repoID, "first", time.Second)
+		assert.NoError(t, err)
+		// the first repository stays the most recently requested one
+		_, _, status := GetStats(10, "repo.git", "first")
+		assert.Equal(t, StatusReady, status)
+	}
+
+	// the least recently requested repository is dropped
+	assert.Equal(t, 2, recent.Len())
+	_, err := Wait(11, "first", time.Millisecond)
+	assert.Error(t, err)
+	_, _, status := GetStats(12, "repo.git", "first")
+	assert.Equal(t, StatusReady, status)
+}
+
+func TestGetCommitStats(t *testing.T) {
+	commits, err := getCommitStats("../../../integrations/gitea-repositories-meta/user2/repo16.git", "69554a64c1e6030f051e5c3f94bfbd773cd6a324")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 3)
+
+	assert.Equal(t, []*Contributor{
+		{Name: commits[0].AuthorName, Email: "user2@example.com", Commits: 2, Additions: 2, Deletions: 1},
+		{Name: commits[1].AuthorName, Email: "user21@example.com", Commits: 1, Additions: 1, Deletions: 1},
+	}, NewStats(commits).Contributors(time.Time{}, time.Time{}))
+
+	_, err = getCommitStats("../../../integrations/gitea-repositories-meta/user2/repo16.git", "0000000000000000000000000000000000000000")
+	assert.Error(t, err)
+}